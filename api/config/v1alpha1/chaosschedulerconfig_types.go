@@ -0,0 +1,143 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cfg "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+//+kubebuilder:object:root=true
+
+// ChaosSchedulerConfig is the Schema for the configuration file of the chaos-scheduler
+type ChaosSchedulerConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ControllerManagerConfigurationSpec contains the settings of the manager: metrics, health probes, webhook and leader election
+	cfg.ControllerManagerConfigurationSpec `json:",inline"`
+
+	// WatchNamespaces restricts the scheduler to the schedules of these namespaces, defaults to the WATCH_NAMESPACE env
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+	// MaxConcurrentReconciles is the maximum number of schedules reconciled at the same time, defaults to 1
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+	// RateLimiter limits the requeues of the schedules after failures and conflicts
+	RateLimiter RateLimiterConfig `json:"rateLimiter,omitempty"`
+	// Logging contains the format and the level of the logs
+	Logging LoggingConfig `json:"logging,omitempty"`
+	// Defaults are applied to the schedules which do not set the corresponding fields
+	Defaults ScheduleDefaults `json:"defaults,omitempty"`
+	// PrometheusURL is the address of the Prometheus server used by the sloGuard of the schedules which do not define one
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+	// KillSwitch refers to the ConfigMap which pauses all the schedules
+	KillSwitch KillSwitchConfig `json:"killSwitch,omitempty"`
+	// MutexLeaseNamespace is the namespace of the Leases of the mutex groups spanning the cluster, defaults to the
+	// namespace of the scheduler
+	MutexLeaseNamespace string `json:"mutexLeaseNamespace,omitempty"`
+	// Calendar contains the settings of the endpoint serving the upcoming runs as iCalendar files
+	Calendar CalendarConfig `json:"calendar,omitempty"`
+	// ConversionWebhook serves the conversion of the ChaosSchedules between the v1alpha1 and v1beta1 versions,
+	// it needs the serving certificate of the webhook in its certDir
+	ConversionWebhook bool `json:"conversionWebhook,omitempty"`
+	// CloudEvents contains the sink receiving the CloudEvents of the decisions of the scheduler
+	CloudEvents CloudEventsConfig `json:"cloudEvents,omitempty"`
+	// AuditLogPath is the json lines file the decisions of the scheduler are appended to, "-" writes them to the
+	// standard output. No audit log is written when it is empty
+	AuditLogPath string `json:"auditLogPath,omitempty"`
+	// Tracing contains the endpoint the OpenTelemetry spans of the reconciles and of the runs are exported to
+	Tracing TracingConfig `json:"tracing,omitempty"`
+}
+
+//RateLimiterConfig defines the per-schedule exponential backoff along with the overall rate of the requeues
+type RateLimiterConfig struct {
+	//BaseDelay is the first delay of the backoff of a schedule, defaults to 5ms
+	BaseDelay *metav1.Duration `json:"baseDelay,omitempty"`
+	//MaxDelay is the maximum delay of the backoff of a schedule, defaults to 1000s
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+	//QPS is the overall rate of the requeues, defaults to 10
+	QPS int `json:"qps,omitempty"`
+	//Burst is the overall burst of the requeues, defaults to 100
+	Burst int `json:"burst,omitempty"`
+}
+
+//CalendarConfig defines the endpoint serving the upcoming runs of the schedules as iCalendar files
+type CalendarConfig struct {
+	//BindAddress is the address the calendar endpoint binds to, "0" disables it. Defaults to "127.0.0.1:8082" as the
+	//endpoint has no authentication
+	BindAddress string `json:"bindAddress,omitempty"`
+	//Days is the number of days of upcoming runs rendered in the calendars, defaults to 30
+	Days int `json:"days,omitempty"`
+	//EventDuration is the duration of the events of the runs whose experiments do not set their TOTAL_CHAOS_DURATION,
+	//defaults to 15m
+	EventDuration metav1.Duration `json:"eventDuration,omitempty"`
+}
+
+//CloudEventsConfig defines the sink of the CloudEvents and the outbox holding them until they are delivered
+type CloudEventsConfig struct {
+	//SinkURL is the address the CloudEvents are posted to, no event is emitted when it is empty
+	SinkURL string `json:"sinkURL,omitempty"`
+	//OutboxName is the name of the ConfigMap holding the undelivered events, defaults to "chaos-scheduler-outbox"
+	OutboxName string `json:"outboxName,omitempty"`
+	//OutboxNamespace is the namespace of the outbox ConfigMap, defaults to the namespace of the kill switch
+	OutboxNamespace string `json:"outboxNamespace,omitempty"`
+}
+
+//TracingConfig defines the OTLP endpoint receiving the spans of the scheduler
+type TracingConfig struct {
+	//OTLPEndpoint is the address of the OTLP/HTTP receiver, e.g. "http://otel-collector:4318". The spans are posted
+	//to its /v1/traces path, no span is recorded when it is empty
+	OTLPEndpoint string `json:"otlpEndpoint,omitempty"`
+}
+
+//LoggingConfig defines the format and the level of the logs
+type LoggingConfig struct {
+	//Format of the logs, either "json" or "console"
+	Format string `json:"format,omitempty"`
+	//Level of the logs, either "debug", "info", "error" or a positive verbosity level
+	Level string `json:"level,omitempty"`
+	//Development enables the development mode of the logger, with the stack traces of the warnings and the console
+	//format by default
+	Development bool `json:"development,omitempty"`
+}
+
+//ScheduleDefaults defines the defaults of the schedules
+type ScheduleDefaults struct {
+	//ConcurrencyPolicy is applied to the schedules which do not set it, defaults to "Forbid"
+	ConcurrencyPolicy schedulerV1.ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	//HistoryLimit is the number of finished runs retained in the status of the schedules which do not set it, defaults to 10
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+	//TimeZone is the IANA time zone in which the repeat schedules are evaluated, defaults to the local time zone
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+//KillSwitchConfig refers to the kill switch ConfigMap
+type KillSwitchConfig struct {
+	//Name of the ConfigMap, defaults to "chaos-kill-switch"
+	Name string `json:"name,omitempty"`
+	//Namespace of the ConfigMap, defaults to the watch namespace or the namespace of the scheduler
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Complete returns the settings of the manager, see config.ControllerManagerConfiguration
+func (c *ChaosSchedulerConfig) Complete() (cfg.ControllerManagerConfigurationSpec, error) {
+	return c.ControllerManagerConfigurationSpec, nil
+}
+
+func init() {
+	SchemeBuilder.Register(&ChaosSchedulerConfig{})
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -35,6 +36,208 @@ type ChaosScheduleSpec struct {
 	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
 	// EngineTemplateSpec is the spec of the engine to be created by this schedule
 	EngineTemplateSpec operatorV1.ChaosEngineSpec `json:"engineTemplateSpec,omitempty"`
+	// TargetRotation picks a different target application for every run of the schedule
+	// It is applied to the engineTemplateSpec only, the engineTemplates keep their own appinfo
+	TargetRotation *TargetRotation `json:"targetRotation,omitempty"`
+	// EngineTemplates is the ordered list of engines to be created one after the other in every run
+	// It takes precedence over the EngineTemplateSpec
+	EngineTemplates []EngineTemplate `json:"engineTemplates,omitempty"`
+	// HistoryLimit is the number of finished runs to be retained in the status
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+	// MutexGroup prevents the schedules of the same group from running chaos at the same time
+	MutexGroup *MutexGroup `json:"mutexGroup,omitempty"`
+	// DependsOn is the list of schedules whose most recent run should have succeeded before this schedule fires
+	DependsOn []ScheduleReference `json:"dependsOn,omitempty"`
+	// Preconditions are checked right before each run, the run is skipped if any of them fails
+	Preconditions *Preconditions `json:"preconditions,omitempty"`
+	// SLOGuard is a Prometheus query checked before each run and polled while the engines are active,
+	// the active engines are stopped once it is breached
+	SLOGuard *SLOGuard `json:"sloGuard,omitempty"`
+	// Hooks are the Jobs or webhooks run before and after each run of the schedule
+	Hooks *RunHooks `json:"hooks,omitempty"`
+	// Notifications are the sinks the events of the schedule are sent to, along with the events they subscribe to
+	Notifications []NotificationSink `json:"notifications,omitempty"`
+}
+
+//NotificationSink defines where the events of the schedule are sent, and which of them
+type NotificationSink struct {
+	//Name of the sink, it identifies the sink in the logs of the scheduler
+	Name string `json:"name"`
+	//Type of the sink, one of "webhook", "slack" or "cloudevents"
+	Type NotificationSinkType `json:"type"`
+	//URL the events are sent to
+	URL string `json:"url,omitempty"`
+	//URLSecretRef takes the URL from a key of a Secret in the namespace of the schedule, e.g. for the Slack webhooks
+	URLSecretRef *coreV1.SecretKeySelector `json:"urlSecretRef,omitempty"`
+	//HMACSecretRef refers to the key the webhook payloads are signed with, it is supported by the webhook type only
+	HMACSecretRef *coreV1.SecretKeySelector `json:"hmacSecretRef,omitempty"`
+	//Events are the reasons of the events sent to the sink, e.g. SuccessfulCreate or ScheduleCompleted
+	//All the events are sent when it is empty
+	Events []string `json:"events,omitempty"`
+}
+
+// NotificationSinkType
+type NotificationSinkType string
+
+const (
+	//WebhookSink posts the events as json, signed with HMAC-SHA256 in the X-Chaos-Signature-256 header
+	WebhookSink NotificationSinkType = "webhook"
+
+	//SlackSink posts the events as messages to a Slack compatible incoming webhook
+	SlackSink NotificationSinkType = "slack"
+
+	//CloudEventsSink posts the events as CloudEvents in the binary mode of the HTTP binding
+	CloudEventsSink NotificationSinkType = "cloudevents"
+)
+
+//RunHooks defines the hooks run around each run of the schedule
+type RunHooks struct {
+	//PreRun is run before the engines of the run are created, the engines are only created once it succeeded
+	PreRun *RunHook `json:"preRun,omitempty"`
+	//PostRun is run once all the engines of the run are finished, the run is finished once the hook is done
+	PostRun *RunHook `json:"postRun,omitempty"`
+}
+
+//RunHook defines either a Job or a webhook call, exactly one of them should be set
+type RunHook struct {
+	//Job is the template of the Job created for each run, the hook succeeds once the Job is complete
+	Job *batchV1.JobTemplateSpec `json:"job,omitempty"`
+	//Webhook is the http endpoint called for each run, the hook succeeds once it returns a 2xx status
+	Webhook *WebhookHook `json:"webhook,omitempty"`
+	//Timeout after which the hook fails, defaults to 10m for the Job and 10s for the webhook
+	//It is set as the activeDeadlineSeconds of the Job, unless the template sets its own
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	//FailurePolicy determines whether to "continue", "skip" or "abort" the run when the hook fails, defaults to abort
+	//The skip policy is only supported by the preRun hook
+	FailurePolicy HookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+//WebhookHook defines the http request sent for the hook
+type WebhookHook struct {
+	//URL of the webhook, the request carries the schedule and the run as a json body
+	URL string `json:"url"`
+	//Method of the request, defaults to POST
+	Method string `json:"method,omitempty"`
+	//Headers added to the request
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// HookFailurePolicy
+type HookFailurePolicy string
+
+const (
+	//ContinueOnHookFailure goes on with the run as if the hook succeeded
+	ContinueOnHookFailure HookFailurePolicy = "continue"
+
+	//SkipOnHookFailure skips the run whose preRun hook failed, the schedule waits for its next run
+	SkipOnHookFailure HookFailurePolicy = "skip"
+
+	//AbortOnHookFailure finishes the run with the Aborted verdict, without creating its engines for the preRun hook
+	AbortOnHookFailure HookFailurePolicy = "abort"
+)
+
+//SLOGuard defines the PromQL query whose result is compared with the threshold
+type SLOGuard struct {
+	//PrometheusURL is the address of the Prometheus server, defaults to the one the scheduler is started with
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+	//Query is the PromQL query, it should evaluate to a scalar or an instant vector
+	Query string `json:"query"`
+	//Comparison is the operator applied as "<result> <comparison> <threshold>", the SLO is breached when it holds
+	Comparison SLOComparison `json:"comparison"`
+	//Threshold is the value the result of the query is compared with
+	Threshold string `json:"threshold"`
+	//Interval at which the query is polled while the engines are active, defaults to 30s
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	//Timeout of the query, defaults to 10s
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+//SLOComparison is the operator used to compare the result of the query with the threshold
+type SLOComparison string
+
+const (
+	//GreaterThan breaches the SLO when the result is greater than the threshold
+	GreaterThan SLOComparison = ">"
+	//GreaterThanOrEqual breaches the SLO when the result is greater than or equal to the threshold
+	GreaterThanOrEqual SLOComparison = ">="
+	//LessThan breaches the SLO when the result is less than the threshold
+	LessThan SLOComparison = "<"
+	//LessThanOrEqual breaches the SLO when the result is less than or equal to the threshold
+	LessThanOrEqual SLOComparison = "<="
+	//Equal breaches the SLO when the result is equal to the threshold
+	Equal SLOComparison = "=="
+	//NotEqual breaches the SLO when the result is not equal to the threshold
+	NotEqual SLOComparison = "!="
+)
+
+//Preconditions defines the steady state of the targets which is required to inject chaos
+type Preconditions struct {
+	//TargetReady checks that all the replicas of the target workloads are ready
+	TargetReady bool `json:"targetReady,omitempty"`
+	//NoCrashLoopBackOff checks that none of the target pods is in CrashLoopBackOff
+	NoCrashLoopBackOff bool `json:"noCrashLoopBackOff,omitempty"`
+	//PodDisruptionBudgets is the list of PodDisruptionBudgets which should allow disruptions
+	PodDisruptionBudgets []PodDisruptionBudgetReference `json:"podDisruptionBudgets,omitempty"`
+	//HTTPProbe checks that an http endpoint returns the expected status
+	HTTPProbe *HTTPPrecondition `json:"httpProbe,omitempty"`
+}
+
+//PodDisruptionBudgetReference refers to a PodDisruptionBudget
+type PodDisruptionBudgetReference struct {
+	//Name of the PodDisruptionBudget
+	Name string `json:"name"`
+	//Namespace of the PodDisruptionBudget, defaults to the namespace of the schedule
+	Namespace string `json:"namespace,omitempty"`
+}
+
+//HTTPPrecondition defines the http endpoint to be probed
+type HTTPPrecondition struct {
+	//URL to be probed with a GET request
+	URL string `json:"url"`
+	//ExpectedStatus is the expected response code, defaults to 200
+	ExpectedStatus int `json:"expectedStatus,omitempty"`
+	//Timeout of the request, defaults to 5s and is capped to 10s
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+//MutexGroup defines the group of schedules which are mutually exclusive
+type MutexGroup struct {
+	//Name of the group, the engines of the schedules are labelled with it
+	Name string `json:"name"`
+	//Scope determines whether the group spans the namespace of the schedule or the whole cluster
+	Scope MutexScope `json:"scope,omitempty"`
+	//Policy determines whether to "defer" or "skip" the run while another schedule of the group is running
+	Policy MutexPolicy `json:"policy,omitempty"`
+}
+
+// MutexScope
+type MutexScope string
+
+const (
+	//NamespacedMutex excludes the schedules of the group present in the same namespace
+	NamespacedMutex MutexScope = "Namespaced"
+
+	//ClusterMutex excludes the schedules of the group present in any namespace
+	ClusterMutex MutexScope = "Cluster"
+)
+
+// MutexPolicy
+type MutexPolicy string
+
+const (
+	//DeferRun waits for the other schedules of the group to finish before starting the run
+	DeferRun MutexPolicy = "defer"
+
+	//SkipRun skips the run if another schedule of the group is running
+	SkipRun MutexPolicy = "skip"
+)
+
+//ScheduleReference refers to another schedule
+type ScheduleReference struct {
+	//Name of the schedule
+	Name string `json:"name"`
+	//Namespace of the schedule, defaults to the namespace of the referring schedule
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // ConcurrencyPolicy
@@ -105,8 +308,100 @@ type ChaosScheduleStatus struct {
 	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
 	// LastScheduleCompletionTime states the last time an engine was completed
 	LastScheduleCompletionTime *metav1.Time `json:"lastScheduleCompletionTime,omitempty"`
+	// LastEngineCreationTime states the time at which the engine of the last run was actually created
+	LastEngineCreationTime *metav1.Time `json:"lastEngineCreationTime,omitempty"`
+	// LastScheduleLag states the delay between the scheduled time of the last run and the creation of its engine
+	LastScheduleLag *metav1.Duration `json:"lastScheduleLag,omitempty"`
 	// Active states the list of chaosengines that are currently running
 	Active []coreV1.ObjectReference `json:"active,omitempty"`
+	// TargetRotation states the targets picked by the target rotation
+	TargetRotation *TargetRotationStatus `json:"targetRotation,omitempty"`
+	// Workflow states the progress of the engineTemplates in the current run
+	Workflow *WorkflowStatus `json:"workflow,omitempty"`
+	// ActiveRuns states the list of runs that are currently running, along with all the engines created for them
+	ActiveRuns []RunStatus `json:"activeRuns,omitempty"`
+	// History states the most recent finished runs
+	History []RunStatus `json:"history,omitempty"`
+	// LastSkippedRun states the last run which has been skipped or deferred, along with the reason
+	LastSkippedRun *SkippedRun `json:"lastSkippedRun,omitempty"`
+	// SLOGuard states the result of the last evaluation of the sloGuard
+	SLOGuard *SLOGuardStatus `json:"sloGuard,omitempty"`
+	// PreRunHook states the execution of the preRun hook for the most recent run
+	PreRunHook *HookStatus `json:"preRunHook,omitempty"`
+	// PostRunHook states the execution of the postRun hook for the most recent finished run
+	PostRunHook *HookStatus `json:"postRunHook,omitempty"`
+	// Conditions states the latest observations of the schedule, e.g. whether it is paused by the kill switch
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//SLOGuardStatus describes the last evaluation of the sloGuard
+type SLOGuardStatus struct {
+	//LastCheckTime is the time at which the query was last evaluated
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	//LastValue is the result of the query at the last evaluation
+	LastValue string `json:"lastValue,omitempty"`
+	//Breached is true if the SLO was breached at the last evaluation
+	Breached bool `json:"breached,omitempty"`
+	//Message describes the failure of the last evaluation, if any
+	Message string `json:"message,omitempty"`
+}
+
+//HookStatus describes the execution of a hook for a run
+type HookStatus struct {
+	//RunID of the run the hook is executed for
+	RunID string `json:"runID"`
+	//Phase of the hook, one of Running, Succeeded or Failed
+	Phase HookPhase `json:"phase"`
+	//Job is the name of the Job created for the hook, if any
+	Job string `json:"job,omitempty"`
+	//StartTime is the time at which the hook is started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	//CompletionTime is the time at which the hook succeeded or failed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	//Message describes the failure of the hook, if any
+	Message string `json:"message,omitempty"`
+}
+
+// HookPhase
+type HookPhase string
+
+const (
+	//HookRunning denotes that the Job of the hook is not finished yet
+	HookRunning HookPhase = "Running"
+
+	//HookSucceeded denotes that the Job of the hook is complete or that the webhook returned a 2xx status
+	HookSucceeded HookPhase = "Succeeded"
+
+	//HookFailed denotes that the hook failed or timed out
+	HookFailed HookPhase = "Failed"
+)
+
+//SkippedRun describes a run which has not been started at its scheduled time
+type SkippedRun struct {
+	//ScheduledTime is the time at which the run was scheduled
+	ScheduledTime *metav1.Time `json:"scheduledTime,omitempty"`
+	//Time at which the run has been skipped or deferred
+	Time metav1.Time `json:"time"`
+	//Deferred is true if the run is only delayed until the reason no longer applies
+	Deferred bool `json:"deferred,omitempty"`
+	//Reason is a brief CamelCase reason for skipping the run
+	Reason string `json:"reason"`
+	//Message is a human readable description of the reason
+	Message string `json:"message,omitempty"`
+}
+
+//RunStatus describes a single run of the schedule and the engines created for it
+type RunStatus struct {
+	//RunID identifies the run, the engines of the run are labelled with it
+	RunID string `json:"runID"`
+	//Engines is the list of engines created for the run
+	Engines []coreV1.ObjectReference `json:"engines,omitempty"`
+	//StartTime is the time at which the first engine of the run is created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	//EndTime is the time at which the run is finished
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	//Verdict of the run, Pass only if all of its engines passed
+	Verdict string `json:"verdict,omitempty"`
 }
 
 // Schedule defines information about schedule of chaos batch run
@@ -117,6 +412,8 @@ type Schedule struct {
 	Once *ScheduleOnce `json:"once,omitempty"`
 	// Repeat is for scheduling the engine between a time range
 	Repeat *ScheduleRepeat `json:"repeat,omitempty"`
+	// RRule is for scheduling the engine as per an iCalendar recurrence rule
+	RRule *ScheduleRRule `json:"rrule,omitempty"`
 }
 
 // ScheduleOnce will contain parameters for execution the once strategy of scheduling
@@ -133,6 +430,18 @@ type ScheduleRepeat struct {
 	WorkDays   *WorkDays                `json:"workDays,omitempty"`
 }
 
+// ScheduleRRule will contain the RFC 5545 recurrence rule of the schedule
+type ScheduleRRule struct {
+	//Rule is the recurrence rule, e.g. "FREQ=WEEKLY;BYDAY=TU,TH;BYHOUR=10", its COUNT and UNTIL bound the runs
+	Rule string `json:"rule"`
+	//StartTime is the DTSTART of the rule, defaults to the midnight of the day on which the schedule is created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	//ExcludedTimes are the EXDATEs of the rule, the occurrences at these times are not run
+	ExcludedTimes []metav1.Time `json:"excludedTimes,omitempty"`
+	//TimeRange limits the runs of the rule the same way as the ones of the repeat schedule
+	TimeRange *TimeRange `json:"timeRange,omitempty"`
+}
+
 //TimeRange will contain time constraints for the chaos to be injected
 type TimeRange struct {
 	//Start limit of the time range in which experiment is to be run
@@ -178,6 +487,135 @@ type WorkDays struct {
 	IncludedDays string `json:"includedDays,omitempty"`
 }
 
+//TargetRotation defines the targets over which the runs of the schedule are spread
+type TargetRotation struct {
+	//Strategy decides how the target of the next run is picked
+	Strategy RotationStrategy `json:"strategy,omitempty"`
+	//Candidates is the static list of targets to rotate over
+	Candidates []RotationTarget `json:"candidates,omitempty"`
+	//Selector discovers the targets from the workloads present in the cluster
+	Selector *TargetSelector `json:"selector,omitempty"`
+}
+
+// RotationStrategy
+type RotationStrategy string
+
+const (
+	//RoundRobinRotation picks the candidates one after the other
+	RoundRobinRotation RotationStrategy = "round-robin"
+
+	//RandomRotation picks a random candidate for every run, the pick is stable until the run is started
+	RandomRotation RotationStrategy = "random"
+
+	//LeastRecentlyTargetedRotation picks the candidate which has not been targeted for the longest time
+	LeastRecentlyTargetedRotation RotationStrategy = "least-recently-targeted"
+)
+
+//RotationTarget defines a single target application of the rotation
+type RotationTarget struct {
+	//Appns is the namespace of the target application
+	Appns string `json:"appns,omitempty"`
+	//Applabel is the label of the target application
+	Applabel string `json:"applabel,omitempty"`
+	//AppKind is the kind of the target application
+	AppKind string `json:"appkind,omitempty"`
+	//Name pins the run to a single workload, it is set for the targets discovered by the selector
+	Name string `json:"name,omitempty"`
+}
+
+//TargetSelector selects the workloads to rotate over
+type TargetSelector struct {
+	//AppKind is the kind of the workloads, one of deployment, statefulset or daemonset
+	AppKind string `json:"appkind"`
+	//Applabel is the label selector of the workloads
+	Applabel string `json:"applabel"`
+	//Namespaces in which the workloads are looked up, defaults to the namespace of the schedule
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+//TargetRotationStatus describes the targets picked by the rotation
+type TargetRotationStatus struct {
+	//LastTarget is the target of the most recent run
+	LastTarget *RotationTarget `json:"lastTarget,omitempty"`
+	//History contains the last time each of the candidates has been targeted
+	History []TargetRecord `json:"history,omitempty"`
+}
+
+//TargetRecord defines the last time a target has been picked
+type TargetRecord struct {
+	//Target is the picked target
+	Target RotationTarget `json:"target"`
+	//LastTargetedTime is the time at which the target has been picked
+	LastTargetedTime metav1.Time `json:"lastTargetedTime"`
+}
+
+//EngineTemplate defines a single step of a multi-step schedule
+type EngineTemplate struct {
+	//Name of the step, it is used as suffix of the engine name
+	Name string `json:"name"`
+	//Delay is the time to wait after the previous step is finished before starting this step
+	Delay *metav1.Duration `json:"delay,omitempty"`
+	//OnFailure determines whether to "continue" or "abort" the run when the engine of this step fails
+	OnFailure StepFailurePolicy `json:"onFailure,omitempty"`
+	//Parallel starts the engine of this step along with the engine of the previous step
+	Parallel bool `json:"parallel,omitempty"`
+	//Spec is the spec of the engine to be created for this step
+	Spec operatorV1.ChaosEngineSpec `json:"spec"`
+}
+
+// StepFailurePolicy
+type StepFailurePolicy string
+
+const (
+	//ContinueOnFailure starts the next step even if the current step failed
+	ContinueOnFailure StepFailurePolicy = "continue"
+
+	//AbortOnFailure skips the remaining steps of the run if the current step failed
+	AbortOnFailure StepFailurePolicy = "abort"
+)
+
+// WorkflowPhase describes the phase of a multi-step run
+type WorkflowPhase string
+
+const (
+	//WorkflowRunning denotes that the steps of the run are being executed
+	WorkflowRunning WorkflowPhase = "running"
+
+	//WorkflowCompleted denotes that all the steps of the run are executed
+	WorkflowCompleted WorkflowPhase = "completed"
+
+	//WorkflowAborted denotes that the run is aborted because of a failed step
+	WorkflowAborted WorkflowPhase = "aborted"
+)
+
+//WorkflowStatus describes the progress of the steps of a run
+type WorkflowStatus struct {
+	//RunID identifies the run, the engines of the run are labelled with it
+	RunID string `json:"runID,omitempty"`
+	//Phase defines the current phase of the run
+	Phase WorkflowPhase `json:"phase,omitempty"`
+	//CurrentStep is the index of the step being executed, the first one of them for the parallel steps
+	CurrentStep int `json:"currentStep"`
+	//NextStepTime is the time after which the next step is started
+	NextStepTime *metav1.Time `json:"nextStepTime,omitempty"`
+	//Steps contains the status of the started steps
+	Steps []StepStatus `json:"steps,omitempty"`
+}
+
+//StepStatus describes the status of a single step of the run
+type StepStatus struct {
+	//Name of the step
+	Name string `json:"name"`
+	//Engine is the name of the engine created for the step
+	Engine string `json:"engine,omitempty"`
+	//Verdict of the step, Pass or Fail once the engine is finished
+	Verdict string `json:"verdict,omitempty"`
+	//StartTime is the time at which the engine of the step is created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	//EndTime is the time at which the engine of the step is finished
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
 // +genclient
 // +resource:path=chaosschedule
 //+kubebuilder:object:root=true
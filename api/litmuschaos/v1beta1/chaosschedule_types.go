@@ -0,0 +1,191 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// ChaosScheduleSpec defines the desired state of ChaosSchedule
+// The fields which are unchanged since v1alpha1 share their types with it
+type ChaosScheduleSpec struct {
+	// ChaosServiceAccount is the SA specified for chaos runner pods
+	ChaosServiceAccount string `json:"chaosServiceAccount,omitempty"`
+	// Schedule defines when the runs of the schedule are started
+	Schedule Schedule `json:"schedule"`
+	// ScheduleState determines whether to "halt", "abort" or "active" the schedule
+	ScheduleState v1alpha1.ScheduleState `json:"scheduleState,omitempty"`
+	// ConcurrencyPolicy will state whether two engines from the same schedule
+	// can exist simultaneously or not
+	ConcurrencyPolicy v1alpha1.ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// EngineTemplateSpec is the spec of the engine to be created by this schedule
+	EngineTemplateSpec operatorV1.ChaosEngineSpec `json:"engineTemplateSpec,omitempty"`
+	// TargetRotation picks a different target application for every run of the schedule
+	TargetRotation *v1alpha1.TargetRotation `json:"targetRotation,omitempty"`
+	// EngineTemplates is the ordered list of engines to be created one after the other in every run
+	EngineTemplates []v1alpha1.EngineTemplate `json:"engineTemplates,omitempty"`
+	// HistoryLimit is the number of finished runs to be retained in the status
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+	// MutexGroup prevents the schedules of the same group from running chaos at the same time
+	MutexGroup *v1alpha1.MutexGroup `json:"mutexGroup,omitempty"`
+	// DependsOn is the list of schedules whose most recent run should have succeeded before this schedule fires
+	DependsOn []v1alpha1.ScheduleReference `json:"dependsOn,omitempty"`
+	// Preconditions are checked right before each run, the run is skipped if any of them fails
+	Preconditions *v1alpha1.Preconditions `json:"preconditions,omitempty"`
+	// SLOGuard is a Prometheus query checked before each run and polled while the engines are active
+	SLOGuard *v1alpha1.SLOGuard `json:"sloGuard,omitempty"`
+	// Hooks are the Jobs or webhooks run before and after each run of the schedule
+	Hooks *v1alpha1.RunHooks `json:"hooks,omitempty"`
+	// Notifications are the sinks the events of the schedule are sent to, along with the events they subscribe to
+	Notifications []v1alpha1.NotificationSink `json:"notifications,omitempty"`
+}
+
+// ScheduleType
+type ScheduleType string
+
+const (
+	//NowSchedule starts a single run as soon as the schedule is created
+	NowSchedule ScheduleType = "Now"
+
+	//OnceSchedule starts a single run at the execution time
+	OnceSchedule ScheduleType = "Once"
+
+	//RepeatSchedule starts the runs at a fixed interval
+	RepeatSchedule ScheduleType = "Repeat"
+
+	//RRuleSchedule starts the runs as per an iCalendar recurrence rule
+	RRuleSchedule ScheduleType = "RRule"
+)
+
+//Schedule is a discriminated union, only the member matching the type is set
+type Schedule struct {
+	//Type of the schedule, one of "Now", "Once", "Repeat" or "RRule"
+	Type ScheduleType `json:"type"`
+	//Once contains the execution time of the Once schedule
+	Once *v1alpha1.ScheduleOnce `json:"once,omitempty"`
+	//Repeat contains the interval of the Repeat schedule
+	Repeat *ScheduleRepeat `json:"repeat,omitempty"`
+	//RRule contains the recurrence rule of the RRule schedule
+	RRule *v1alpha1.ScheduleRRule `json:"rrule,omitempty"`
+}
+
+//ScheduleRepeat defines the runs repeated at a fixed interval
+type ScheduleRepeat struct {
+	//Interval between two runs, a whole number of minutes or hours, e.g. "15m" or "2h"
+	Interval metav1.Duration `json:"interval"`
+	//MinuteOfTheHour at which the runs start when the interval is a number of hours
+	MinuteOfTheHour int32 `json:"minuteOfTheHour,omitempty"`
+	//Hours of the day, from 0 to 23, in which the runs are started. All of them if empty
+	Hours []int32 `json:"hours,omitempty"`
+	//Days of the week on which the runs are started. All of them if empty
+	Days []Weekday `json:"days,omitempty"`
+	//TimeRange limits the runs to a period of time
+	TimeRange *v1alpha1.TimeRange `json:"timeRange,omitempty"`
+	//Random schedules the runs at a random time
+	Random bool `json:"random,omitempty"`
+}
+
+// Weekday is the three-letter abbreviation of a day of the week
+type Weekday string
+
+const (
+	Sunday    Weekday = "Sun"
+	Monday    Weekday = "Mon"
+	Tuesday   Weekday = "Tue"
+	Wednesday Weekday = "Wed"
+	Thursday  Weekday = "Thu"
+	Friday    Weekday = "Fri"
+	Saturday  Weekday = "Sat"
+)
+
+// ChaosScheduleStatus defines the observed state of ChaosSchedule
+type ChaosScheduleStatus struct {
+	//Phase of the schedule, one of "running", "halted", "stopped" or "completed"
+	Phase v1alpha1.ChaosStatus `json:"phase,omitempty"`
+	//StartTime defines the starting timestamp of the schedule
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	//EndTime defines the end timestamp of the schedule
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	//RunCount is the number of runs started so far
+	RunCount int `json:"runCount,omitempty"`
+	//NextRunTime is the approximate time at which the next run is started
+	NextRunTime *metav1.Time `json:"nextRunTime,omitempty"`
+	//LastRun describes the timing of the most recent run
+	LastRun *LastRunStatus `json:"lastRun,omitempty"`
+	//Active is the list of chaosengines that are currently running
+	Active []coreV1.ObjectReference `json:"active,omitempty"`
+	//ActiveRuns is the list of runs that are currently running, along with all the engines created for them
+	ActiveRuns []v1alpha1.RunStatus `json:"activeRuns,omitempty"`
+	//History is the list of the most recent finished runs
+	History []v1alpha1.RunStatus `json:"history,omitempty"`
+	//LastSkippedRun is the last run which has been skipped or deferred, along with the reason
+	LastSkippedRun *v1alpha1.SkippedRun `json:"lastSkippedRun,omitempty"`
+	//TargetRotation states the targets picked by the target rotation
+	TargetRotation *v1alpha1.TargetRotationStatus `json:"targetRotation,omitempty"`
+	//Workflow states the progress of the engineTemplates in the current run
+	Workflow *v1alpha1.WorkflowStatus `json:"workflow,omitempty"`
+	//SLOGuard states the result of the last evaluation of the sloGuard
+	SLOGuard *v1alpha1.SLOGuardStatus `json:"sloGuard,omitempty"`
+	//PreRunHook states the execution of the preRun hook for the most recent run
+	PreRunHook *v1alpha1.HookStatus `json:"preRunHook,omitempty"`
+	//PostRunHook states the execution of the postRun hook for the most recent finished run
+	PostRunHook *v1alpha1.HookStatus `json:"postRunHook,omitempty"`
+	//Conditions are the latest observations of the schedule, e.g. whether it is paused by the kill switch
+	//+listType=map
+	//+listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//LastRunStatus describes the timing of the most recent run
+type LastRunStatus struct {
+	//ScheduledTime is the time at which the run was scheduled
+	ScheduledTime *metav1.Time `json:"scheduledTime,omitempty"`
+	//EngineCreationTime is the time at which the engine of the run was actually created
+	EngineCreationTime *metav1.Time `json:"engineCreationTime,omitempty"`
+	//CompletionTime is the time at which the run was completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	//Lag is the delay between the scheduled time of the run and the creation of its engine
+	Lag *metav1.Duration `json:"lag,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ChaosSchedule is the Schema for the chaosschedules API
+type ChaosSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ChaosScheduleSpec   `json:"spec,omitempty"`
+	Status ChaosScheduleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ChaosScheduleList contains a list of ChaosSchedule
+type ChaosScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ChaosSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ChaosSchedule{}, &ChaosScheduleList{})
+}
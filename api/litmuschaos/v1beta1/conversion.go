@@ -0,0 +1,438 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// DroppedScheduleAnnotation holds the members of the v1alpha1 schedule which have no place in the v1beta1 schedule,
+// i.e. the members ignored by the scheduler, so that they are restored when the schedule is converted back
+const DroppedScheduleAnnotation = "litmuschaos.io/v1alpha1-dropped-schedule"
+
+// weekdays are the days of the week in the order of time.Weekday
+var weekdays = []Weekday{Sunday, Monday, Tuesday, Wednesday, Thursday, Friday, Saturday}
+
+// scheduleTypes are the schedule types in the order the scheduler picks the members of the v1alpha1 schedule
+var scheduleTypes = []ScheduleType{NowSchedule, OnceSchedule, RRuleSchedule, RepeatSchedule}
+
+// ConvertTo converts the schedule to the v1alpha1 version
+func (src *ChaosSchedule) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.ChaosSchedule)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = v1alpha1.ChaosScheduleSpec{
+		ChaosServiceAccount: src.Spec.ChaosServiceAccount,
+		ScheduleState:       src.Spec.ScheduleState,
+		ConcurrencyPolicy:   src.Spec.ConcurrencyPolicy,
+		EngineTemplateSpec:  src.Spec.EngineTemplateSpec,
+		TargetRotation:      src.Spec.TargetRotation,
+		EngineTemplates:     src.Spec.EngineTemplates,
+		HistoryLimit:        src.Spec.HistoryLimit,
+		MutexGroup:          src.Spec.MutexGroup,
+		DependsOn:           src.Spec.DependsOn,
+		Preconditions:       src.Spec.Preconditions,
+		SLOGuard:            src.Spec.SLOGuard,
+		Hooks:               src.Spec.Hooks,
+		Notifications:       src.Spec.Notifications,
+	}
+
+	schedule := src.Spec.Schedule
+	switch schedule.Type {
+	case NowSchedule:
+		dst.Spec.Schedule.Now = true
+	case OnceSchedule:
+		dst.Spec.Schedule.Once = schedule.Once
+	case RRuleSchedule:
+		dst.Spec.Schedule.RRule = schedule.RRule
+	case RepeatSchedule:
+		if schedule.Repeat != nil {
+			repeat, err := convertRepeatTo(schedule.Repeat)
+			if err != nil {
+				return err
+			}
+			dst.Spec.Schedule.Repeat = repeat
+		}
+	default:
+		return fmt.Errorf("invalid schedule type %q, should be one of ('Now', 'Once', 'Repeat', 'RRule')", schedule.Type)
+	}
+	if err := restoreDroppedSchedule(dst, schedule.Type); err != nil {
+		return err
+	}
+
+	status := src.Status
+	dst.Status = v1alpha1.ChaosScheduleStatus{
+		Schedule: v1alpha1.ScheduleStatus{
+			Status:              status.Phase,
+			StartTime:           status.StartTime,
+			EndTime:             status.EndTime,
+			RunInstances:        status.RunCount,
+			ExpectedNextRunTime: status.NextRunTime,
+		},
+		Active:         status.Active,
+		TargetRotation: status.TargetRotation,
+		Workflow:       status.Workflow,
+		ActiveRuns:     status.ActiveRuns,
+		History:        status.History,
+		LastSkippedRun: status.LastSkippedRun,
+		SLOGuard:       status.SLOGuard,
+		PreRunHook:     status.PreRunHook,
+		PostRunHook:    status.PostRunHook,
+		Conditions:     status.Conditions,
+	}
+	if lastRun := status.LastRun; lastRun != nil {
+		dst.Status.LastScheduleTime = lastRun.ScheduledTime
+		dst.Status.LastScheduleCompletionTime = lastRun.CompletionTime
+		dst.Status.LastEngineCreationTime = lastRun.EngineCreationTime
+		dst.Status.LastScheduleLag = lastRun.Lag
+	}
+	return nil
+}
+
+// ConvertFrom converts the schedule from the v1alpha1 version
+// The schedule type is picked in the same order as the scheduler does, the other members are ignored by it and kept
+// in the DroppedScheduleAnnotation
+func (dst *ChaosSchedule) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.ChaosSchedule)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = ChaosScheduleSpec{
+		ChaosServiceAccount: src.Spec.ChaosServiceAccount,
+		ScheduleState:       src.Spec.ScheduleState,
+		ConcurrencyPolicy:   src.Spec.ConcurrencyPolicy,
+		EngineTemplateSpec:  src.Spec.EngineTemplateSpec,
+		TargetRotation:      src.Spec.TargetRotation,
+		EngineTemplates:     src.Spec.EngineTemplates,
+		HistoryLimit:        src.Spec.HistoryLimit,
+		MutexGroup:          src.Spec.MutexGroup,
+		DependsOn:           src.Spec.DependsOn,
+		Preconditions:       src.Spec.Preconditions,
+		SLOGuard:            src.Spec.SLOGuard,
+		Hooks:               src.Spec.Hooks,
+		Notifications:       src.Spec.Notifications,
+	}
+
+	schedule := src.Spec.Schedule
+	switch {
+	case schedule.Now:
+		dst.Spec.Schedule.Type = NowSchedule
+	case schedule.Once != nil:
+		dst.Spec.Schedule = Schedule{Type: OnceSchedule, Once: schedule.Once}
+	case schedule.RRule != nil:
+		dst.Spec.Schedule = Schedule{Type: RRuleSchedule, RRule: schedule.RRule}
+	case schedule.Repeat != nil:
+		repeat, err := convertRepeatFrom(schedule.Repeat)
+		if err != nil {
+			return err
+		}
+		dst.Spec.Schedule = Schedule{Type: RepeatSchedule, Repeat: repeat}
+	}
+	if err := keepDroppedSchedule(dst, schedule); err != nil {
+		return err
+	}
+
+	status := src.Status
+	dst.Status = ChaosScheduleStatus{
+		Phase:          status.Schedule.Status,
+		StartTime:      status.Schedule.StartTime,
+		EndTime:        status.Schedule.EndTime,
+		RunCount:       status.Schedule.RunInstances,
+		NextRunTime:    status.Schedule.ExpectedNextRunTime,
+		Active:         status.Active,
+		ActiveRuns:     status.ActiveRuns,
+		History:        status.History,
+		LastSkippedRun: status.LastSkippedRun,
+		TargetRotation: status.TargetRotation,
+		Workflow:       status.Workflow,
+		SLOGuard:       status.SLOGuard,
+		PreRunHook:     status.PreRunHook,
+		PostRunHook:    status.PostRunHook,
+		Conditions:     status.Conditions,
+	}
+	if status.LastScheduleTime != nil || status.LastScheduleCompletionTime != nil || status.LastEngineCreationTime != nil || status.LastScheduleLag != nil {
+		dst.Status.LastRun = &LastRunStatus{
+			ScheduledTime:      status.LastScheduleTime,
+			EngineCreationTime: status.LastEngineCreationTime,
+			CompletionTime:     status.LastScheduleCompletionTime,
+			Lag:                status.LastScheduleLag,
+		}
+	}
+	return nil
+}
+
+// keepDroppedSchedule keeps the members of the v1alpha1 schedule which are not converted in the annotation of the schedule
+func keepDroppedSchedule(dst *ChaosSchedule, src v1alpha1.Schedule) error {
+
+	dropped := src
+	switch dst.Spec.Schedule.Type {
+	case NowSchedule:
+		dropped.Now = false
+	case OnceSchedule:
+		dropped.Once = nil
+	case RRuleSchedule:
+		dropped.RRule = nil
+	case RepeatSchedule:
+		// the hours are dropped from the interval when the minutes are set, as in the cron formed by the scheduler
+		dropped.Repeat = nil
+		if interval := src.Repeat.Properties.MinChaosInterval; interval != nil && interval.Minute != nil && interval.Hour != nil {
+			dropped.Repeat = &v1alpha1.ScheduleRepeat{Properties: v1alpha1.ScheduleRepeatProperties{
+				MinChaosInterval: &v1alpha1.MinChaosInterval{Hour: interval.Hour},
+			}}
+		}
+	}
+
+	annotations := map[string]string{}
+	for key, value := range dst.Annotations {
+		annotations[key] = value
+	}
+	delete(annotations, DroppedScheduleAnnotation)
+	if dropped.Now || dropped.Once != nil || dropped.RRule != nil || dropped.Repeat != nil {
+		data, err := json.Marshal(dropped)
+		if err != nil {
+			return err
+		}
+		annotations[DroppedScheduleAnnotation] = string(data)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	dst.Annotations = annotations
+	return nil
+}
+
+// restoreDroppedSchedule restores the members of the v1alpha1 schedule kept by keepDroppedSchedule, the members are
+// only restored when they are still ignored by the scheduler, i.e. when they come after the type of the schedule
+func restoreDroppedSchedule(dst *v1alpha1.ChaosSchedule, scheduleType ScheduleType) error {
+
+	data, found := dst.Annotations[DroppedScheduleAnnotation]
+	if !found {
+		return nil
+	}
+	annotations := map[string]string{}
+	for key, value := range dst.Annotations {
+		if key != DroppedScheduleAnnotation {
+			annotations[key] = value
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	dst.Annotations = annotations
+
+	var dropped v1alpha1.Schedule
+	if err := json.Unmarshal([]byte(data), &dropped); err != nil {
+		return fmt.Errorf("invalid %s annotation: %v", DroppedScheduleAnnotation, err)
+	}
+	ignored := false
+	for _, t := range scheduleTypes {
+		switch {
+		case t == scheduleType:
+			ignored = true
+		case !ignored:
+		case t == OnceSchedule:
+			dst.Spec.Schedule.Once = dropped.Once
+		case t == RRuleSchedule:
+			dst.Spec.Schedule.RRule = dropped.RRule
+		case t == RepeatSchedule:
+			dst.Spec.Schedule.Repeat = dropped.Repeat
+		}
+	}
+
+	repeat := dst.Spec.Schedule.Repeat
+	if scheduleType == RepeatSchedule && repeat != nil && dropped.Repeat != nil && dropped.Repeat.Properties.MinChaosInterval != nil {
+		if interval := repeat.Properties.MinChaosInterval; interval != nil && interval.Minute != nil && interval.Hour == nil {
+			interval.Hour = dropped.Repeat.Properties.MinChaosInterval.Hour
+		}
+	}
+	return nil
+}
+
+// convertRepeatTo converts the interval and the hour and day lists to the properties and the strings of v1alpha1
+func convertRepeatTo(src *ScheduleRepeat) (*v1alpha1.ScheduleRepeat, error) {
+
+	dst := &v1alpha1.ScheduleRepeat{
+		TimeRange:  src.TimeRange,
+		Properties: v1alpha1.ScheduleRepeatProperties{Random: src.Random},
+	}
+
+	// the multiples of an hour are converted to hours, the cron formed from the minutes of v1alpha1 does not span hours
+	interval := src.Interval.Duration
+	switch {
+	case interval == 0:
+	case interval > 0 && interval%time.Hour == 0:
+		dst.Properties.MinChaosInterval = &v1alpha1.MinChaosInterval{Hour: &v1alpha1.Hour{
+			EveryNthHour:    int(interval / time.Hour),
+			MinuteOfTheHour: int(src.MinuteOfTheHour),
+		}}
+	case interval > 0 && interval%time.Minute == 0:
+		dst.Properties.MinChaosInterval = &v1alpha1.MinChaosInterval{Minute: &v1alpha1.Minute{
+			EveryNthMinute: int(interval / time.Minute),
+		}}
+	default:
+		return nil, fmt.Errorf("invalid repeat interval %s, should be a whole number of minutes", interval)
+	}
+
+	if len(src.Hours) != 0 {
+		dst.WorkHours = &v1alpha1.WorkHours{IncludedHours: formatHours(src.Hours)}
+	}
+	if len(src.Days) != 0 {
+		days := make([]string, 0, len(src.Days))
+		for _, day := range src.Days {
+			days = append(days, string(day))
+		}
+		dst.WorkDays = &v1alpha1.WorkDays{IncludedDays: strings.Join(days, ",")}
+	}
+	return dst, nil
+}
+
+// convertRepeatFrom converts the properties and the strings of v1alpha1 to the interval and the hour and day lists
+func convertRepeatFrom(src *v1alpha1.ScheduleRepeat) (*ScheduleRepeat, error) {
+
+	dst := &ScheduleRepeat{
+		TimeRange: src.TimeRange,
+		Random:    src.Properties.Random,
+	}
+
+	// the minute interval takes precedence, as in the cron formed by the scheduler
+	if interval := src.Properties.MinChaosInterval; interval != nil {
+		switch {
+		case interval.Minute != nil:
+			dst.Interval.Duration = time.Duration(interval.Minute.EveryNthMinute) * time.Minute
+		case interval.Hour != nil:
+			dst.Interval.Duration = time.Duration(interval.Hour.EveryNthHour) * time.Hour
+			dst.MinuteOfTheHour = int32(interval.Hour.MinuteOfTheHour)
+		}
+	}
+
+	if src.WorkHours != nil && src.WorkHours.IncludedHours != "" {
+		hours, err := parseHours(src.WorkHours.IncludedHours)
+		if err != nil {
+			return nil, err
+		}
+		dst.Hours = hours
+	}
+	if src.WorkDays != nil && src.WorkDays.IncludedDays != "" {
+		days, err := parseDays(src.WorkDays.IncludedDays)
+		if err != nil {
+			return nil, err
+		}
+		dst.Days = days
+	}
+	return dst, nil
+}
+
+// parseHours expands the comma separated hours and ranges of hours, e.g. "9-12,14"
+func parseHours(includedHours string) ([]int32, error) {
+
+	var hours []int32
+	for _, field := range strings.Split(includedHours, ",") {
+		start, end, err := parseRange(field, func(value string) (int, error) {
+			hour, err := strconv.Atoi(value)
+			if err != nil || hour < 0 || hour > 23 {
+				return 0, fmt.Errorf("invalid hour %q, should be between 0 and 23", value)
+			}
+			return hour, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid includedHours %q, %v", includedHours, err)
+		}
+		for hour := start; hour <= end; hour++ {
+			hours = append(hours, int32(hour))
+		}
+	}
+	return hours, nil
+}
+
+// formatHours folds the consecutive hours into ranges, e.g. "9-12,14"
+func formatHours(hours []int32) string {
+
+	sorted := append([]int32{}, hours...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var fields []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] <= sorted[j]+1 {
+			j++
+		}
+		if sorted[i] == sorted[j] {
+			fields = append(fields, strconv.Itoa(int(sorted[i])))
+		} else {
+			fields = append(fields, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(fields, ",")
+}
+
+// parseDays expands the comma separated days and ranges of days, given either by their names or their numbers
+func parseDays(includedDays string) ([]Weekday, error) {
+
+	var days []Weekday
+	for _, field := range strings.Split(includedDays, ",") {
+		start, end, err := parseRange(field, func(value string) (int, error) {
+			for i, day := range weekdays {
+				if strings.EqualFold(value, string(day)) {
+					return i, nil
+				}
+			}
+			day, err := strconv.Atoi(value)
+			if err != nil || day < 0 || day > 6 {
+				return 0, fmt.Errorf("invalid day %q, should be either an abbreviated name or between 0 and 6", value)
+			}
+			return day, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid includedDays %q, %v", includedDays, err)
+		}
+		for day := start; day <= end; day++ {
+			days = append(days, weekdays[day])
+		}
+	}
+	return days, nil
+}
+
+// parseRange parses either a single value or a range of values separated by a dash
+func parseRange(field string, parse func(string) (int, error)) (int, int, error) {
+
+	bounds := strings.SplitN(strings.TrimSpace(field), "-", 2)
+	start, err := parse(strings.TrimSpace(bounds[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+	end := start
+	if len(bounds) == 2 {
+		if end, err = parse(strings.TrimSpace(bounds[1])); err != nil {
+			return 0, 0, err
+		}
+	}
+	if end < start {
+		return 0, 0, fmt.Errorf("the range %q is reversed", field)
+	}
+	return start, end, nil
+}
+
+var _ conversion.Convertible = &ChaosSchedule{}
@@ -0,0 +1,450 @@
+package v1beta1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	coreV1 "k8s.io/api/core/v1"
+	apix "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+var (
+	start   = metav1.NewTime(time.Date(2021, time.October, 6, 9, 0, 0, 0, time.UTC))
+	end     = metav1.NewTime(time.Date(2021, time.October, 8, 18, 0, 0, 0, time.UTC))
+	limit   = int32(5)
+	engines = []coreV1.ObjectReference{{Kind: "ChaosEngine", Name: "nginx-1633510800", UID: types.UID("1")}}
+)
+
+// newAlphaSchedule returns a v1alpha1 schedule with the given schedule and a status covering all the fields
+func newAlphaSchedule(schedule v1alpha1.Schedule) *v1alpha1.ChaosSchedule {
+	return &v1alpha1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", Labels: map[string]string{"team": "sre"}},
+		Spec: v1alpha1.ChaosScheduleSpec{
+			Schedule:          schedule,
+			ScheduleState:     v1alpha1.StateActive,
+			ConcurrencyPolicy: v1alpha1.ForbidConcurrent,
+			HistoryLimit:      &limit,
+			EngineTemplateSpec: operatorV1.ChaosEngineSpec{
+				Appinfo:     operatorV1.ApplicationParams{Appns: "default", Applabel: "app=nginx", AppKind: "deployment"},
+				Experiments: []operatorV1.ExperimentList{{Name: "pod-delete"}},
+			},
+			MutexGroup: &v1alpha1.MutexGroup{Name: "payments", Policy: v1alpha1.SkipRun},
+			DependsOn:  []v1alpha1.ScheduleReference{{Name: "network-latency"}},
+		},
+		Status: v1alpha1.ChaosScheduleStatus{
+			Schedule: v1alpha1.ScheduleStatus{
+				Status:              v1alpha1.StatusRunning,
+				StartTime:           &start,
+				RunInstances:        3,
+				ExpectedNextRunTime: &end,
+			},
+			LastScheduleTime:           &start,
+			LastScheduleCompletionTime: &start,
+			LastEngineCreationTime:     &start,
+			LastScheduleLag:            &metav1.Duration{Duration: time.Second},
+			Active:                     engines,
+			ActiveRuns:                 []v1alpha1.RunStatus{{RunID: "1633510800", Engines: engines, StartTime: &start}},
+			History:                    []v1alpha1.RunStatus{{RunID: "1633507200", StartTime: &start, EndTime: &start, Verdict: "Pass"}},
+			Conditions:                 []metav1.Condition{{Type: "Paused", Status: metav1.ConditionFalse, Reason: "KillSwitchReleased", LastTransitionTime: start}},
+		},
+	}
+}
+
+func TestRoundTripFromV1alpha1(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule v1alpha1.Schedule
+	}{
+		{
+			name:     "now",
+			schedule: v1alpha1.Schedule{Now: true},
+		},
+		{
+			name:     "once",
+			schedule: v1alpha1.Schedule{Once: &v1alpha1.ScheduleOnce{ExecutionTime: end}},
+		},
+		{
+			name: "repeat every few hours within the work hours and days",
+			schedule: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				TimeRange: &v1alpha1.TimeRange{StartTime: &start, EndTime: &end},
+				Properties: v1alpha1.ScheduleRepeatProperties{
+					MinChaosInterval: &v1alpha1.MinChaosInterval{Hour: &v1alpha1.Hour{EveryNthHour: 2, MinuteOfTheHour: 30}},
+				},
+				WorkHours: &v1alpha1.WorkHours{IncludedHours: "9-12,14,16-17"},
+				WorkDays:  &v1alpha1.WorkDays{IncludedDays: "Mon,Wed,Fri"},
+			}},
+		},
+		{
+			name: "repeat every few minutes at random",
+			schedule: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				Properties: v1alpha1.ScheduleRepeatProperties{
+					MinChaosInterval: &v1alpha1.MinChaosInterval{Minute: &v1alpha1.Minute{EveryNthMinute: 15}},
+					Random:           true,
+				},
+			}},
+		},
+		{
+			name: "repeat every 90 minutes",
+			schedule: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				Properties: v1alpha1.ScheduleRepeatProperties{
+					MinChaosInterval: &v1alpha1.MinChaosInterval{Minute: &v1alpha1.Minute{EveryNthMinute: 90}},
+				},
+			}},
+		},
+		{
+			name: "rrule",
+			schedule: v1alpha1.Schedule{RRule: &v1alpha1.ScheduleRRule{
+				Rule:          "FREQ=WEEKLY;BYDAY=TU,TH;BYHOUR=10",
+				StartTime:     &start,
+				ExcludedTimes: []metav1.Time{end},
+			}},
+		},
+		{
+			name: "members ignored by the scheduler",
+			schedule: v1alpha1.Schedule{
+				Now:    true,
+				Once:   &v1alpha1.ScheduleOnce{ExecutionTime: end},
+				RRule:  &v1alpha1.ScheduleRRule{Rule: "FREQ=DAILY"},
+				Repeat: &v1alpha1.ScheduleRepeat{Properties: v1alpha1.ScheduleRepeatProperties{Random: true}},
+			},
+		},
+		{
+			name: "hours ignored by the scheduler",
+			schedule: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				Properties: v1alpha1.ScheduleRepeatProperties{MinChaosInterval: &v1alpha1.MinChaosInterval{
+					Hour:   &v1alpha1.Hour{EveryNthHour: 2, MinuteOfTheHour: 10},
+					Minute: &v1alpha1.Minute{EveryNthMinute: 20},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := newAlphaSchedule(tt.schedule)
+			original.Annotations = map[string]string{"team": "sre"}
+
+			beta := &ChaosSchedule{}
+			if err := beta.ConvertFrom(original.DeepCopy()); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			converted := &v1alpha1.ChaosSchedule{}
+			if err := beta.ConvertTo(converted); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(original, converted) {
+				t.Fatalf("the round trip is lossy:\n%s", diff.ObjectReflectDiff(original, converted))
+			}
+		})
+	}
+}
+
+func TestRoundTripFromV1beta1(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+	}{
+		{
+			name:     "now",
+			schedule: Schedule{Type: NowSchedule},
+		},
+		{
+			name:     "once",
+			schedule: Schedule{Type: OnceSchedule, Once: &v1alpha1.ScheduleOnce{ExecutionTime: end}},
+		},
+		{
+			name: "repeat",
+			schedule: Schedule{Type: RepeatSchedule, Repeat: &ScheduleRepeat{
+				Interval:        metav1.Duration{Duration: 3 * time.Hour},
+				MinuteOfTheHour: 15,
+				Hours:           []int32{9, 10, 11, 15},
+				Days:            []Weekday{Monday, Tuesday, Saturday},
+				TimeRange:       &v1alpha1.TimeRange{EndTime: &end},
+			}},
+		},
+		{
+			name: "repeat every few minutes",
+			schedule: Schedule{Type: RepeatSchedule, Repeat: &ScheduleRepeat{
+				Interval: metav1.Duration{Duration: 10 * time.Minute},
+				Random:   true,
+			}},
+		},
+		{
+			name:     "rrule",
+			schedule: Schedule{Type: RRuleSchedule, RRule: &v1alpha1.ScheduleRRule{Rule: "FREQ=DAILY;COUNT=3"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &ChaosSchedule{
+				ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+				Spec:       ChaosScheduleSpec{Schedule: tt.schedule, ScheduleState: v1alpha1.StateHalted},
+				Status: ChaosScheduleStatus{
+					Phase:   v1alpha1.StatusHalted,
+					LastRun: &LastRunStatus{ScheduledTime: &start, Lag: &metav1.Duration{Duration: time.Second}},
+				},
+			}
+
+			alpha := &v1alpha1.ChaosSchedule{}
+			if err := original.DeepCopy().ConvertTo(alpha); err != nil {
+				t.Fatalf("ConvertTo() error = %v", err)
+			}
+			converted := &ChaosSchedule{}
+			if err := converted.ConvertFrom(alpha); err != nil {
+				t.Fatalf("ConvertFrom() error = %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(original, converted) {
+				t.Fatalf("the round trip is lossy:\n%s", diff.ObjectReflectDiff(original, converted))
+			}
+		})
+	}
+}
+
+func TestConvertFrom(t *testing.T) {
+	hourly := v1alpha1.ScheduleRepeatProperties{
+		MinChaosInterval: &v1alpha1.MinChaosInterval{Hour: &v1alpha1.Hour{EveryNthHour: 1}},
+	}
+
+	tests := []struct {
+		name     string
+		schedule v1alpha1.Schedule
+		want     Schedule
+		wantErr  bool
+	}{
+		{
+			name: "the members ignored by the scheduler are not converted",
+			schedule: v1alpha1.Schedule{
+				Now:    true,
+				Once:   &v1alpha1.ScheduleOnce{ExecutionTime: end},
+				Repeat: &v1alpha1.ScheduleRepeat{Properties: hourly},
+			},
+			want: Schedule{Type: NowSchedule},
+		},
+		{
+			name: "ranges of days",
+			schedule: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				Properties: hourly,
+				WorkDays:   &v1alpha1.WorkDays{IncludedDays: "Mon-Wed,sat"},
+			}},
+			want: Schedule{Type: RepeatSchedule, Repeat: &ScheduleRepeat{
+				Interval: metav1.Duration{Duration: time.Hour},
+				Days:     []Weekday{Monday, Tuesday, Wednesday, Saturday},
+			}},
+		},
+		{
+			name: "numbered days and hours",
+			schedule: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				Properties: hourly,
+				WorkDays:   &v1alpha1.WorkDays{IncludedDays: "1-2,0"},
+				WorkHours:  &v1alpha1.WorkHours{IncludedHours: "22-23, 0"},
+			}},
+			want: Schedule{Type: RepeatSchedule, Repeat: &ScheduleRepeat{
+				Interval: metav1.Duration{Duration: time.Hour},
+				Hours:    []int32{22, 23, 0},
+				Days:     []Weekday{Monday, Tuesday, Sunday},
+			}},
+		},
+		{
+			name: "the minutes take precedence over the hours",
+			schedule: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				Properties: v1alpha1.ScheduleRepeatProperties{MinChaosInterval: &v1alpha1.MinChaosInterval{
+					Hour:   &v1alpha1.Hour{EveryNthHour: 2},
+					Minute: &v1alpha1.Minute{EveryNthMinute: 20},
+				}},
+			}},
+			want: Schedule{Type: RepeatSchedule, Repeat: &ScheduleRepeat{Interval: metav1.Duration{Duration: 20 * time.Minute}}},
+		},
+		{
+			name: "invalid hours",
+			schedule: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				Properties: hourly,
+				WorkHours:  &v1alpha1.WorkHours{IncludedHours: "17-9"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid days",
+			schedule: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				Properties: hourly,
+				WorkDays:   &v1alpha1.WorkDays{IncludedDays: "Monday"},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beta := &ChaosSchedule{}
+			err := beta.ConvertFrom(&v1alpha1.ChaosSchedule{Spec: v1alpha1.ChaosScheduleSpec{Schedule: tt.schedule}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertFrom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !apiequality.Semantic.DeepEqual(beta.Spec.Schedule, tt.want) {
+				t.Fatalf("ConvertFrom() schedule:\n%s", diff.ObjectReflectDiff(tt.want, beta.Spec.Schedule))
+			}
+		})
+	}
+}
+
+func TestRestoreDroppedSchedule(t *testing.T) {
+	original := newAlphaSchedule(v1alpha1.Schedule{
+		Now:    true,
+		Once:   &v1alpha1.ScheduleOnce{ExecutionTime: end},
+		Repeat: &v1alpha1.ScheduleRepeat{Properties: v1alpha1.ScheduleRepeatProperties{Random: true}},
+	})
+	beta := &ChaosSchedule{}
+	if err := beta.ConvertFrom(original); err != nil {
+		t.Fatalf("ConvertFrom() error = %v", err)
+	}
+	if _, found := beta.Annotations[DroppedScheduleAnnotation]; !found {
+		t.Fatalf("annotations = %v, want the dropped members kept", beta.Annotations)
+	}
+	if original.Annotations != nil {
+		t.Fatalf("the annotations of the v1alpha1 schedule are changed: %v", original.Annotations)
+	}
+
+	// the members before the new type would take precedence over it, only the members after it are restored
+	beta.Spec.Schedule = Schedule{Type: RRuleSchedule, RRule: &v1alpha1.ScheduleRRule{Rule: "FREQ=HOURLY"}}
+	converted := &v1alpha1.ChaosSchedule{}
+	if err := beta.ConvertTo(converted); err != nil {
+		t.Fatalf("ConvertTo() error = %v", err)
+	}
+	want := v1alpha1.Schedule{RRule: beta.Spec.Schedule.RRule, Repeat: original.Spec.Schedule.Repeat}
+	if !apiequality.Semantic.DeepEqual(converted.Spec.Schedule, want) {
+		t.Fatalf("ConvertTo() schedule:\n%s", diff.ObjectReflectDiff(want, converted.Spec.Schedule))
+	}
+	if converted.Annotations != nil {
+		t.Fatalf("annotations = %v, want the dropped members removed", converted.Annotations)
+	}
+
+	beta.Annotations = map[string]string{DroppedScheduleAnnotation: "{"}
+	if err := beta.ConvertTo(&v1alpha1.ChaosSchedule{}); err == nil {
+		t.Fatal("ConvertTo() succeeded with an invalid annotation")
+	}
+}
+
+func TestConvertTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		want     v1alpha1.Schedule
+		wantErr  bool
+	}{
+		{
+			name: "multiples of an hour",
+			schedule: Schedule{Type: RepeatSchedule, Repeat: &ScheduleRepeat{
+				Interval: metav1.Duration{Duration: 120 * time.Minute},
+				Hours:    []int32{14, 9, 10, 11},
+			}},
+			want: v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+				Properties: v1alpha1.ScheduleRepeatProperties{
+					MinChaosInterval: &v1alpha1.MinChaosInterval{Hour: &v1alpha1.Hour{EveryNthHour: 2}},
+				},
+				WorkHours: &v1alpha1.WorkHours{IncludedHours: "9-11,14"},
+			}},
+		},
+		{
+			name:     "the members not matching the type are ignored",
+			schedule: Schedule{Type: OnceSchedule, Once: &v1alpha1.ScheduleOnce{ExecutionTime: end}, RRule: &v1alpha1.ScheduleRRule{Rule: "FREQ=DAILY"}},
+			want:     v1alpha1.Schedule{Once: &v1alpha1.ScheduleOnce{ExecutionTime: end}},
+		},
+		{
+			name:     "interval in seconds",
+			schedule: Schedule{Type: RepeatSchedule, Repeat: &ScheduleRepeat{Interval: metav1.Duration{Duration: 90 * time.Second}}},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown type",
+			schedule: Schedule{Type: "Cron"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alpha := &v1alpha1.ChaosSchedule{}
+			beta := &ChaosSchedule{Spec: ChaosScheduleSpec{Schedule: tt.schedule}}
+			err := beta.ConvertTo(alpha)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvertTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !apiequality.Semantic.DeepEqual(alpha.Spec.Schedule, tt.want) {
+				t.Fatalf("ConvertTo() schedule:\n%s", diff.ObjectReflectDiff(tt.want, alpha.Spec.Schedule))
+			}
+		})
+	}
+}
+
+// TestConversionWebhook converts a stored v1alpha1 schedule through the webhook served by the scheduler
+func TestConversionWebhook(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	webhook := &conversion.Webhook{}
+	if err := webhook.InjectScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	alpha := newAlphaSchedule(v1alpha1.Schedule{Repeat: &v1alpha1.ScheduleRepeat{
+		Properties: v1alpha1.ScheduleRepeatProperties{
+			MinChaosInterval: &v1alpha1.MinChaosInterval{Minute: &v1alpha1.Minute{EveryNthMinute: 30}},
+		},
+		WorkDays: &v1alpha1.WorkDays{IncludedDays: "Mon,Tue"},
+	}})
+	alpha.APIVersion, alpha.Kind = v1alpha1.GroupVersion.String(), "ChaosSchedule"
+	raw, err := json.Marshal(alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	review, err := json.Marshal(&apix.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: apix.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+		Request: &apix.ConversionRequest{
+			UID:               "1",
+			DesiredAPIVersion: GroupVersion.String(),
+			Objects:           []runtime.RawExtension{{Raw: raw}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := httptest.NewRecorder()
+	webhook.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(review)))
+
+	response := &apix.ConversionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Response.Result.Status != metav1.StatusSuccess {
+		t.Fatalf("conversion failed: %s", response.Response.Result.Message)
+	}
+	beta := &ChaosSchedule{}
+	if err := json.Unmarshal(response.Response.ConvertedObjects[0].Raw, beta); err != nil {
+		t.Fatal(err)
+	}
+	want := Schedule{Type: RepeatSchedule, Repeat: &ScheduleRepeat{
+		Interval: metav1.Duration{Duration: 30 * time.Minute},
+		Days:     []Weekday{Monday, Tuesday},
+	}}
+	if beta.APIVersion != GroupVersion.String() || !apiequality.Semantic.DeepEqual(beta.Spec.Schedule, want) {
+		t.Fatalf("converted %s schedule:\n%s", beta.APIVersion, diff.ObjectReflectDiff(want, beta.Spec.Schedule))
+	}
+}
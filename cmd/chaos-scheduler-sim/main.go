@@ -0,0 +1,273 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// chaos-scheduler-sim projects the fire times of ChaosSchedules without a cluster, it runs the scheduling
+// functions of the controller against a virtual clock
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/controllers"
+	"github.com/litmuschaos/chaos-scheduler/pkg/calendar"
+	"github.com/litmuschaos/chaos-scheduler/pkg/config"
+)
+
+// exitNeverFires is the exit code when any of the schedules never fires within the window
+const exitNeverFires = 2
+
+// simulation is the projection of a schedule, as printed in the json output
+type simulation struct {
+	Namespace  string      `json:"namespace"`
+	Name       string      `json:"name"`
+	CronString string      `json:"cron,omitempty"`
+	FireTimes  []time.Time `json:"fireTimes"`
+	Truncated  bool        `json:"truncated,omitempty"`
+	NeverFires bool        `json:"neverFires"`
+	Reason     string      `json:"reason,omitempty"`
+
+	schedule *schedulerV1.ChaosSchedule
+}
+
+func main() {
+	var (
+		file       string
+		configFile string
+		timeZone   string
+		from       string
+		days       int
+		output     string
+	)
+	flag.StringVar(&file, "f", "", "The file holding the ChaosSchedules, - reads from the standard input.")
+	flag.StringVar(&configFile, "config", "", "The configuration file of the chaos-scheduler, the schedules are evaluated in its default time zone.")
+	flag.StringVar(&timeZone, "time-zone", "", "The time zone in which the schedules are evaluated, takes precedence over the config file. Defaults to the local time zone.")
+	flag.StringVar(&from, "from", "", "The start of the simulation in RFC3339 format, defaults to now. The schedules without a creationTimestamp are considered as created at this time.")
+	flag.IntVar(&days, "days", 30, "The number of days simulated.")
+	flag.StringVar(&output, "o", "table", "The output format, one of ('table', 'json', 'ics').")
+	flag.Parse()
+
+	simulations, err := run(file, configFile, timeZone, from, days, output, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	neverFires := false
+	for _, sim := range simulations {
+		if sim.NeverFires {
+			fmt.Fprintf(os.Stderr, "Warning: %s/%s will never fire within the %d days, %s\n", sim.Namespace, sim.Name, days, sim.Reason)
+			neverFires = true
+		}
+	}
+	if neverFires {
+		os.Exit(exitNeverFires)
+	}
+}
+
+// run simulates the schedules of the file and writes the projections in the given format
+func run(file, configFile, timeZone, from string, days int, output string, w io.Writer) ([]simulation, error) {
+
+	switch output {
+	case "table", "json", "ics":
+	default:
+		return nil, fmt.Errorf("invalid output format %q, should be one of ('table', 'json', 'ics')", output)
+	}
+	if file == "" {
+		return nil, errors.New("the file of the ChaosSchedules is required, see -f")
+	}
+	if days <= 0 {
+		return nil, fmt.Errorf("invalid number of days %d, should be positive", days)
+	}
+
+	schedulerConfig := config.Default()
+	if configFile != "" {
+		var err error
+		if schedulerConfig, err = config.Load(configFile); err != nil {
+			return nil, err
+		}
+	}
+	if timeZone != "" {
+		schedulerConfig.Defaults.TimeZone = timeZone
+	}
+	settings, err := controllers.NewSettings(schedulerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q, err: %v", schedulerConfig.Defaults.TimeZone, err)
+	}
+
+	start := time.Now()
+	if from != "" {
+		if start, err = time.Parse(time.RFC3339, from); err != nil {
+			return nil, fmt.Errorf("invalid start time %q, err: %v", from, err)
+		}
+	}
+	start = start.In(settings.Location())
+	end := start.AddDate(0, 0, days)
+
+	schedules, err := readSchedules(file)
+	if err != nil {
+		return nil, err
+	}
+
+	simulations := []simulation{}
+	for _, schedule := range schedules {
+		sim := simulation{Namespace: schedule.Namespace, Name: schedule.Name, FireTimes: []time.Time{}, schedule: schedule}
+		// an invalid spec never fires, the controller fails on every reconcile of it
+		projection, err := controllers.ProjectFireTimes(schedule, settings, start, end)
+		switch {
+		case err != nil:
+			sim.NeverFires = true
+			sim.Reason = fmt.Sprintf("its spec is invalid: %v", err)
+		case len(projection.FireTimes) == 0:
+			sim.CronString = projection.CronString
+			sim.NeverFires = true
+			sim.Reason = getNeverFireReason(schedule, projection, start, end)
+		default:
+			sim.CronString = projection.CronString
+			sim.FireTimes = projection.FireTimes
+			sim.Truncated = projection.Truncated
+		}
+		simulations = append(simulations, sim)
+	}
+
+	switch output {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(simulations)
+	case "ics":
+		err = writeCalendar(w, simulations, schedulerConfig.Calendar.EventDuration.Duration)
+	default:
+		err = writeTable(w, simulations, settings.Location())
+	}
+	return simulations, err
+}
+
+// readSchedules reads the ChaosSchedules of the yaml or json file, the other kinds of objects are ignored
+func readSchedules(file string) ([]*schedulerV1.ChaosSchedule, error) {
+
+	var reader io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	var schedules []*schedulerV1.ChaosSchedule
+	decoder := k8syaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		schedule := &schedulerV1.ChaosSchedule{}
+		err := decoder.Decode(schedule)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s, err: %v", file, err)
+		}
+		if schedule.Kind != "ChaosSchedule" {
+			continue
+		}
+		schedules = append(schedules, schedule)
+	}
+	if len(schedules) == 0 {
+		return nil, fmt.Errorf("no ChaosSchedule found in %s", file)
+	}
+	return schedules, nil
+}
+
+// getNeverFireReason explains why the schedule does not fire within the window
+func getNeverFireReason(schedule *schedulerV1.ChaosSchedule, projection *controllers.Projection, start, end time.Time) string {
+
+	switch schedule.Spec.ScheduleState {
+	case schedulerV1.StateHalted, schedulerV1.StateStopped, schedulerV1.StateCompleted:
+		return fmt.Sprintf("its scheduleState is %s", schedule.Spec.ScheduleState)
+	}
+
+	if once := schedule.Spec.Schedule.Once; once != nil {
+		return fmt.Sprintf("its executionTime %s is after the end of the simulation", once.ExecutionTime.Format(time.RFC3339))
+	}
+
+	var timeRange *schedulerV1.TimeRange
+	switch {
+	case schedule.Spec.Schedule.RRule != nil:
+		timeRange = schedule.Spec.Schedule.RRule.TimeRange
+	case schedule.Spec.Schedule.Repeat != nil:
+		timeRange = schedule.Spec.Schedule.Repeat.TimeRange
+	}
+	if timeRange != nil {
+		switch {
+		case timeRange.StartTime != nil && timeRange.EndTime != nil && timeRange.EndTime.Before(timeRange.StartTime):
+			return "the endTime of its timeRange is before the startTime"
+		case timeRange.EndTime != nil && timeRange.EndTime.Time.Before(start):
+			return fmt.Sprintf("its timeRange ended at %s", timeRange.EndTime.Format(time.RFC3339))
+		case timeRange.StartTime != nil && timeRange.StartTime.Time.After(end):
+			return fmt.Sprintf("its timeRange starts at %s", timeRange.StartTime.Format(time.RFC3339))
+		}
+	}
+	if !schedule.CreationTimestamp.IsZero() && schedule.CreationTimestamp.Time.After(end) {
+		return fmt.Sprintf("it is created at %s", schedule.CreationTimestamp.Format(time.RFC3339))
+	}
+	if schedule.Spec.Schedule.RRule != nil {
+		return fmt.Sprintf("the rrule %q has no occurrence within the simulation", projection.CronString)
+	}
+	return fmt.Sprintf("no tick of the cron %q matches its workDays and workHours", projection.CronString)
+}
+
+// writeTable prints a line per fire time, in the time zone of the simulation
+func writeTable(w io.Writer, simulations []simulation, location *time.Location) error {
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tFIRE TIME\tCRON")
+	for _, sim := range simulations {
+		cron := sim.CronString
+		if cron == "" {
+			cron = "-"
+		}
+		if sim.NeverFires {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", sim.Namespace, sim.Name, "never", cron)
+			continue
+		}
+		for _, fireTime := range sim.FireTimes {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", sim.Namespace, sim.Name, fireTime.In(location).Format("Mon 2006-01-02 15:04:05 MST"), cron)
+		}
+		if sim.Truncated {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", sim.Namespace, sim.Name, "...", cron)
+		}
+	}
+	return tw.Flush()
+}
+
+// writeCalendar writes the fire times of all the schedules as a single calendar
+func writeCalendar(w io.Writer, simulations []simulation, eventDuration time.Duration) error {
+
+	var events []calendar.Event
+	for _, sim := range simulations {
+		events = append(events, controllers.GetCalendarEvents(sim.schedule, sim.FireTimes, eventDuration)...)
+	}
+	return calendar.Write(w, "chaos-scheduler-sim", time.Now(), events)
+}
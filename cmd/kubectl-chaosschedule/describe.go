@@ -0,0 +1,158 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/controllers"
+)
+
+// description is the json and yaml output of describe
+type description struct {
+	ChaosSchedule *schedulerV1.ChaosSchedule `json:"chaosSchedule"`
+	UpcomingRuns  []time.Time                `json:"upcomingRuns"`
+	Error         string                     `json:"error,omitempty"`
+}
+
+func newDescribeCommand(o *options) *cobra.Command {
+	var upcoming, days int
+	cmd := &cobra.Command{
+		Use:   "describe [NAME...]",
+		Short: "Show the details of the chaosschedules, along with the history of their runs and their upcoming runs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if days <= 0 {
+				return fmt.Errorf("invalid number of days %d, should be positive", days)
+			}
+			return o.runDescribe(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), args, upcoming, days)
+		},
+	}
+	cmd.Flags().IntVar(&upcoming, "upcoming", 5, "The maximum number of upcoming runs shown.")
+	cmd.Flags().IntVar(&days, "days", 30, "The number of days over which the upcoming runs are projected.")
+	return cmd
+}
+
+func (o *options) runDescribe(ctx context.Context, w, errOut io.Writer, names []string, upcoming, days int) error {
+	schedules, err := o.getSchedules(ctx, names)
+	if err != nil {
+		return err
+	}
+	if len(schedules) == 0 && o.output != "json" && o.output != "yaml" {
+		fmt.Fprintln(errOut, "No chaosschedules found.")
+		return nil
+	}
+
+	now := o.now()
+	descriptions := []description{}
+	for i := range schedules {
+		setTypeMeta(&schedules[i])
+		desc := description{ChaosSchedule: &schedules[i], UpcomingRuns: []time.Time{}}
+		projection, err := controllers.ProjectFireTimes(&schedules[i], o.settings, now, now.AddDate(0, 0, days))
+		switch {
+		case err != nil:
+			desc.Error = err.Error()
+		case len(projection.FireTimes) > upcoming:
+			desc.UpcomingRuns = projection.FireTimes[:upcoming]
+		default:
+			desc.UpcomingRuns = append(desc.UpcomingRuns, projection.FireTimes...)
+		}
+		descriptions = append(descriptions, desc)
+	}
+
+	switch o.output {
+	case "json", "yaml":
+		if len(descriptions) == 1 {
+			return printObject(w, o.output, descriptions[0])
+		}
+		return printObject(w, o.output, descriptions)
+	}
+	for i, desc := range descriptions {
+		if i != 0 {
+			fmt.Fprintln(w)
+		}
+		if err := o.printDescription(w, desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printDescription prints the chaosschedule the way kubectl describes the objects
+func (o *options) printDescription(w io.Writer, desc description) error {
+	schedule := desc.ChaosSchedule
+	status := &schedule.Status
+
+	tw := newTabWriter(w)
+	fmt.Fprintf(tw, "Name:\t%s\n", schedule.Name)
+	fmt.Fprintf(tw, "Namespace:\t%s\n", schedule.Namespace)
+	fmt.Fprintf(tw, "Labels:\t%s\n", formatOrNone(labels.FormatLabels(schedule.Labels)))
+	fmt.Fprintf(tw, "Created:\t%s (%s ago)\n", o.formatTime(schedule.CreationTimestamp.Time), o.formatAge(schedule.CreationTimestamp))
+	fmt.Fprintf(tw, "Schedule Type:\t%s\n", getScheduleType(schedule))
+	fmt.Fprintf(tw, "Schedule State:\t%s\n", formatOrNone(string(schedule.Spec.ScheduleState)))
+	fmt.Fprintf(tw, "Status:\t%s\n", formatOrNone(string(status.Schedule.Status)))
+	fmt.Fprintf(tw, "Run Instances:\t%d\n", status.Schedule.RunInstances)
+	fmt.Fprintf(tw, "Last Run:\t%s\n", o.formatMetaTime(status.LastScheduleTime))
+	fmt.Fprintf(tw, "Last Completion:\t%s\n", o.formatMetaTime(status.LastScheduleCompletionTime))
+	if skipped := status.LastSkippedRun; skipped != nil {
+		fmt.Fprintf(tw, "Last Skipped Run:\t%s, %s: %s\n", o.formatMetaTime(skipped.ScheduledTime), skipped.Reason, skipped.Message)
+	}
+	fmt.Fprintf(tw, "Active Engines:\t%s\n", getEngineNames(status.Active))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(w, "History:")
+	if len(status.History) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		tw = newTabWriter(w)
+		fmt.Fprintln(tw, "  RUN ID\tSTART\tEND\tDURATION\tVERDICT\tENGINES")
+		// the most recent runs are listed first
+		for i := len(status.History) - 1; i >= 0; i-- {
+			run := status.History[i]
+			runDuration := "<none>"
+			if run.StartTime != nil && run.EndTime != nil {
+				runDuration = duration.HumanDuration(run.EndTime.Sub(run.StartTime.Time))
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\t%s\n", run.RunID, o.formatMetaTime(run.StartTime), o.formatMetaTime(run.EndTime),
+				runDuration, formatOrNone(run.Verdict), getEngineNames(run.Engines))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintln(w, "Upcoming Runs:")
+	switch {
+	case desc.Error != "":
+		fmt.Fprintf(w, "  <invalid>, %s\n", desc.Error)
+	case len(desc.UpcomingRuns) == 0:
+		fmt.Fprintln(w, "  <none>")
+	}
+	for _, run := range desc.UpcomingRuns {
+		fmt.Fprintf(w, "  %s\n", o.formatTime(run))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/controllers"
+)
+
+// nextRunDays bounds the projection of the next run of the schedules
+const nextRunDays = 366
+
+func newListCommand(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list [NAME...]",
+		Aliases: []string{"ls"},
+		Short:   "List the chaosschedules with their state, next run, last verdict and active engines",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runList(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), args)
+		},
+	}
+}
+
+func (o *options) runList(ctx context.Context, w, errOut io.Writer, names []string) error {
+	schedules, err := o.getSchedules(ctx, names)
+	if err != nil {
+		return err
+	}
+	switch o.output {
+	case "json", "yaml":
+		return printSchedules(w, o.output, schedules)
+	}
+	if len(schedules) == 0 {
+		fmt.Fprintln(errOut, "No chaosschedules found.")
+		return nil
+	}
+
+	tw := newTabWriter(w)
+	columns := []string{"NAME", "STATE", "STATUS", "NEXT RUN", "LAST VERDICT", "ACTIVE", "AGE"}
+	if o.output == "wide" {
+		columns = append(columns, "TYPE", "SCHEDULE", "LAST RUN", "RUNS", "ENGINES")
+	}
+	o.printRow(tw, "NAMESPACE", columns...)
+
+	for i := range schedules {
+		schedule := &schedules[i]
+		nextRun, recurrence := o.getNextRun(schedule)
+		row := []string{
+			schedule.Name,
+			formatOrNone(string(schedule.Spec.ScheduleState)),
+			formatOrNone(string(schedule.Status.Schedule.Status)),
+			nextRun,
+			getLastVerdict(schedule),
+			strconv.Itoa(len(schedule.Status.Active)),
+			o.formatAge(schedule.CreationTimestamp),
+		}
+		if o.output == "wide" {
+			row = append(row,
+				getScheduleType(schedule),
+				formatOrNone(recurrence),
+				o.formatMetaTime(schedule.Status.LastScheduleTime),
+				strconv.Itoa(schedule.Status.Schedule.RunInstances),
+				getEngineNames(schedule.Status.Active),
+			)
+		}
+		o.printRow(tw, schedule.Namespace, row...)
+	}
+	return tw.Flush()
+}
+
+// getNextRun returns the scheduled time of the next run as projected by the simulator, along with the cron or the
+// rule of the schedule
+func (o *options) getNextRun(schedule *schedulerV1.ChaosSchedule) (string, string) {
+	now := o.now()
+	projection, err := controllers.ProjectFireTimes(schedule, o.settings, now, now.AddDate(0, 0, nextRunDays))
+	if err != nil {
+		return "<invalid>", ""
+	}
+	if len(projection.FireTimes) == 0 {
+		return "<none>", projection.CronString
+	}
+	return o.formatTime(projection.FireTimes[0]), projection.CronString
+}
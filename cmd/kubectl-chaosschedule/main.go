@@ -0,0 +1,199 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-chaosschedule operates the ChaosSchedules of a cluster, it is run as the "kubectl chaosschedule" plugin
+// once installed in the PATH
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/controllers"
+	"github.com/litmuschaos/chaos-scheduler/pkg/client/clientset/versioned"
+	"github.com/litmuschaos/chaos-scheduler/pkg/config"
+	"github.com/litmuschaos/chaos-scheduler/pkg/sdk"
+)
+
+func main() {
+	if err := newRootCommand(&options{}, os.Stdout, os.Stderr).ExecuteContext(context.Background()); err != nil {
+		os.Exit(1)
+	}
+}
+
+// options are the flags shared by all the commands, along with the clients built from them
+type options struct {
+	loadingRules  *clientcmd.ClientConfigLoadingRules
+	overrides     *clientcmd.ConfigOverrides
+	allNamespaces bool
+	output        string
+	timeZone      string
+
+	// clientset and namespace are set by the tests, they are otherwise read from the kubeconfig
+	clientset versioned.Interface
+	namespace string
+	// now defaults to the real clock
+	now func() time.Time
+
+	settings *controllers.Settings
+}
+
+func newRootCommand(o *options, out, errOut io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "kubectl-chaosschedule",
+		Short:        "Operate the ChaosSchedules of the cluster",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return o.complete()
+		},
+	}
+	cmd.SetOut(out)
+	cmd.SetErr(errOut)
+
+	o.loadingRules = clientcmd.NewDefaultClientConfigLoadingRules()
+	o.overrides = &clientcmd.ConfigOverrides{}
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&o.loadingRules.ExplicitPath, "kubeconfig", "", "Path to the kubeconfig file to use.")
+	clientcmd.BindOverrideFlags(o.overrides, flags, clientcmd.RecommendedConfigOverrideFlags(""))
+	flags.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "Operate on the chaosschedules of all the namespaces.")
+	flags.StringVarP(&o.output, "output", "o", "", "The output format, one of ('json', 'yaml', 'wide'), defaults to a table.")
+	flags.StringVar(&o.timeZone, "time-zone", "", "The time zone in which the schedules are evaluated, it should match the one of the chaos-scheduler. Defaults to the local time zone.")
+
+	cmd.AddCommand(
+		newListCommand(o),
+		newDescribeCommand(o),
+		newStateCommand(o, "halt", "halted", "Stop the chaosschedules from starting new runs, the running engines are left to finish", (*sdk.Client).Halt),
+		newStateCommand(o, "resume", "resumed", "Set halted or stopped chaosschedules active again", (*sdk.Client).Resume),
+		newStateCommand(o, "stop", "stopped", "Stop the chaosschedules from starting new runs and stop their running engines", (*sdk.Client).Stop),
+		newTriggerCommand(o),
+		newSimulateCommand(o),
+	)
+	return cmd
+}
+
+// complete validates the shared flags and builds the settings in which the schedules are evaluated
+func (o *options) complete() error {
+	switch o.output {
+	case "", "json", "yaml", "wide":
+	default:
+		return fmt.Errorf("invalid output format %q, should be one of ('json', 'yaml', 'wide')", o.output)
+	}
+	if o.now == nil {
+		o.now = time.Now
+	}
+
+	schedulerConfig := config.Default()
+	if o.timeZone != "" {
+		schedulerConfig.Defaults.TimeZone = o.timeZone
+	}
+	settings, err := controllers.NewSettings(schedulerConfig)
+	if err != nil {
+		return fmt.Errorf("invalid time zone %q, err: %v", schedulerConfig.Defaults.TimeZone, err)
+	}
+	o.settings = settings
+	return nil
+}
+
+// client returns the clientset and the namespace of the kubeconfig, they are only built by the commands which need
+// the cluster
+func (o *options) client() (versioned.Interface, string, error) {
+	if o.clientset != nil {
+		if namespace := o.overrides.Context.Namespace; namespace != "" {
+			return o.clientset, namespace, nil
+		}
+		return o.clientset, o.namespace, nil
+	}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(o.loadingRules, o.overrides)
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", err
+	}
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	clientset, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	o.clientset, o.namespace = clientset, namespace
+	return clientset, namespace, nil
+}
+
+// getSchedules returns the named chaosschedules of the namespace, or all of them if no name is given. The names are
+// looked up in all the namespaces with --all-namespaces
+func (o *options) getSchedules(ctx context.Context, names []string) ([]schedulerV1.ChaosSchedule, error) {
+	clientset, namespace, err := o.client()
+	if err != nil {
+		return nil, err
+	}
+	if o.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	schedules := clientset.LitmuschaosV1alpha1().ChaosSchedules(namespace)
+
+	if len(names) != 0 && !o.allNamespaces {
+		var items []schedulerV1.ChaosSchedule
+		for _, name := range names {
+			schedule, err := schedules.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, *schedule)
+		}
+		return items, nil
+	}
+
+	list, err := schedules.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	items := list.Items
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+	if len(names) == 0 {
+		return items, nil
+	}
+
+	var found []schedulerV1.ChaosSchedule
+	for _, name := range names {
+		matched := false
+		for _, item := range items {
+			if item.Name == name {
+				found = append(found, item)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, k8serrors.NewNotFound(schedulerV1.Resource("chaosschedules"), name)
+		}
+	}
+	return found, nil
+}
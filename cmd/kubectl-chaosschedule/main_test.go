@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/client/clientset/versioned/fake"
+	"github.com/litmuschaos/chaos-scheduler/pkg/sdk"
+)
+
+var now = time.Date(2021, time.October, 6, 9, 3, 0, 0, time.UTC)
+
+// newSchedules returns a running repeat schedule of the default namespace and a halted once schedule of another
+// namespace
+func newSchedules(t *testing.T) []runtime.Object {
+	nginx, err := sdk.NewSchedule("schedule-nginx", "default").
+		Labels(map[string]string{"team": "sre"}).
+		EveryMinutes(10).
+		Target("default", "app=nginx", "deployment").
+		Experiment("pod-delete", nil).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nginx.UID = "1"
+	nginx.CreationTimestamp = metav1.NewTime(now.Add(-3 * time.Hour))
+	nginx.Spec.ScheduleState = schedulerV1.StateActive
+	nginx.Status.Schedule = schedulerV1.ScheduleStatus{Status: schedulerV1.StatusRunning, RunInstances: 3}
+	nginx.Status.LastScheduleTime = &metav1.Time{Time: now.Add(-3 * time.Minute)}
+	nginx.Status.Active = []corev1.ObjectReference{{Namespace: "default", Name: "schedule-nginx-3"}}
+	nginx.Status.History = []schedulerV1.RunStatus{
+		{RunID: "run-1", StartTime: &metav1.Time{Time: now.Add(-23 * time.Minute)}, EndTime: &metav1.Time{Time: now.Add(-21 * time.Minute)}, Verdict: "Pass",
+			Engines: []corev1.ObjectReference{{Name: "schedule-nginx-1"}}},
+		{RunID: "run-2", StartTime: &metav1.Time{Time: now.Add(-13 * time.Minute)}, EndTime: &metav1.Time{Time: now.Add(-10 * time.Minute)}, Verdict: "Fail",
+			Engines: []corev1.ObjectReference{{Name: "schedule-nginx-2"}}},
+	}
+
+	kafka, err := sdk.NewSchedule("schedule-kafka", "streaming").
+		Once(now.Add(time.Hour)).
+		Target("streaming", "app=kafka", "statefulset").
+		Experiment("pod-delete", nil).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kafka.UID = "2"
+	kafka.CreationTimestamp = metav1.NewTime(now.Add(-48 * time.Hour))
+	kafka.Spec.ScheduleState = schedulerV1.StateHalted
+	kafka.Status.Schedule.Status = schedulerV1.StatusHalted
+
+	return []runtime.Object{nginx, kafka}
+}
+
+// runCommand runs the plugin against the fake clientset, in the UTC time zone
+func runCommand(clientset *fake.Clientset, args ...string) (string, string, error) {
+	o := &options{clientset: clientset, namespace: "default", now: func() time.Time { return now }}
+	var out, errOut bytes.Buffer
+	cmd := newRootCommand(o, &out, &errOut)
+	cmd.SetArgs(append(args, "--time-zone", "UTC"))
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), errOut.String(), err
+}
+
+// fields splits the lines of a table into their columns
+func fields(output string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		rows = append(rows, strings.Split(strings.Join(strings.Fields(line), " "), " "))
+	}
+	return rows
+}
+
+func TestList(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newSchedules(t)...)
+
+	out, _, err := runCommand(clientset, "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := fields(out)
+	if len(rows) != 2 || strings.Join(rows[1], " ") != "schedule-nginx active running 2021-10-06 09:10:00 UTC Fail 1 3h" {
+		t.Fatalf("list:\n%s", out)
+	}
+
+	out, _, err = runCommand(clientset, "list", "-A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows = fields(out)
+	if len(rows) != 3 || rows[0][0] != "NAMESPACE" || rows[1][0] != "default" || rows[2][0] != "streaming" ||
+		rows[2][2] != "halt" || rows[2][4] != "<none>" {
+		t.Fatalf("list -A:\n%s", out)
+	}
+
+	out, _, err = runCommand(clientset, "list", "-o", "wide")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "*/10 * * * *") || !strings.Contains(out, "schedule-nginx-3") || !strings.Contains(out, "repeat") {
+		t.Fatalf("list -o wide:\n%s", out)
+	}
+
+	out, _, err = runCommand(clientset, "list", "-A", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := &schedulerV1.ChaosScheduleList{}
+	if err := json.Unmarshal([]byte(out), list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Kind != "ChaosScheduleList" || len(list.Items) != 2 || list.Items[0].Kind != "ChaosSchedule" {
+		t.Fatalf("list -A -o json:\n%s", out)
+	}
+
+	if _, errOut, err := runCommand(clientset, "list", "-n", "empty"); err != nil || !strings.Contains(errOut, "No chaosschedules found") {
+		t.Fatalf("list of an empty namespace: %q, %v", errOut, err)
+	}
+}
+
+func TestListNames(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newSchedules(t)...)
+
+	out, _, err := runCommand(clientset, "list", "schedule-kafka", "-A", "-o", "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := &schedulerV1.ChaosSchedule{}
+	if err := yaml.Unmarshal([]byte(out), schedule); err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Name != "schedule-kafka" || schedule.Namespace != "streaming" {
+		t.Fatalf("list schedule-kafka -A -o yaml:\n%s", out)
+	}
+
+	if _, _, err := runCommand(clientset, "list", "schedule-kafka"); !k8serrors.IsNotFound(err) {
+		t.Fatalf("list of a schedule of another namespace error = %v", err)
+	}
+	if _, _, err := runCommand(clientset, "list", "schedule-redis", "-A"); !k8serrors.IsNotFound(err) {
+		t.Fatalf("list of a missing schedule error = %v", err)
+	}
+	if _, _, err := runCommand(clientset, "list", "-o", "name"); err == nil {
+		t.Fatal("list -o name should fail")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newSchedules(t)...)
+
+	out, _, err := runCommand(clientset, "describe", "schedule-nginx", "--upcoming", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Labels:            team=sre",
+		"Last Run:          2021-10-06 09:00:00 UTC",
+		"Active Engines:    schedule-nginx-3",
+		"History:\n  RUN ID   START",
+		"Upcoming Runs:\n  2021-10-06 09:10:00 UTC\n  2021-10-06 09:20:00 UTC\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("describe does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "run-2") > strings.Index(out, "run-1") {
+		t.Fatalf("describe should list the most recent runs first:\n%s", out)
+	}
+
+	out, _, err = runCommand(clientset, "describe", "schedule-kafka", "-n", "streaming", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := &description{}
+	if err := json.Unmarshal([]byte(out), desc); err != nil {
+		t.Fatal(err)
+	}
+	if desc.ChaosSchedule.Name != "schedule-kafka" || len(desc.UpcomingRuns) != 0 {
+		t.Fatalf("describe -o json of a halted schedule:\n%s", out)
+	}
+}
+
+func TestState(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newSchedules(t)...)
+	schedules := clientset.LitmuschaosV1alpha1().ChaosSchedules("default")
+
+	tests := []struct {
+		args []string
+		want string
+		done string
+	}{
+		{args: []string{"halt", "schedule-nginx"}, want: "halt", done: "chaosschedule.litmuschaos.io/schedule-nginx halted\n"},
+		{args: []string{"resume", "schedule-nginx"}, want: "active", done: "chaosschedule.litmuschaos.io/schedule-nginx resumed\n"},
+		{args: []string{"stop", "schedule-nginx", "-A"}, want: "stop", done: "chaosschedule.litmuschaos.io/schedule-nginx stopped in default\n"},
+	}
+	for _, tt := range tests {
+		out, _, err := runCommand(clientset, tt.args...)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		if out != tt.done {
+			t.Fatalf("%v: output %q, want %q", tt.args, out, tt.done)
+		}
+		schedule, err := schedules.Get(context.TODO(), "schedule-nginx", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(schedule.Spec.ScheduleState) != tt.want {
+			t.Fatalf("%v: scheduleState = %s, want %s", tt.args, schedule.Spec.ScheduleState, tt.want)
+		}
+	}
+
+	out, _, err := runCommand(clientset, "resume", "schedule-nginx", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := &schedulerV1.ChaosSchedule{}
+	if err := json.Unmarshal([]byte(out), schedule); err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Spec.ScheduleState != schedulerV1.StateActive {
+		t.Fatalf("resume -o json:\n%s", out)
+	}
+
+	if _, _, err := runCommand(clientset, "halt"); err == nil {
+		t.Fatal("halt without any name should fail")
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newSchedules(t)...)
+
+	out, _, err := runCommand(clientset, "trigger", "schedule-nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "chaosschedule.litmuschaos.io/schedule-nginx-manual-") || !strings.HasSuffix(out, " created\n") {
+		t.Fatalf("trigger output %q", out)
+	}
+
+	list, err := clientset.LitmuschaosV1alpha1().ChaosSchedules("default").List(context.TODO(), metav1.ListOptions{LabelSelector: sdk.TriggeredByLabel + "=schedule-nginx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || !list.Items[0].Spec.Schedule.Now {
+		t.Fatalf("trigger created %+v", list.Items)
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schedules.yaml")
+	manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: litmuschaos.io/v1alpha1
+kind: ChaosSchedule
+metadata:
+  name: schedule-nginx
+  namespace: default
+spec:
+  schedule:
+    repeat:
+      properties:
+        minChaosInterval:
+          hour:
+            everyNthHour: 6
+  engineTemplateSpec:
+    experiments:
+    - name: pod-delete
+`
+	if err := os.WriteFile(file, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// no cluster is needed to simulate the schedules of a file
+	o := &options{now: func() time.Time { return now }}
+	var out bytes.Buffer
+	cmd := newRootCommand(o, &out, &bytes.Buffer{})
+	cmd.SetArgs([]string{"simulate", "-f", file, "--from", "2021-10-06T00:00:00Z", "--days", "1", "--time-zone", "UTC", "-o", "wide"})
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rows := fields(out.String())
+	if len(rows) != 6 || strings.Join(rows[1][:4], " ") != "schedule-nginx 2021-10-06 00:00:00 UTC" || rows[1][4] != "0" {
+		t.Fatalf("simulate -f -o wide:\n%s", out.String())
+	}
+
+	clientset := fake.NewSimpleClientset(newSchedules(t)...)
+	output, _, err := runCommand(clientset, "simulate", "-A", "--days", "1", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var simulations []simulation
+	if err := json.Unmarshal([]byte(output), &simulations); err != nil {
+		t.Fatal(err)
+	}
+	if len(simulations) != 2 || len(simulations[0].FireTimes) != 6*24 || len(simulations[1].FireTimes) != 0 {
+		t.Fatalf("simulate -A -o json:\n%s", output)
+	}
+
+	if _, _, err := runCommand(clientset, "simulate", "schedule-nginx", "-f", file); err == nil {
+		t.Fatal("simulate with both names and -f should fail")
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/yaml"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// resourceName prefixes the names of the chaosschedules in the messages, the way kubectl does
+const resourceName = "chaosschedule.litmuschaos.io"
+
+// printObject prints the object in the json or yaml output format
+func printObject(w io.Writer, output string, obj interface{}) error {
+	if output == "yaml" {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(obj)
+}
+
+// printSchedules prints a single chaosschedule as is and several of them as a list, the way kubectl does
+func printSchedules(w io.Writer, output string, schedules []schedulerV1.ChaosSchedule) error {
+	for i := range schedules {
+		setTypeMeta(&schedules[i])
+	}
+	if len(schedules) == 1 {
+		return printObject(w, output, &schedules[0])
+	}
+	list := &schedulerV1.ChaosScheduleList{
+		TypeMeta: metav1.TypeMeta{APIVersion: schedulerV1.GroupVersion.String(), Kind: "ChaosScheduleList"},
+		Items:    schedules,
+	}
+	if list.Items == nil {
+		list.Items = []schedulerV1.ChaosSchedule{}
+	}
+	return printObject(w, output, list)
+}
+
+// setTypeMeta sets the type of the chaosschedule, which is dropped by the decoding of the clientset
+func setTypeMeta(schedule *schedulerV1.ChaosSchedule) {
+	schedule.APIVersion = schedulerV1.GroupVersion.String()
+	schedule.Kind = "ChaosSchedule"
+}
+
+func newTabWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+}
+
+// printRow writes the tab separated columns of a table, the namespace column is only printed for --all-namespaces
+func (o *options) printRow(w io.Writer, namespace string, columns ...string) {
+	if o.allNamespaces {
+		columns = append([]string{namespace}, columns...)
+	}
+	fmt.Fprintln(w, strings.Join(columns, "\t"))
+}
+
+// formatTime prints the time in the time zone of the schedules, the zero time is printed as <none>
+func (o *options) formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "<none>"
+	}
+	return t.In(o.settings.Location()).Format("2006-01-02 15:04:05 MST")
+}
+
+// formatMetaTime is formatTime for the optional times of the status
+func (o *options) formatMetaTime(t *metav1.Time) string {
+	if t == nil {
+		return "<none>"
+	}
+	return o.formatTime(t.Time)
+}
+
+// formatAge prints the time elapsed since the given time, the way kubectl prints the age of the objects
+func (o *options) formatAge(t metav1.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(o.now().Sub(t.Time))
+}
+
+// formatOrNone prints <none> for the empty values
+func formatOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
+
+// getScheduleType returns the type of schedule set in the spec
+func getScheduleType(schedule *schedulerV1.ChaosSchedule) string {
+	switch {
+	case schedule.Spec.Schedule.Now:
+		return "now"
+	case schedule.Spec.Schedule.Once != nil:
+		return "once"
+	case schedule.Spec.Schedule.Repeat != nil:
+		return "repeat"
+	case schedule.Spec.Schedule.RRule != nil:
+		return "rrule"
+	}
+	return "<none>"
+}
+
+// getLastVerdict returns the verdict of the most recent finished run
+func getLastVerdict(schedule *schedulerV1.ChaosSchedule) string {
+	if history := schedule.Status.History; len(history) != 0 {
+		return formatOrNone(history[len(history)-1].Verdict)
+	}
+	return "<none>"
+}
+
+// getEngineNames returns the comma separated names of the engines
+func getEngineNames(engines []corev1.ObjectReference) string {
+	names := make([]string, 0, len(engines))
+	for _, engine := range engines {
+		names = append(names, engine.Name)
+	}
+	return formatOrNone(strings.Join(names, ","))
+}
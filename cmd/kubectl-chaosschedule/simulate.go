@@ -0,0 +1,172 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/controllers"
+)
+
+// simulation is the projection of a schedule, as printed in the json and yaml output
+type simulation struct {
+	Namespace  string      `json:"namespace"`
+	Name       string      `json:"name"`
+	CronString string      `json:"cron,omitempty"`
+	FireTimes  []time.Time `json:"fireTimes"`
+	Truncated  bool        `json:"truncated,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+func newSimulateCommand(o *options) *cobra.Command {
+	var (
+		file string
+		from string
+		days int
+	)
+	cmd := &cobra.Command{
+		Use:   "simulate [NAME... | -f FILE]",
+		Short: "Project the runs of the chaosschedules without creating any engine",
+		Long: "Project the runs of the chaosschedules without creating any engine, the chaosschedules are read from the " +
+			"cluster or from a file with -f, in which case no cluster is needed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file != "" && len(args) != 0 {
+				return errors.New("the names of the chaosschedules cannot be given along with -f")
+			}
+			if days <= 0 {
+				return fmt.Errorf("invalid number of days %d, should be positive", days)
+			}
+			start := o.now()
+			if from != "" {
+				var err error
+				if start, err = time.Parse(time.RFC3339, from); err != nil {
+					return fmt.Errorf("invalid start time %q, err: %v", from, err)
+				}
+			}
+			return o.runSimulate(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout(), file, args, start, days)
+		},
+	}
+	cmd.Flags().StringVarP(&file, "filename", "f", "", "The file holding the ChaosSchedules, - reads from the standard input.")
+	cmd.Flags().StringVar(&from, "from", "", "The start of the simulation in RFC3339 format, defaults to now.")
+	cmd.Flags().IntVar(&days, "days", 7, "The number of days simulated.")
+	return cmd
+}
+
+func (o *options) runSimulate(ctx context.Context, in io.Reader, w io.Writer, file string, names []string, start time.Time, days int) error {
+	var schedules []schedulerV1.ChaosSchedule
+	var err error
+	if file != "" {
+		schedules, err = readSchedules(in, file)
+	} else {
+		schedules, err = o.getSchedules(ctx, names)
+	}
+	if err != nil {
+		return err
+	}
+
+	start = start.In(o.settings.Location())
+	end := start.AddDate(0, 0, days)
+	simulations := []simulation{}
+	for i := range schedules {
+		sim := simulation{Namespace: schedules[i].Namespace, Name: schedules[i].Name, FireTimes: []time.Time{}}
+		projection, err := controllers.ProjectFireTimes(&schedules[i], o.settings, start, end)
+		if err != nil {
+			sim.Error = err.Error()
+		} else {
+			sim.CronString = projection.CronString
+			sim.FireTimes = append(sim.FireTimes, projection.FireTimes...)
+			sim.Truncated = projection.Truncated
+		}
+		simulations = append(simulations, sim)
+	}
+
+	switch o.output {
+	case "json", "yaml":
+		return printObject(w, o.output, simulations)
+	}
+	tw := newTabWriter(w)
+	columns := []string{"NAME", "FIRE TIME"}
+	if o.output == "wide" {
+		columns = append(columns, "SCHEDULE")
+	}
+	o.printRow(tw, "NAMESPACE", columns...)
+	for _, sim := range simulations {
+		fireTimes := make([]string, 0, len(sim.FireTimes)+1)
+		for _, fireTime := range sim.FireTimes {
+			fireTimes = append(fireTimes, o.formatTime(fireTime))
+		}
+		switch {
+		case sim.Error != "":
+			fireTimes = append(fireTimes, "<invalid>")
+		case len(sim.FireTimes) == 0:
+			fireTimes = append(fireTimes, "<never>")
+		case sim.Truncated:
+			fireTimes = append(fireTimes, "...")
+		}
+		for _, fireTime := range fireTimes {
+			row := []string{sim.Name, fireTime}
+			if o.output == "wide" {
+				row = append(row, formatOrNone(sim.CronString))
+			}
+			o.printRow(tw, sim.Namespace, row...)
+		}
+	}
+	return tw.Flush()
+}
+
+// readSchedules reads the ChaosSchedules of the yaml or json file, the other kinds of objects are ignored
+func readSchedules(in io.Reader, file string) ([]schedulerV1.ChaosSchedule, error) {
+	reader := in
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	var schedules []schedulerV1.ChaosSchedule
+	decoder := k8syaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		schedule := schedulerV1.ChaosSchedule{}
+		err := decoder.Decode(&schedule)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s, err: %v", file, err)
+		}
+		if schedule.Kind != "ChaosSchedule" {
+			continue
+		}
+		schedules = append(schedules, schedule)
+	}
+	if len(schedules) == 0 {
+		return nil, fmt.Errorf("no ChaosSchedule found in %s", file)
+	}
+	return schedules, nil
+}
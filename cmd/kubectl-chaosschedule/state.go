@@ -0,0 +1,117 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/sdk"
+)
+
+// stateFunc sets the scheduleState of a chaosschedule, e.g. sdk.Client.Halt
+type stateFunc func(c *sdk.Client, ctx context.Context, namespace, name string) (*schedulerV1.ChaosSchedule, error)
+
+// newStateCommand returns the command setting the scheduleState of the chaosschedules, done is printed after their
+// names once they are updated
+func newStateCommand(o *options, use, done, short string, setState stateFunc) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " NAME...",
+		Short: short,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runState(cmd.Context(), cmd.OutOrStdout(), args, done, setState)
+		},
+	}
+}
+
+func (o *options) runState(ctx context.Context, w io.Writer, names []string, done string, setState stateFunc) error {
+	schedules, err := o.getSchedules(ctx, names)
+	if err != nil {
+		return err
+	}
+	clientset, _, err := o.client()
+	if err != nil {
+		return err
+	}
+	client := sdk.New(clientset)
+
+	var updated []schedulerV1.ChaosSchedule
+	for _, schedule := range schedules {
+		result, err := setState(client, ctx, schedule.Namespace, schedule.Name)
+		if err != nil {
+			return err
+		}
+		updated = append(updated, *result)
+	}
+	return o.printUpdated(w, updated, done)
+}
+
+// printUpdated prints the updated chaosschedules in the json or yaml output format, or a line per chaosschedule
+func (o *options) printUpdated(w io.Writer, schedules []schedulerV1.ChaosSchedule, done string) error {
+	switch o.output {
+	case "json", "yaml":
+		return printSchedules(w, o.output, schedules)
+	}
+	for _, schedule := range schedules {
+		if o.allNamespaces {
+			fmt.Fprintf(w, "%s/%s %s in %s\n", resourceName, schedule.Name, done, schedule.Namespace)
+			continue
+		}
+		fmt.Fprintf(w, "%s/%s %s\n", resourceName, schedule.Name, done)
+	}
+	return nil
+}
+
+func newTriggerCommand(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "trigger NAME...",
+		Short: "Start an extra run of the chaosschedules right away, outside of their schedule",
+		Long: "Start an extra run of the chaosschedules right away, outside of their schedule. The run is a chaosschedule " +
+			"of type now with the same spec, owned by the triggered chaosschedule.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runTrigger(cmd.Context(), cmd.OutOrStdout(), args)
+		},
+	}
+}
+
+func (o *options) runTrigger(ctx context.Context, w io.Writer, names []string) error {
+	schedules, err := o.getSchedules(ctx, names)
+	if err != nil {
+		return err
+	}
+	clientset, _, err := o.client()
+	if err != nil {
+		return err
+	}
+	client := sdk.New(clientset)
+
+	var runs []schedulerV1.ChaosSchedule
+	for _, schedule := range schedules {
+		run, err := client.TriggerNow(ctx, schedule.Namespace, schedule.Name)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, *run)
+	}
+	return o.printUpdated(w, runs, "created")
+}
@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/audit"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// audit writes the decision about the schedule to the audit log, if any
+func (r *ChaosScheduleReconciler) audit(cs *chaosTypes.SchedulerInfo, record audit.Record) {
+	if r.Audit == nil {
+		return
+	}
+	record.Time = r.now()
+	record.Schedule = audit.Schedule{
+		Namespace:  cs.Instance.Namespace,
+		Name:       cs.Instance.Name,
+		UID:        cs.Instance.UID,
+		Generation: cs.Instance.Generation,
+	}
+	r.Audit.Log(record)
+}
+
+// auditRunCreated records the creation of the engines of the run, along with the concurrency of the schedule
+func (r *ChaosScheduleReconciler) auditRunCreated(cs *chaosTypes.SchedulerInfo, runID string, scheduledTime time.Time) {
+
+	record := audit.Record{
+		Decision:      audit.Create,
+		RunID:         runID,
+		ScheduledTime: &scheduledTime,
+		Policy:        "schedule/" + getScheduleType(cs),
+	}
+	concurrent := 0
+	for _, run := range cs.Instance.Status.ActiveRuns {
+		if run.RunID == runID {
+			record.Engines = getAuditEngines(run.Engines)
+			continue
+		}
+		concurrent++
+	}
+	// the concurrency policy only applies while the earlier runs are active
+	policy := r.getConcurrencyPolicy(cs)
+	if concurrent != 0 {
+		record.Policy = "concurrencyPolicy/" + string(policy)
+	}
+	record.Inputs = map[string]string{
+		"concurrencyPolicy": string(policy),
+		"concurrentRuns":    strconv.Itoa(concurrent),
+	}
+	if lag := cs.Instance.Status.LastScheduleLag; lag != nil {
+		record.Inputs["lag"] = lag.Duration.String()
+	}
+	r.audit(cs, record)
+}
+
+// auditHeldRun records the run which did not pass its gates
+func (r *ChaosScheduleReconciler) auditHeldRun(cs *chaosTypes.SchedulerInfo, decision audit.Decision, scheduledTime time.Time, gate gateResult) {
+	r.audit(cs, audit.Record{
+		Decision:      decision,
+		RunID:         getRunID(scheduledTime),
+		ScheduledTime: &scheduledTime,
+		Policy:        gate.policy,
+		Reason:        gate.reason,
+		Message:       gate.message,
+	})
+}
+
+// auditRunCompleted records the run moved to the history
+func (r *ChaosScheduleReconciler) auditRunCompleted(cs *chaosTypes.SchedulerInfo, run schedulerV1.RunStatus) {
+
+	record := audit.Record{
+		Decision: audit.Complete,
+		RunID:    run.RunID,
+		Engines:  getAuditEngines(run.Engines),
+		Verdict:  run.Verdict,
+	}
+	if seconds, err := strconv.ParseInt(run.RunID, 10, 64); err == nil {
+		scheduledTime := time.Unix(seconds, 0).UTC()
+		record.ScheduledTime = &scheduledTime
+	}
+	if hook := cs.Instance.Status.PostRunHook; hook != nil && hook.RunID == run.RunID {
+		record.Inputs = map[string]string{"postRunHook": string(hook.Phase)}
+	}
+	r.audit(cs, record)
+}
+
+// auditStoppedEngines records the engines stopped as per the policy
+func (r *ChaosScheduleReconciler) auditStoppedEngines(cs *chaosTypes.SchedulerInfo, decision audit.Decision, policy, reason, message string, stopped []string) {
+
+	names := map[string]bool{}
+	for _, name := range stopped {
+		names[name] = true
+	}
+	var engines []corev1.ObjectReference
+	for _, ref := range cs.Instance.Status.Active {
+		if names[ref.Name] {
+			engines = append(engines, ref)
+		}
+	}
+	r.audit(cs, audit.Record{
+		Decision: decision,
+		Engines:  getAuditEngines(engines),
+		Policy:   policy,
+		Reason:   reason,
+		Message:  message,
+	})
+}
+
+// getAuditEngines returns the engines of the references as recorded in the audit log
+func getAuditEngines(refs []corev1.ObjectReference) []audit.Engine {
+	var engines []audit.Engine
+	for _, ref := range refs {
+		engines = append(engines, audit.Engine{Namespace: ref.Namespace, Name: ref.Name, UID: ref.UID})
+	}
+	return engines
+}
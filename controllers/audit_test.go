@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/audit"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// readAuditLog decodes the records written to the buffer
+func readAuditLog(t *testing.T, buf *bytes.Buffer) []audit.Record {
+	var records []audit.Record
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		record := audit.Record{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid audit record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestAuditHeldRuns(t *testing.T) {
+	schedule := newHookSchedule(nil)
+	schedule.Spec.MutexGroup = &schedulerV1.MutexGroup{Name: "databases"}
+	s := &reconcileScheduler{r: newFakeReconciler(t, schedule), reqLogger: chaosTypes.Log}
+	var buf bytes.Buffer
+	s.r.Audit = audit.New(&buf)
+
+	cs := &chaosTypes.SchedulerInfo{Instance: schedule.DeepCopy()}
+	if err := s.r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, cs.Instance); err != nil {
+		t.Fatal(err)
+	}
+
+	deferred := gateResult{policy: "mutexGroup/" + string(getMutexPolicy(schedule.Spec.MutexGroup)), reason: "MutexGroupBusy", message: "engine default/other is active"}
+	// the deferred run is evaluated again until the gate passes, it is recorded once per reason
+	for i := 0; i < 2; i++ {
+		if _, err := s.holdRun(cs, at(10, 0), deferred, reconcile.Request{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	aborted := gateResult{abort: true, policy: "hooks.preRun/abort", reason: "PreRunHookFailed", message: "preRun hook failed"}
+	if _, err := s.holdRun(cs, at(10, 10), aborted, reconcile.Request{}); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readAuditLog(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("wrote %d records, want the deferral, the abort and the completion of the run: %+v", len(records), records)
+	}
+
+	want := []struct {
+		decision audit.Decision
+		policy   string
+		runID    string
+		verdict  string
+	}{
+		{audit.Defer, "mutexGroup/defer", getRunID(at(10, 0)), ""},
+		{audit.Abort, "hooks.preRun/abort", getRunID(at(10, 10)), ""},
+		{audit.Complete, "", getRunID(at(10, 10)), "Aborted"},
+	}
+	for i, w := range want {
+		record := records[i]
+		if record.Decision != w.decision || record.Policy != w.policy || record.RunID != w.runID || record.Verdict != w.verdict {
+			t.Errorf("record %d = %+v, want %+v", i, record, w)
+		}
+		if record.Version != audit.SchemaVersion || record.Schedule.Name != "schedule" || record.Schedule.UID != "schedule-uid" {
+			t.Errorf("record %d = %+v", i, record)
+		}
+		if record.ScheduledTime == nil {
+			t.Errorf("record %d has no scheduled time", i)
+		}
+	}
+}
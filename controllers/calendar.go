@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/calendar"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// calendarPath is the prefix of the paths served by the CalendarHandler
+const calendarPath = "/calendar/"
+
+// totalChaosDurationEnv is the env of the experiments holding their duration in seconds
+const totalChaosDurationEnv = "TOTAL_CHAOS_DURATION"
+
+// GetCalendarEvents returns an event per fire time of the schedule, described by the experiments of its engines
+// The events last for the expected duration of the run, or the given default duration when it is unknown
+func GetCalendarEvents(schedule *schedulerV1.ChaosSchedule, fireTimes []time.Time, defaultDuration time.Duration) []calendar.Event {
+
+	experiments := []string{}
+	for _, experiment := range schedule.Spec.EngineTemplateSpec.Experiments {
+		experiments = append(experiments, experiment.Name)
+	}
+	for _, template := range schedule.Spec.EngineTemplates {
+		for _, experiment := range template.Spec.Experiments {
+			experiments = append(experiments, experiment.Name)
+		}
+	}
+
+	duration, known := getExpectedRunDuration(schedule)
+	if !known {
+		duration = defaultDuration
+	}
+
+	events := make([]calendar.Event, 0, len(fireTimes))
+	for _, fireTime := range fireTimes {
+		events = append(events, calendar.Event{
+			UID:         calendar.EventUID(schedule.Namespace, schedule.Name, fireTime),
+			Start:       fireTime,
+			Duration:    duration,
+			Summary:     fmt.Sprintf("ChaosSchedule %s/%s", schedule.Namespace, schedule.Name),
+			Description: "Experiments: " + strings.Join(experiments, ", "),
+		})
+	}
+	return events
+}
+
+// getExpectedRunDuration returns the expected duration of a run from the TOTAL_CHAOS_DURATION of the experiments, the
+// experiments of an engine and the steps of the engineTemplates run one after the other, unless the steps are parallel.
+// It returns false when none of the experiments sets its duration
+func getExpectedRunDuration(schedule *schedulerV1.ChaosSchedule) (time.Duration, bool) {
+
+	if len(schedule.Spec.EngineTemplates) == 0 {
+		return getExpectedEngineDuration(schedule.Spec.EngineTemplateSpec)
+	}
+
+	var total, group time.Duration
+	known := false
+	for i, step := range schedule.Spec.EngineTemplates {
+		duration, found := getExpectedEngineDuration(step.Spec)
+		known = known || found
+		// the parallel step starts along with the previous step, the run goes on once the longest of them is finished
+		if step.Parallel && i != 0 {
+			if duration > group {
+				group = duration
+			}
+			continue
+		}
+		total += group
+		if step.Delay != nil {
+			total += step.Delay.Duration
+		}
+		group = duration
+	}
+	return total + group, known
+}
+
+// getExpectedEngineDuration returns the sum of the TOTAL_CHAOS_DURATION of the experiments of the engine
+func getExpectedEngineDuration(spec operatorV1.ChaosEngineSpec) (time.Duration, bool) {
+
+	var total time.Duration
+	known := false
+	for _, experiment := range spec.Experiments {
+		for _, env := range experiment.Spec.Components.ENV {
+			if env.Name != totalChaosDurationEnv {
+				continue
+			}
+			if seconds, err := strconv.Atoi(env.Value); err == nil && seconds > 0 {
+				total += time.Duration(seconds) * time.Second
+				known = true
+			}
+		}
+	}
+	return total, known
+}
+
+// CalendarHandler serves the upcoming runs of the schedules as iCalendar files, it only reads from the given reader
+// which is meant to be the cache of the manager. The paths are
+// /calendar/<namespace>.ics for all the schedules of the namespace and /calendar/<namespace>/<schedule>.ics
+type CalendarHandler struct {
+	// Reader reads the schedules, e.g. the cache of the manager
+	Reader client.Reader
+	// Settings are the controller-wide defaults of the schedules, the time zone in particular
+	Settings *Settings
+	// Days is the number of days of upcoming runs rendered in the calendars
+	Days int
+	// EventDuration is the duration of the events of the runs whose experiments do not set their duration
+	EventDuration time.Duration
+	// Clock is the clock the upcoming runs are projected from
+	Clock clock.Clock
+}
+
+// NewCalendarHandler returns the handler of the calendars, the clock defaults to the real clock when it is nil
+func NewCalendarHandler(reader client.Reader, settings *Settings, days int, eventDuration time.Duration, c clock.Clock) *CalendarHandler {
+
+	if c == nil {
+		c = clock.RealClock{}
+	}
+	return &CalendarHandler{
+		Reader:        reader,
+		Settings:      settings,
+		Days:          days,
+		EventDuration: eventDuration,
+		Clock:         c,
+	}
+}
+
+func (h *CalendarHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	path := strings.TrimPrefix(req.URL.Path, calendarPath)
+	if path == req.URL.Path || !strings.HasSuffix(path, ".ics") {
+		http.NotFound(w, req)
+		return
+	}
+
+	var name, namespace string
+	switch parts := strings.Split(strings.TrimSuffix(path, ".ics"), "/"); len(parts) {
+	case 1:
+		namespace = parts[0]
+	case 2:
+		namespace, name = parts[0], parts[1]
+	}
+	if namespace == "" || (strings.Contains(path, "/") && name == "") {
+		http.NotFound(w, req)
+		return
+	}
+
+	schedules, err := h.getSchedules(req.Context(), namespace, name)
+	switch {
+	case k8serrors.IsNotFound(err):
+		http.NotFound(w, req)
+		return
+	case err != nil:
+		chaosTypes.Log.Error(err, "Unable to read the schedules of the calendar", "Namespace", namespace, "Name", name)
+		http.Error(w, "unable to read the schedules", http.StatusInternalServerError)
+		return
+	}
+
+	now := h.Clock.Now()
+	until := now.AddDate(0, 0, h.Days)
+
+	var events []calendar.Event
+	for i := range schedules {
+		projection, err := ProjectFireTimes(&schedules[i], h.Settings, now, until)
+		if err != nil {
+			// the invalid schedules have no upcoming runs, the other ones are still rendered
+			continue
+		}
+		events = append(events, GetCalendarEvents(&schedules[i], projection.FireTimes, h.EventDuration)...)
+	}
+
+	calendarName := namespace
+	if name != "" {
+		calendarName = namespace + "/" + name
+	}
+	var buf bytes.Buffer
+	if err := calendar.Write(&buf, calendarName, now, events); err != nil {
+		http.Error(w, "unable to render the calendar", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// getSchedules returns the schedule with the given name, or all the schedules of the namespace if the name is empty
+func (h *CalendarHandler) getSchedules(ctx context.Context, namespace, name string) ([]schedulerV1.ChaosSchedule, error) {
+
+	if name != "" {
+		schedule := schedulerV1.ChaosSchedule{}
+		if err := h.Reader.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &schedule); err != nil {
+			return nil, err
+		}
+		return []schedulerV1.ChaosSchedule{schedule}, nil
+	}
+
+	var scheduleList schedulerV1.ChaosScheduleList
+	if err := h.Reader.List(ctx, &scheduleList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	return scheduleList.Items, nil
+}
+
+// CalendarServer serves the CalendarHandler until the manager is stopped
+type CalendarServer struct {
+	// BindAddress is the address the server listens on
+	BindAddress string
+	// Handler serves the calendars
+	Handler *CalendarHandler
+}
+
+// Start runs the server until the context is cancelled
+func (s *CalendarServer) Start(ctx context.Context) error {
+
+	mux := http.NewServeMux()
+	mux.Handle(calendarPath, s.Handler)
+	server := &http.Server{Addr: s.BindAddress, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	chaosTypes.Log.Info("Serving the calendars of the schedules", "BindAddress", s.BindAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection makes the server run on all the replicas, see manager.LeaderElectionRunnable
+func (s *CalendarServer) NeedLeaderElection() bool {
+	return false
+}
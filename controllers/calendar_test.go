@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+func TestCalendarHandler(t *testing.T) {
+	repeat := schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+		Properties: schedulerV1.ScheduleRepeatProperties{
+			MinChaosInterval: &schedulerV1.MinChaosInterval{Hour: &schedulerV1.Hour{EveryNthHour: 6}},
+		},
+	}}
+	hourly := newTestSchedule("hourly", repeat)
+	hourly.CreationTimestamp.Time = at(8, 0)
+	halted := newTestSchedule("halted", repeat)
+	halted.CreationTimestamp.Time = at(8, 0)
+	halted.Spec.ScheduleState = schedulerV1.StateHalted
+
+	handler := NewCalendarHandler(newFakeReconciler(t, hourly, halted).Client, newTestScheduler(t).r.Settings, 1,
+		15*time.Minute, clocktesting.NewFakeClock(at(10, 0)))
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantEvents int
+	}{
+		{name: "schedule", method: http.MethodGet, path: "/calendar/default/hourly.ics", wantStatus: http.StatusOK, wantEvents: 5},
+		{name: "namespace", method: http.MethodGet, path: "/calendar/default.ics", wantStatus: http.StatusOK, wantEvents: 5},
+		{name: "halted schedule", method: http.MethodGet, path: "/calendar/default/halted.ics", wantStatus: http.StatusOK},
+		{name: "empty namespace", method: http.MethodGet, path: "/calendar/other.ics", wantStatus: http.StatusOK},
+		{name: "missing schedule", method: http.MethodGet, path: "/calendar/default/missing.ics", wantStatus: http.StatusNotFound},
+		{name: "invalid path", method: http.MethodGet, path: "/calendar/default/hourly", wantStatus: http.StatusNotFound},
+		{name: "nested path", method: http.MethodGet, path: "/calendar/default/hourly/runs.ics", wantStatus: http.StatusNotFound},
+		{name: "read only", method: http.MethodPost, path: "/calendar/default.ics", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			body := recorder.Body.String()
+			if events := strings.Count(body, "BEGIN:VEVENT"); events != tt.wantEvents {
+				t.Fatalf("%d events rendered, want %d:\n%s", events, tt.wantEvents, body)
+			}
+			if tt.wantEvents != 0 && !strings.Contains(body, "DESCRIPTION:Experiments: pod-delete") {
+				t.Fatalf("the experiments are not described:\n%s", body)
+			}
+			if events := strings.Count(body, "DURATION:PT15M\r\n"); events != tt.wantEvents {
+				t.Fatalf("%d events last for the default duration, want %d:\n%s", events, tt.wantEvents, body)
+			}
+		})
+	}
+}
+
+func TestGetExpectedRunDuration(t *testing.T) {
+	experiment := func(seconds string) operatorV1.ExperimentList {
+		experiment := operatorV1.ExperimentList{Name: "pod-delete"}
+		if seconds != "" {
+			experiment.Spec.Components.ENV = []corev1.EnvVar{{Name: "CHAOS_INTERVAL", Value: "10"}, {Name: "TOTAL_CHAOS_DURATION", Value: seconds}}
+		}
+		return experiment
+	}
+	engine := func(seconds ...string) operatorV1.ChaosEngineSpec {
+		spec := operatorV1.ChaosEngineSpec{}
+		for _, s := range seconds {
+			spec.Experiments = append(spec.Experiments, experiment(s))
+		}
+		return spec
+	}
+
+	tests := []struct {
+		name      string
+		spec      operatorV1.ChaosEngineSpec
+		steps     []schedulerV1.EngineTemplate
+		want      time.Duration
+		wantKnown bool
+	}{
+		{name: "single experiment", spec: engine("60"), want: time.Minute, wantKnown: true},
+		{name: "experiments one after the other", spec: engine("60", "30"), want: 90 * time.Second, wantKnown: true},
+		{name: "experiment without duration", spec: engine("60", ""), want: time.Minute, wantKnown: true},
+		{name: "no duration", spec: engine(""), wantKnown: false},
+		{name: "invalid duration", spec: engine("1m"), wantKnown: false},
+		{
+			name: "steps one after the other with a delay",
+			steps: []schedulerV1.EngineTemplate{
+				{Name: "first", Spec: engine("60")},
+				{Name: "second", Spec: engine("30"), Delay: &metav1.Duration{Duration: 5 * time.Minute}},
+			},
+			want:      6*time.Minute + 30*time.Second,
+			wantKnown: true,
+		},
+		{
+			name: "parallel steps",
+			steps: []schedulerV1.EngineTemplate{
+				{Name: "first", Spec: engine("60")},
+				{Name: "second", Spec: engine("120"), Parallel: true},
+				{Name: "third", Spec: engine("30")},
+			},
+			want:      150 * time.Second,
+			wantKnown: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := newTestSchedule("schedule", everyMinute())
+			schedule.Spec.EngineTemplateSpec = tt.spec
+			schedule.Spec.EngineTemplates = tt.steps
+
+			got, known := getExpectedRunDuration(schedule)
+			if got != tt.want || known != tt.wantKnown {
+				t.Fatalf("getExpectedRunDuration() = %v, %v, want %v, %v", got, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
@@ -19,16 +19,29 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
+
 	"github.com/go-logr/logr"
 	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/audit"
 	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+	"github.com/litmuschaos/chaos-scheduler/pkg/validation"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"time"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -42,21 +55,50 @@ type ChaosScheduleReconciler struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	client.Client
-	Scheme *runtime.Scheme
+	// APIReader reads the objects bypassing the cache, e.g. the Leases of the mutex groups, defaults to the client
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	Recorder record.EventRecorder
+	// Settings are the controller-wide defaults of the schedules
+	Settings *Settings
+	// MaxConcurrentReconciles is the maximum number of schedules reconciled at the same time
+	MaxConcurrentReconciles int
+	// RateLimiter limits the requeues of the schedules, defaults to the rate limiter of controller-runtime
+	RateLimiter ratelimiter.RateLimiter
+	// KillSwitch is the ConfigMap which pauses all the schedules while its globalPause is set
+	KillSwitch types.NamespacedName
+	// MutexLeaseNamespace is the namespace of the Leases of the mutex groups spanning the cluster
+	MutexLeaseNamespace string
+	// Clock is used for every time based decision of the scheduler, defaults to the real clock
+	Clock clock.Clock
+	// Events receives the CloudEvents of the runs and of the schedules, no event is emitted when it is nil
+	Events EventEmitter
+	// Audit records the decisions of the scheduler, no record is written when it is nil
+	Audit *audit.Logger
+	// TracerProvider provides the tracer of the spans of the reconciles and of the runs, no span is recorded when it is nil
+	TracerProvider trace.TracerProvider
 }
 
 // reconcileScheduler contains details of reconcileScheduler
 type reconcileScheduler struct {
 	r         *ChaosScheduleReconciler
 	reqLogger logr.Logger
+	// ctx holds the span of the reconcile
+	ctx context.Context
 }
 
 //+kubebuilder:rbac:groups=litmuschaos.io,resources=chaosschedules,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=litmuschaos.io,resources=chaosschedules/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=litmuschaos.io,resources=chaosschedules/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+//+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
 
 /*Reconcile reads that state of the cluster for a ChaosScheduler object and makes changes based on the state read
 and what is in the ChaosScheduler.Spec
@@ -68,8 +110,34 @@ func (r *ChaosScheduleReconciler) Reconcile(ctx context.Context, request ctrl.Re
 	reqLogger := chaosTypes.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling ChaosScheduler")
 
+	ctx, span := r.tracer().Start(ctx, "Reconcile", trace.WithAttributes(
+		attribute.String("chaosschedule.namespace", request.Namespace),
+		attribute.String("chaosschedule.name", request.Name),
+	))
+	defer span.End()
+
+	result, err := r.reconcile(ctx, request, reqLogger)
+	// the cache may lag behind the last update of the schedule, it is read again rather than waited for
+	if k8serrors.IsConflict(err) {
+		reqLogger.Info("Schedule has been modified in the meantime, requeueing")
+		span.AddEvent("conflict")
+		return reconcile.Result{Requeue: true}, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attribute.Bool("requeue", result.Requeue), attribute.String("requeueAfter", result.RequeueAfter.String()))
+	return result, err
+}
+
+// reconcile drives the schedule as per its scheduleState
+func (r *ChaosScheduleReconciler) reconcile(ctx context.Context, request reconcile.Request, reqLogger logr.Logger) (reconcile.Result, error) {
+
 	// Fetch the ChaosScheduler instance
-	scheduler, err := r.getChaosSchedulerInstance(request)
+	fetchCtx, span := r.tracer().Start(ctx, "fetch")
+	scheduler, err := r.getChaosSchedulerInstance(fetchCtx, request)
+	endSpan(span, client.IgnoreNotFound(err))
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
@@ -81,9 +149,25 @@ func (r *ChaosScheduleReconciler) Reconcile(ctx context.Context, request ctrl.Re
 		return reconcile.Result{}, err
 	}
 
+	trace.SpanFromContext(ctx).SetAttributes(getScheduleAttributes(scheduler)...)
 	schedulerReconcile := &reconcileScheduler{
 		r:         r,
 		reqLogger: reqLogger,
+		ctx:       ctx,
+	}
+
+	// the completed schedules are left as is, all the others are treated as halted while the kill switch is set
+	if !checkScheduleStatus(scheduler, schedulerV1.StatusCompleted) {
+		pause, err := r.getGlobalPause(schedulerReconcile.getContext())
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if pause.paused {
+			return schedulerReconcile.reconcileForGlobalPause(scheduler, pause)
+		}
+		if err := schedulerReconcile.resumeFromGlobalPause(scheduler); err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
 	switch scheduler.Instance.Spec.ScheduleState {
@@ -97,6 +181,10 @@ func (r *ChaosScheduleReconciler) Reconcile(ctx context.Context, request ctrl.Re
 		if !checkScheduleStatus(scheduler, schedulerV1.StatusHalted) {
 			return schedulerReconcile.reconcileForHalt(scheduler, request)
 		}
+	case schedulerV1.StateStopped:
+		if !checkScheduleStatus(scheduler, schedulerV1.StatusStopped) {
+			return schedulerReconcile.reconcileForStop(scheduler, request)
+		}
 	}
 	return reconcile.Result{}, nil
 }
@@ -104,43 +192,92 @@ func (r *ChaosScheduleReconciler) Reconcile(ctx context.Context, request ctrl.Re
 func (schedulerReconcile *reconcileScheduler) reconcileForHalt(cs *chaosTypes.SchedulerInfo, request reconcile.Request) (reconcile.Result, error) {
 
 	cs.Instance.Status.Schedule.Status = schedulerV1.StatusHalted
-	if errUpdate := schedulerReconcile.r.Client.Update(context.TODO(), cs.Instance); errUpdate != nil {
+	// the engines left running by the halt are no longer followed, their runs are recorded as aborted
+	if err := schedulerReconcile.r.finishActiveRuns(schedulerReconcile.getContext(), cs, "Aborted"); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := schedulerReconcile.r.emitHalted(schedulerReconcile.getContext(), cs); err != nil {
+		return reconcile.Result{}, err
+	}
+	if errUpdate := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); errUpdate != nil {
 		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "ScheduleHalted", "Cannot update status as halted")
 		schedulerReconcile.reqLogger.Error(errUpdate, "error updating status")
 		return reconcile.Result{}, errUpdate
 	}
 	schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "ScheduleHalted", "Schedule halted successfully")
+	schedulerReconcile.r.audit(cs, audit.Record{Decision: audit.Halt, Policy: "scheduleState/halt"})
+	return reconcile.Result{}, nil
+}
+
+// reconcileForStop stops the active engines of the schedule right away, unlike the halt which lets them finish
+func (schedulerReconcile *reconcileScheduler) reconcileForStop(cs *chaosTypes.SchedulerInfo, request reconcile.Request) (reconcile.Result, error) {
+
+	stopped, err := schedulerReconcile.r.stopActiveEngines(schedulerReconcile.getContext(), cs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if len(stopped) != 0 {
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "ScheduleStopped", "Stopped engines %s", strings.Join(stopped, ", "))
+	}
+	schedulerReconcile.r.auditStoppedEngines(cs, audit.Stop, "scheduleState/stop", "ScheduleStopped", "", stopped)
+
+	if err := schedulerReconcile.r.finishActiveRuns(schedulerReconcile.getContext(), cs, "Stopped"); err != nil {
+		return reconcile.Result{}, err
+	}
+	cs.Instance.Status.Schedule.Status = schedulerV1.StatusStopped
+	cs.Instance.Status.Schedule.EndTime = &metav1.Time{Time: schedulerReconcile.r.now()}
+	if errUpdate := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); errUpdate != nil {
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "ScheduleStopped", "Cannot update status as stopped")
+		schedulerReconcile.reqLogger.Error(errUpdate, "error updating status")
+		return reconcile.Result{}, errUpdate
+	}
+	schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "ScheduleStopped", "Schedule stopped successfully")
 	return reconcile.Result{}, nil
 }
+
 func (schedulerReconcile *reconcileScheduler) reconcileForComplete(cs *chaosTypes.SchedulerInfo, request reconcile.Request) (reconcile.Result, error) {
 
-	if len(cs.Instance.Status.Active) != 0 {
-		errUpdate := schedulerReconcile.r.updateActiveStatus(cs)
+	if len(cs.Instance.Status.Active) != 0 || isPostRunHookRunning(cs) {
+		errUpdate := schedulerReconcile.r.updateActiveStatus(schedulerReconcile.getContext(), cs)
 		if errUpdate != nil {
 			return reconcile.Result{}, errUpdate
 		}
-		return reconcile.Result{}, nil
+		if err := schedulerReconcile.updateSchedule(cs); err != nil {
+			return reconcile.Result{}, err
+		}
+		return schedulerReconcile.pollSLOGuard(cs, reconcile.Result{})
 	}
 
 	opts := client.UpdateOptions{}
 	cs.Instance.Status.Schedule.Status = schedulerV1.StatusCompleted
-	cs.Instance.Status.Schedule.EndTime = &metav1.Time{Time: time.Now()}
-	if err := schedulerReconcile.r.Client.Update(context.TODO(), cs.Instance, &opts); err != nil {
+	cs.Instance.Status.Schedule.EndTime = &metav1.Time{Time: schedulerReconcile.r.now()}
+	if err := schedulerReconcile.r.emitCompleted(schedulerReconcile.getContext(), cs); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance, &opts); err != nil {
 		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "ScheduleCompleted", "Cannot update status as completed")
 		return reconcile.Result{}, fmt.Errorf("unable to update chaosSchedule for status completed, due to error: %v", err)
 	}
 	schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "ScheduleCompleted", "Schedule completed successfully")
+	schedulerReconcile.r.audit(cs, audit.Record{Decision: audit.Complete, Policy: "scheduleState/complete"})
 	return reconcile.Result{}, nil
 }
 
 func (schedulerReconcile *reconcileScheduler) reconcileForCreationAndRunning(cs *chaosTypes.SchedulerInfo, request reconcile.Request) (reconcile.Result, error) {
 
+	// an invalid schedule is not requeued, the update which fixes it triggers the next reconcile
+	if errs := validation.ValidateChaosSchedule(cs.Instance); len(errs) != 0 {
+		schedulerReconcile.reqLogger.Info("Invalid chaosschedule", "Errors", errs.ToAggregate().Error())
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "InvalidSpec", "Invalid chaosschedule: %v", errs.ToAggregate())
+		return reconcile.Result{}, nil
+	}
+
 	reconcileRes, err := schedule(schedulerReconcile, cs, request)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	return reconcileRes, nil
+	return schedulerReconcile.pollSLOGuard(cs, reconcileRes)
 }
 
 func checkScheduleStatus(cs *chaosTypes.SchedulerInfo, status schedulerV1.ChaosStatus) bool {
@@ -148,9 +285,9 @@ func checkScheduleStatus(cs *chaosTypes.SchedulerInfo, status schedulerV1.ChaosS
 }
 
 // Fetch the ChaosScheduler instance
-func (r *ChaosScheduleReconciler) getChaosSchedulerInstance(request reconcile.Request) (*chaosTypes.SchedulerInfo, error) {
+func (r *ChaosScheduleReconciler) getChaosSchedulerInstance(ctx context.Context, request reconcile.Request) (*chaosTypes.SchedulerInfo, error) {
 	instance := &schedulerV1.ChaosSchedule{}
-	err := r.Client.Get(context.TODO(), request.NamespacedName, instance)
+	err := r.Client.Get(ctx, request.NamespacedName, instance)
 	if err != nil {
 		// Error reading the object - requeue the request.
 		return nil, err
@@ -166,5 +303,11 @@ func (r *ChaosScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&litmuschaosiov1alpha1.ChaosSchedule{}).
 		Owns(&v1alpha1.ChaosEngine{}).
+		Owns(&batchv1.Job{}).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: r.MaxConcurrentReconciles,
+			RateLimiter:             r.RateLimiter,
+		}).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}}, handler.EnqueueRequestsFromMapFunc(r.mapKillSwitchToSchedules)).
 		Complete(r)
 }
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// reconcileTimeout is the time given to the scheduler to act on a change
+const reconcileTimeout = 30 * time.Second
+
+// everyMinute is a repeat schedule whose first run is due right away
+func everyMinute() schedulerV1.Schedule {
+	return schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+		Properties: schedulerV1.ScheduleRepeatProperties{
+			MinChaosInterval: &schedulerV1.MinChaosInterval{Minute: &schedulerV1.Minute{EveryNthMinute: 1}},
+		},
+	}}
+}
+
+// waitForSchedule waits until the condition holds for the latest version of the schedule
+func waitForSchedule(t *testing.T, c client.Client, name string, condition func(*schedulerV1.ChaosSchedule) bool) *schedulerV1.ChaosSchedule {
+	schedule := &schedulerV1.ChaosSchedule{}
+	err := wait.PollImmediate(250*time.Millisecond, reconcileTimeout, func() (bool, error) {
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, schedule); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return condition(schedule), nil
+	})
+	if err != nil {
+		t.Fatalf("schedule %s did not reach the expected state, status: %+v, err: %v", name, schedule.Status, err)
+	}
+	return schedule
+}
+
+// waitForEngines waits until the schedule has at least the given number of engines
+func waitForEngines(t *testing.T, c client.Client, schedule *schedulerV1.ChaosSchedule, count int) []operatorV1.ChaosEngine {
+	var engineList operatorV1.ChaosEngineList
+	err := wait.PollImmediate(250*time.Millisecond, reconcileTimeout, func() (bool, error) {
+		err := c.List(context.TODO(), &engineList, client.InNamespace(schedule.Namespace), client.MatchingLabels{"chaosUID": string(schedule.UID)})
+		return len(engineList.Items) >= count, err
+	})
+	if err != nil {
+		t.Fatalf("schedule %s has %d engines, want %d, err: %v", schedule.Name, len(engineList.Items), count, err)
+	}
+	return engineList.Items
+}
+
+// completeEngine marks the engine as completed, the way the chaos-operator does once the experiments are over
+func completeEngine(t *testing.T, c client.Client, engine *operatorV1.ChaosEngine) {
+	engine.Status.EngineStatus = operatorV1.EngineStatusCompleted
+	engine.Status.Experiments = []operatorV1.ExperimentStatuses{{Name: "pod-delete", Verdict: "Pass"}}
+	if err := c.Update(context.TODO(), engine); err != nil {
+		t.Fatalf("unable to complete the engine, err: %v", err)
+	}
+}
+
+// updateSchedule applies the mutation to the latest version of the schedule, retrying on conflicts
+func updateSchedule(t *testing.T, c client.Client, name string, mutate func(*schedulerV1.ChaosSchedule)) {
+	err := wait.PollImmediate(100*time.Millisecond, reconcileTimeout, func() (bool, error) {
+		schedule := &schedulerV1.ChaosSchedule{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, schedule); err != nil {
+			return false, err
+		}
+		mutate(schedule)
+		err := c.Update(context.TODO(), schedule)
+		if k8serrors.IsConflict(err) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if err != nil {
+		t.Fatalf("unable to update the schedule %s, err: %v", name, err)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	c := startTestEnv(t, 1)
+
+	t.Run("now", func(t *testing.T) {
+		schedule := newTestSchedule("now", schedulerV1.Schedule{Now: true})
+		if err := c.Create(context.TODO(), schedule); err != nil {
+			t.Fatal(err)
+		}
+		engines := waitForEngines(t, c, schedule, 1)
+		waitForSchedule(t, c, "now", func(cs *schedulerV1.ChaosSchedule) bool {
+			return cs.Status.Schedule.Status == schedulerV1.StatusRunning && len(cs.Status.Active) == 1
+		})
+
+		completeEngine(t, c, &engines[0])
+		waitForSchedule(t, c, "now", func(cs *schedulerV1.ChaosSchedule) bool {
+			return cs.Status.Schedule.Status == schedulerV1.StatusCompleted && cs.Spec.ScheduleState == schedulerV1.StateCompleted
+		})
+	})
+
+	t.Run("once", func(t *testing.T) {
+		executionTime := time.Now().Add(3 * time.Second).Truncate(time.Second)
+		schedule := newTestSchedule("once", schedulerV1.Schedule{
+			Once: &schedulerV1.ScheduleOnce{ExecutionTime: metav1.Time{Time: executionTime}},
+		})
+		if err := c.Create(context.TODO(), schedule); err != nil {
+			t.Fatal(err)
+		}
+		engines := waitForEngines(t, c, schedule, 1)
+		if engines[0].CreationTimestamp.Time.Before(executionTime) {
+			t.Fatalf("engine created at %v, before the execution time %v", engines[0].CreationTimestamp, executionTime)
+		}
+	})
+
+	t.Run("repeat", func(t *testing.T) {
+		schedule := newTestSchedule("repeat", everyMinute())
+		if err := c.Create(context.TODO(), schedule); err != nil {
+			t.Fatal(err)
+		}
+		waitForEngines(t, c, schedule, 1)
+		cs := waitForSchedule(t, c, "repeat", func(cs *schedulerV1.ChaosSchedule) bool {
+			return cs.Status.Schedule.RunInstances == 1 && len(cs.Status.ActiveRuns) == 1
+		})
+		if cs.Status.LastScheduleTime == nil || cs.Status.LastScheduleLag == nil {
+			t.Fatalf("the schedule and the lag of the run are not recorded, status: %+v", cs.Status)
+		}
+	})
+
+	t.Run("halt", func(t *testing.T) {
+		schedule := newTestSchedule("halt", everyMinute())
+		if err := c.Create(context.TODO(), schedule); err != nil {
+			t.Fatal(err)
+		}
+		waitForEngines(t, c, schedule, 1)
+
+		updateSchedule(t, c, "halt", func(cs *schedulerV1.ChaosSchedule) {
+			cs.Spec.ScheduleState = schedulerV1.StateHalted
+		})
+		waitForSchedule(t, c, "halt", func(cs *schedulerV1.ChaosSchedule) bool {
+			return cs.Status.Schedule.Status == schedulerV1.StatusHalted
+		})
+	})
+
+	t.Run("complete", func(t *testing.T) {
+		repeat := everyMinute()
+		repeat.Repeat.TimeRange = &schedulerV1.TimeRange{EndTime: &metav1.Time{Time: time.Now().Add(5 * time.Second)}}
+		schedule := newTestSchedule("complete", repeat)
+		if err := c.Create(context.TODO(), schedule); err != nil {
+			t.Fatal(err)
+		}
+		waitForEngines(t, c, schedule, 1)
+
+		cs := waitForSchedule(t, c, "complete", func(cs *schedulerV1.ChaosSchedule) bool {
+			return cs.Status.Schedule.Status == schedulerV1.StatusCompleted
+		})
+		if cs.Status.Schedule.EndTime == nil || len(cs.Status.Active) != 0 {
+			t.Fatalf("the completed schedule is not cleaned up, status: %+v", cs.Status)
+		}
+	})
+
+	t.Run("deletion", func(t *testing.T) {
+		schedule := newTestSchedule("deletion", everyMinute())
+		if err := c.Create(context.TODO(), schedule); err != nil {
+			t.Fatal(err)
+		}
+		waitForEngines(t, c, schedule, 1)
+
+		if err := c.Delete(context.TODO(), schedule); err != nil {
+			t.Fatal(err)
+		}
+		err := wait.PollImmediate(250*time.Millisecond, reconcileTimeout, func() (bool, error) {
+			err := c.Get(context.TODO(), types.NamespacedName{Name: "deletion", Namespace: "default"}, &schedulerV1.ChaosSchedule{})
+			return k8serrors.IsNotFound(err), client.IgnoreNotFound(err)
+		})
+		if err != nil {
+			t.Fatalf("the schedule has not been deleted, err: %v", err)
+		}
+
+		// there is no garbage collector in the test environment, the engine of the deleted schedule is left as is
+		time.Sleep(2 * time.Second)
+		var engineList operatorV1.ChaosEngineList
+		if err := c.List(context.TODO(), &engineList, client.MatchingLabels{"chaosUID": string(schedule.UID)}); err != nil {
+			t.Fatal(err)
+		}
+		if len(engineList.Items) != 1 {
+			t.Fatalf("%d engines found after the deletion of the schedule, want 1", len(engineList.Items))
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"time"
+)
+
+// now returns the current time as per the clock of the reconciler, the real clock is used if it is not set
+func (r *ChaosScheduleReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
+
+// until returns the duration until the given time as per the clock of the reconciler
+func (r *ChaosScheduleReconciler) until(t time.Time) time.Duration {
+	return t.Sub(r.now())
+}
+
+// since returns the time elapsed since the given time as per the clock of the reconciler
+func (r *ChaosScheduleReconciler) since(t time.Time) time.Duration {
+	return r.now().Sub(t)
+}
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	configV1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// maxHarnessReconciles bounds the number of reconciles of a harness, in case the schedule never settles
+const maxHarnessReconciles = 100000
+
+// clockedClient sets the fields of the created objects which are otherwise set by the api server
+type clockedClient struct {
+	client.Client
+	clock *clocktesting.FakeClock
+}
+
+func (c *clockedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	obj.SetUID(uuid.NewUUID())
+	obj.SetCreationTimestamp(metav1.NewTime(c.clock.Now()))
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+// scheduleHarness drives a schedule through the reconciler with a fake client and a fake clock
+// The clock is moved forward to the requeues of the schedule and the engines are completed as soon as they are created
+type scheduleHarness struct {
+	t       *testing.T
+	clock   *clocktesting.FakeClock
+	r       *ChaosScheduleReconciler
+	request reconcile.Request
+}
+
+// newScheduleHarness creates the schedule at the given time, the schedules are evaluated in UTC
+func newScheduleHarness(t *testing.T, schedule *schedulerV1.ChaosSchedule, start time.Time) *scheduleHarness {
+	fakeClock := clocktesting.NewFakeClock(start)
+	r := newFakeReconciler(t)
+	settings, err := NewSettings(&configV1.ChaosSchedulerConfig{Defaults: configV1.ScheduleDefaults{TimeZone: "UTC"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Settings = settings
+	r.Recorder = &record.FakeRecorder{}
+	r.Clock = fakeClock
+	r.Client = &clockedClient{Client: r.Client, clock: fakeClock}
+
+	if err := r.Client.Create(context.TODO(), schedule); err != nil {
+		t.Fatalf("unable to create the schedule, err: %v", err)
+	}
+	// the first reconcile happens right after the creation
+	fakeClock.Step(time.Second)
+	return &scheduleHarness{
+		t:       t,
+		clock:   fakeClock,
+		r:       r,
+		request: reconcile.Request{NamespacedName: types.NamespacedName{Name: schedule.Name, Namespace: schedule.Namespace}},
+	}
+}
+
+// runUntil reconciles the schedule until the clock reaches the given time or the schedule is no longer requeued
+func (h *scheduleHarness) runUntil(end time.Time) {
+	for i := 0; i < maxHarnessReconciles; i++ {
+		result, err := h.r.Reconcile(context.TODO(), h.request)
+		if err != nil {
+			h.t.Fatalf("reconcile failed at %v, err: %v", h.clock.Now(), err)
+		}
+
+		// the completion of an engine triggers a reconcile right away
+		completed := h.completeEngines()
+		switch {
+		case result.Requeue && result.RequeueAfter <= 0, completed:
+		case result.RequeueAfter > 0:
+			if h.clock.Now().Add(result.RequeueAfter).After(end) {
+				h.clock.SetTime(end)
+				return
+			}
+			h.clock.Step(result.RequeueAfter)
+		default:
+			return
+		}
+	}
+	h.t.Fatalf("the schedule did not settle after %d reconciles", maxHarnessReconciles)
+}
+
+// completeEngines completes the running engines, the way the chaos-operator does once the experiments are over
+func (h *scheduleHarness) completeEngines() bool {
+	completed := false
+	for _, engine := range h.engines() {
+		if IsEngineFinished(&engine) {
+			continue
+		}
+		engine.Status.EngineStatus = operatorV1.EngineStatusCompleted
+		engine.Status.Experiments = []operatorV1.ExperimentStatuses{{Name: "pod-delete", Verdict: "Pass"}}
+		if err := h.r.Client.Update(context.TODO(), &engine); err != nil {
+			h.t.Fatalf("unable to complete the engine %s, err: %v", engine.Name, err)
+		}
+		completed = true
+	}
+	return completed
+}
+
+// engines returns all the engines created for the schedule
+func (h *scheduleHarness) engines() []operatorV1.ChaosEngine {
+	var engineList operatorV1.ChaosEngineList
+	if err := h.r.Client.List(context.TODO(), &engineList, client.InNamespace(h.request.Namespace)); err != nil {
+		h.t.Fatalf("unable to list the engines, err: %v", err)
+	}
+	return engineList.Items
+}
+
+// schedule returns the latest version of the schedule
+func (h *scheduleHarness) schedule() *schedulerV1.ChaosSchedule {
+	schedule := &schedulerV1.ChaosSchedule{}
+	if err := h.r.Client.Get(context.TODO(), h.request.NamespacedName, schedule); err != nil {
+		h.t.Fatalf("unable to get the schedule, err: %v", err)
+	}
+	return schedule
+}
+
+func TestFastForwardRepeatSchedule(t *testing.T) {
+	// Sunday, the 3rd of October 2021
+	start := time.Date(2021, time.October, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		repeat   schedulerV1.ScheduleRepeat
+		duration time.Duration
+		wantRuns int
+		valid    func(time.Time) bool
+	}{
+		{
+			name: "every 10 minutes for a day",
+			repeat: schedulerV1.ScheduleRepeat{Properties: schedulerV1.ScheduleRepeatProperties{
+				MinChaosInterval: &schedulerV1.MinChaosInterval{Minute: &schedulerV1.Minute{EveryNthMinute: 10}},
+			}},
+			duration: 24 * time.Hour,
+			// the first run starts right away, the following ones on the ticks
+			wantRuns: 1 + 24*6,
+			valid: func(t time.Time) bool {
+				return t.Equal(start.Add(time.Second)) || t.Minute()%10 == 0 && t.Second() == 0
+			},
+		},
+		{
+			name: "hourly during the working hours for a week",
+			repeat: schedulerV1.ScheduleRepeat{
+				Properties: schedulerV1.ScheduleRepeatProperties{
+					MinChaosInterval: &schedulerV1.MinChaosInterval{Hour: &schedulerV1.Hour{EveryNthHour: 1}},
+				},
+				WorkDays:  &schedulerV1.WorkDays{IncludedDays: "Mon-Fri"},
+				WorkHours: &schedulerV1.WorkHours{IncludedHours: "9-17"},
+			},
+			duration: 7 * 24 * time.Hour,
+			wantRuns: 5 * 9,
+			valid: func(t time.Time) bool {
+				return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday &&
+					t.Hour() >= 9 && t.Hour() <= 17 && t.Minute() == 0 && t.Second() == 0
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := newTestSchedule("fast-forward", schedulerV1.Schedule{Repeat: &tt.repeat})
+			h := newScheduleHarness(t, schedule, start)
+			h.runUntil(start.Add(tt.duration))
+
+			engines := h.engines()
+			if len(engines) != tt.wantRuns {
+				t.Fatalf("%d engines created, want %d", len(engines), tt.wantRuns)
+			}
+			for _, engine := range engines {
+				runID, err := strconv.ParseInt(engine.Labels["chaosRunID"], 10, 64)
+				if err != nil {
+					t.Fatalf("invalid run id of the engine %s, err: %v", engine.Name, err)
+				}
+				if scheduledTime := time.Unix(runID, 0).UTC(); !tt.valid(scheduledTime) {
+					t.Fatalf("engine %s scheduled at %v, outside of the schedule", engine.Name, scheduledTime)
+				}
+			}
+
+			cs := h.schedule()
+			if cs.Status.Schedule.RunInstances != tt.wantRuns {
+				t.Fatalf("runInstances = %d, want %d", cs.Status.Schedule.RunInstances, tt.wantRuns)
+			}
+			if cs.Status.LastScheduleLag == nil || cs.Status.LastScheduleLag.Duration != 0 {
+				t.Fatalf("lastScheduleLag = %v, want 0 with a fake clock", cs.Status.LastScheduleLag)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/notify"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// types of the CloudEvents emitted for the decisions of the scheduler
+const (
+	runCreatedEvent   = "io.litmuschaos.schedule.run.created"
+	runSkippedEvent   = "io.litmuschaos.schedule.run.skipped"
+	runCompletedEvent = "io.litmuschaos.schedule.run.completed"
+	haltedEvent       = "io.litmuschaos.schedule.halted"
+	completedEvent    = "io.litmuschaos.schedule.completed"
+)
+
+// EventEmitter stores the CloudEvents until they are delivered, see notify.Outbox
+// The events are emitted before the decision is persisted in the schedule, a reconcile which is retried emits them
+// again with the same id
+type EventEmitter interface {
+	Emit(ctx context.Context, event notify.CloudEvent) error
+}
+
+// scheduleEventData is the data of the CloudEvents of the scheduler
+type scheduleEventData struct {
+	Schedule scheduleMetadata `json:"schedule"`
+	RunID    string           `json:"runID,omitempty"`
+	// Engines are the engines created for the run
+	Engines       []engineReference `json:"engines,omitempty"`
+	ScheduledTime *time.Time        `json:"scheduledTime,omitempty"`
+	// ActualTime is the time at which the decision is taken, or the engines are created for the run.created events
+	ActualTime time.Time `json:"actualTime"`
+	Verdict    string    `json:"verdict,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Message    string    `json:"message,omitempty"`
+}
+
+// scheduleMetadata identifies the schedule the event is about
+type scheduleMetadata struct {
+	Namespace  string            `json:"namespace"`
+	Name       string            `json:"name"`
+	UID        types.UID         `json:"uid"`
+	Generation int64             `json:"generation"`
+	Type       string            `json:"type"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// engineReference identifies an engine of the run
+type engineReference struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+}
+
+// emitRunCreated emits the run.created event once the engines of the run are created
+func (r *ChaosScheduleReconciler) emitRunCreated(ctx context.Context, cs *chaosTypes.SchedulerInfo, runID string, scheduledTime time.Time) error {
+
+	data := scheduleEventData{RunID: runID, ScheduledTime: &scheduledTime, ActualTime: r.now()}
+	if created := cs.Instance.Status.LastEngineCreationTime; created != nil {
+		data.ActualTime = created.Time
+	}
+	for _, run := range cs.Instance.Status.ActiveRuns {
+		if run.RunID == runID {
+			data.Engines = getEngineReferences(run)
+		}
+	}
+	return r.emitEvent(ctx, cs, runCreatedEvent, runID+".run.created", runID, data)
+}
+
+// emitRunSkipped emits the run.skipped event of the run which did not pass the gates
+func (r *ChaosScheduleReconciler) emitRunSkipped(ctx context.Context, cs *chaosTypes.SchedulerInfo, scheduledTime time.Time, gate gateResult) error {
+
+	runID := getRunID(scheduledTime)
+	return r.emitEvent(ctx, cs, runSkippedEvent, runID+".run.skipped", runID, scheduleEventData{
+		RunID:         runID,
+		ScheduledTime: &scheduledTime,
+		ActualTime:    r.now(),
+		Reason:        gate.reason,
+		Message:       gate.message,
+	})
+}
+
+// emitRunCompleted emits the run.completed event of the run moved to the history
+func (r *ChaosScheduleReconciler) emitRunCompleted(ctx context.Context, cs *chaosTypes.SchedulerInfo, run schedulerV1.RunStatus) error {
+
+	data := scheduleEventData{RunID: run.RunID, Engines: getEngineReferences(run), ActualTime: r.now(), Verdict: run.Verdict}
+	if run.EndTime != nil {
+		data.ActualTime = run.EndTime.Time
+	}
+	// the run id is the unix time of the run, see getRunID
+	if seconds, err := strconv.ParseInt(run.RunID, 10, 64); err == nil {
+		scheduledTime := time.Unix(seconds, 0).UTC()
+		data.ScheduledTime = &scheduledTime
+	}
+	return r.emitEvent(ctx, cs, runCompletedEvent, run.RunID+".run.completed", run.RunID, data)
+}
+
+// emitHalted emits the halted event, each halt of the schedule is a new generation of its spec
+func (r *ChaosScheduleReconciler) emitHalted(ctx context.Context, cs *chaosTypes.SchedulerInfo) error {
+	return r.emitEvent(ctx, cs, haltedEvent, fmt.Sprintf("halted.%d", cs.Instance.Generation), "", scheduleEventData{ActualTime: r.now()})
+}
+
+// emitCompleted emits the completed event of the schedule
+func (r *ChaosScheduleReconciler) emitCompleted(ctx context.Context, cs *chaosTypes.SchedulerInfo) error {
+	return r.emitEvent(ctx, cs, completedEvent, "completed", "", scheduleEventData{ActualTime: r.now()})
+}
+
+// emitEvent emits the event about the schedule, its id is derived from the uid of the schedule and the given suffix
+// so that the same decision always has the same id
+func (r *ChaosScheduleReconciler) emitEvent(ctx context.Context, cs *chaosTypes.SchedulerInfo, eventType, idSuffix, subject string, data scheduleEventData) error {
+
+	if r.Events == nil {
+		return nil
+	}
+	data.Schedule = scheduleMetadata{
+		Namespace:  cs.Instance.Namespace,
+		Name:       cs.Instance.Name,
+		UID:        cs.Instance.UID,
+		Generation: cs.Instance.Generation,
+		Type:       getScheduleType(cs),
+		Labels:     cs.Instance.Labels,
+	}
+	return r.Events.Emit(ctx, notify.CloudEvent{
+		ID:      fmt.Sprintf("%s.%s", cs.Instance.UID, idSuffix),
+		Source:  notify.ScheduleSource(cs.Instance.Namespace, cs.Instance.Name),
+		Type:    eventType,
+		Subject: subject,
+		Time:    data.ActualTime,
+		Data:    data,
+	})
+}
+
+// getEngineReferences returns the references to the engines of the run
+func getEngineReferences(run schedulerV1.RunStatus) []engineReference {
+	var engines []engineReference
+	for _, e := range run.Engines {
+		engines = append(engines, engineReference{Namespace: e.Namespace, Name: e.Name, UID: e.UID})
+	}
+	return engines
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/notify"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// fakeEmitter records the emitted events
+type fakeEmitter struct {
+	events []notify.CloudEvent
+}
+
+func (f *fakeEmitter) Emit(ctx context.Context, event notify.CloudEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func TestFinishRunEmitsRunCompleted(t *testing.T) {
+	schedule := newHookSchedule(nil)
+	r := newFakeReconciler(t, schedule)
+	emitter := &fakeEmitter{}
+	r.Events = emitter
+
+	cs := &chaosTypes.SchedulerInfo{Instance: schedule.DeepCopy()}
+	run := newTestRun(getRunID(at(10, 0)), "a")
+	run.StartTime = &metav1.Time{Time: at(10, 0)}
+	cs.Instance.Status.ActiveRuns = []schedulerV1.RunStatus{run}
+
+	if err := r.finishRun(context.TODO(), cs, run.RunID, "Fail"); err != nil {
+		t.Fatal(err)
+	}
+	if len(emitter.events) != 1 {
+		t.Fatalf("emitted %d events, want 1", len(emitter.events))
+	}
+	event := emitter.events[0]
+	if event.Type != runCompletedEvent || event.ID != "schedule-uid."+run.RunID+".run.completed" || event.Subject != run.RunID {
+		t.Fatalf("emitted %+v", event)
+	}
+	data := event.Data.(scheduleEventData)
+	if data.Verdict != "Fail" || len(data.Engines) != 1 || data.Engines[0].Name != "engine-a" {
+		t.Fatalf("data = %+v", data)
+	}
+	if data.ScheduledTime == nil || !data.ScheduledTime.Equal(at(10, 0)) {
+		t.Fatalf("scheduledTime = %v, want %v", data.ScheduledTime, at(10, 0))
+	}
+	if data.Schedule.Name != "schedule" || data.Schedule.UID != "schedule-uid" {
+		t.Fatalf("schedule = %+v", data.Schedule)
+	}
+}
+
+func TestHoldRunEmitsRunSkipped(t *testing.T) {
+	schedule := newHookSchedule(nil)
+	s := &reconcileScheduler{r: newFakeReconciler(t, schedule), reqLogger: chaosTypes.Log}
+	emitter := &fakeEmitter{}
+	s.r.Events = emitter
+	cs := &chaosTypes.SchedulerInfo{Instance: schedule.DeepCopy()}
+	if err := s.r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, cs.Instance); err != nil {
+		t.Fatal(err)
+	}
+
+	// the deferred runs are evaluated again, only the skipped ones are decided
+	deferred := gateResult{reason: "MutexGroupBusy", message: "engine default/other is active"}
+	if _, err := s.holdRun(cs, at(10, 0), deferred, reconcile.Request{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(emitter.events) != 0 {
+		t.Fatalf("emitted %+v for the deferred run", emitter.events)
+	}
+
+	skipped := gateResult{skip: true, reason: "MutexGroupBusy", message: "engine default/other is active"}
+	if _, err := s.holdRun(cs, at(10, 0), skipped, reconcile.Request{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(emitter.events) != 1 || emitter.events[0].Type != runSkippedEvent {
+		t.Fatalf("emitted %+v, want the run.skipped event", emitter.events)
+	}
+	if data := emitter.events[0].Data.(scheduleEventData); data.Reason != "MutexGroupBusy" || data.RunID != getRunID(at(10, 0)) {
+		t.Fatalf("data = %+v", data)
+	}
+}
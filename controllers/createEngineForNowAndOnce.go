@@ -1,7 +1,7 @@
 package controllers
 
 import (
-	"context"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -16,48 +16,74 @@ import (
 
 func (schedulerReconcile *reconcileScheduler) createForNowAndOnce(cs *chaosTypes.SchedulerInfo, request reconcile.Request) (reconcile.Result, error) {
 
-	err := schedulerReconcile.r.updateActiveStatus(cs)
+	err := schedulerReconcile.r.updateActiveStatus(schedulerReconcile.getContext(), cs)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	if errUpdate := schedulerReconcile.r.Client.Update(context.TODO(), cs.Instance); errUpdate != nil {
+	if errUpdate := schedulerReconcile.updateSchedule(cs); errUpdate != nil {
 		return reconcile.Result{}, errUpdate
 	}
 
-	currentTime := metav1.Now()
-	engine := &operatorV1.ChaosEngine{}
-	err = schedulerReconcile.r.Client.Get(context.TODO(), types.NamespacedName{Name: cs.Instance.Name, Namespace: cs.Instance.Namespace}, engine)
+	if len(cs.Instance.Spec.EngineTemplates) != 0 {
+		return schedulerReconcile.createWorkflowForNowAndOnce(cs, request)
+	}
+
+	currentTime := metav1.NewTime(schedulerReconcile.r.now())
+	scheduledTime := getNowAndOnceScheduledTime(cs)
+	runID := getRunID(scheduledTime)
+	engine, err := schedulerReconcile.getNowAndOnceEngine(cs, scheduledTime)
 	if err != nil && k8serrors.IsNotFound(err) {
-		schedulerReconcile.reqLogger.Info("Creating a new engine", "Pod.Namespace", cs.Instance.Name, "Pod.Name", cs.Instance.Namespace)
+		gate, errGate := schedulerReconcile.evaluateRunGates(cs)
+		if errGate != nil {
+			return reconcile.Result{}, errGate
+		}
+		if !gate.passed {
+			return schedulerReconcile.holdRun(cs, scheduledTime, gate, request)
+		}
+		gate, errGate = schedulerReconcile.checkPreRunHook(cs, scheduledTime)
+		if errGate != nil {
+			return reconcile.Result{}, errGate
+		}
+		if !gate.passed {
+			return schedulerReconcile.holdRun(cs, scheduledTime, gate, request)
+		}
 
-		engine, err = schedulerReconcile.r.getEngineFromTemplate(cs)
+		engine, err = schedulerReconcile.r.getEngineFromTemplate(schedulerReconcile.getContext(), cs)
 		if err != nil {
 			schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "FailedCreate", "Failed to add controller references: %v", err)
 			return reconcile.Result{}, err
 		}
+		engine.Name = getEngineName(cs, scheduledTime)
+		engine.Labels["chaosRunID"] = runID
 
-		if err = schedulerReconcile.r.Client.Create(context.TODO(), engine); err != nil {
-			schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "FailedCreate", "Error creating engine: %v", err)
-			return reconcile.Result{}, err
+		schedulerReconcile.reqLogger.Info("Creating a new engine", "ChaosEngine.Namespace", engine.Namespace, "ChaosEngine.Name", engine.Name)
+		adopted, errCreate := schedulerReconcile.createOrAdoptEngine(cs, engine, runID)
+		if errCreate != nil {
+			schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "FailedCreate", "Error creating engine: %v", errCreate)
+			return reconcile.Result{}, errCreate
 		}
-		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "SuccessfulCreate", "Created engine %v", engine.Name)
-		cs.Instance.Spec.ScheduleState = schedulerV1.StateActive
-		cs.Instance.Status.Schedule.Status = schedulerV1.StatusRunning
-		cs.Instance.Status.Schedule.StartTime = &currentTime
-		cs.Instance.Status.LastScheduleTime = &currentTime
-		ref, errRef := schedulerReconcile.r.getRef(engine)
-		if errRef != nil {
-			return reconcile.Result{}, errRef
+		startTime := currentTime
+		if adopted {
+			startTime = engine.CreationTimestamp
+		} else {
+			schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "SuccessfulCreate", "Created engine %v", engine.Name)
 		}
-		cs.Instance.Status.Active = append(cs.Instance.Status.Active, *ref)
-		if err := schedulerReconcile.r.Client.Update(context.TODO(), cs.Instance); err != nil {
+		if err := schedulerReconcile.recordNowAndOnceRun(cs, engine, runID, scheduledTime, startTime); err != nil {
 			return reconcile.Result{}, err
 		}
-		schedulerReconcile.reqLogger.Info("Engine created successfully")
+		schedulerReconcile.reqLogger.Info("Engine created successfully", "Lag", cs.Instance.Status.LastScheduleLag.Duration)
 	} else if err != nil {
 		return reconcile.Result{}, err
-	} else if IsEngineFinished(engine) {
+	} else if !IsEngineFinished(engine) && !inActiveList(*cs, engine.UID) {
+		// the engine has been created by an earlier reconcile, e.g. of the former leader, whose status update was lost
+		if err := schedulerReconcile.adoptEngine(cs, engine, runID); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := schedulerReconcile.recordNowAndOnceRun(cs, engine, runID, scheduledTime, engine.CreationTimestamp); err != nil {
+			return reconcile.Result{}, err
+		}
+	} else if IsEngineFinished(engine) && !isPostRunHookRunning(cs) {
 		cs.Instance.Spec.ScheduleState = schedulerV1.StateCompleted
 		cs.Instance.Status.Schedule.EndTime = &currentTime
 		if err := schedulerReconcile.UpdateSchedulerStatus(cs, request); err != nil {
@@ -66,3 +92,51 @@ func (schedulerReconcile *reconcileScheduler) createForNowAndOnce(cs *chaosTypes
 	}
 	return reconcile.Result{}, nil
 }
+
+// getNowAndOnceEngine returns the engine of the single run of the schedule, named after its scheduled time
+// The engines created before the names were derived from the scheduled time are named after the schedule, they are
+// only returned when they belong to the schedule
+func (schedulerReconcile *reconcileScheduler) getNowAndOnceEngine(cs *chaosTypes.SchedulerInfo, scheduledTime time.Time) (*operatorV1.ChaosEngine, error) {
+
+	engine := &operatorV1.ChaosEngine{}
+	err := schedulerReconcile.r.Client.Get(schedulerReconcile.getContext(), types.NamespacedName{Name: getEngineName(cs, scheduledTime), Namespace: cs.Instance.Namespace}, engine)
+	if !k8serrors.IsNotFound(err) {
+		return engine, err
+	}
+	legacy := &operatorV1.ChaosEngine{}
+	if errLegacy := schedulerReconcile.r.Client.Get(schedulerReconcile.getContext(), types.NamespacedName{Name: cs.Instance.Name, Namespace: cs.Instance.Namespace}, legacy); errLegacy != nil {
+		if k8serrors.IsNotFound(errLegacy) {
+			return engine, err
+		}
+		return engine, errLegacy
+	}
+	if legacy.Labels["chaosUID"] != string(cs.Instance.UID) {
+		return engine, err
+	}
+	return legacy, nil
+}
+
+// recordNowAndOnceRun records the run of the created or adopted engine in the status of the schedule
+func (schedulerReconcile *reconcileScheduler) recordNowAndOnceRun(cs *chaosTypes.SchedulerInfo, engine *operatorV1.ChaosEngine, runID string, scheduledTime time.Time, startTime metav1.Time) error {
+
+	setFireTimeLag(cs, scheduledTime, startTime.Time)
+	cs.Instance.Spec.ScheduleState = schedulerV1.StateActive
+	cs.Instance.Status.Schedule.Status = schedulerV1.StatusRunning
+	cs.Instance.Status.Schedule.StartTime = &startTime
+	// the delay of the creation is recorded as the lag, the run is recorded at its scheduled time like the repeat runs
+	cs.Instance.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	ref, errRef := schedulerReconcile.r.getRef(engine)
+	if errRef != nil {
+		return errRef
+	}
+	addToActiveList(cs, runID, *ref, startTime.Time)
+	if err := schedulerReconcile.r.emitRunCreated(schedulerReconcile.getContext(), cs, runID, scheduledTime); err != nil {
+		return err
+	}
+	if err := schedulerReconcile.updateSchedule(cs); err != nil {
+		return err
+	}
+	observeFireTimeLag(cs)
+	schedulerReconcile.r.auditRunCreated(cs, runID, scheduledTime)
+	return nil
+}
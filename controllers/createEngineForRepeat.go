@@ -1,41 +1,52 @@
 package controllers
 
 import (
-	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	cron "github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/audit"
 	"github.com/litmuschaos/chaos-scheduler/pkg/types"
+	"github.com/litmuschaos/chaos-scheduler/pkg/validation"
 )
 
 func (schedulerReconcile *reconcileScheduler) createEngineRepeat(cs *types.SchedulerInfo, request reconcile.Request) (reconcile.Result, error) {
 
-	err := schedulerReconcile.r.updateActiveStatus(cs)
+	err := schedulerReconcile.r.updateActiveStatus(schedulerReconcile.getContext(), cs)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	if errUpdate := schedulerReconcile.r.Client.Update(context.TODO(), cs.Instance); errUpdate != nil {
+	if errUpdate := schedulerReconcile.updateSchedule(cs); errUpdate != nil {
 		schedulerReconcile.reqLogger.Error(errUpdate, "error updating status")
 		return reconcile.Result{}, errUpdate
 	}
 
-	timeRange := cs.Instance.Spec.Schedule.Repeat.TimeRange
+	// the steps of the current run are still being executed, the whole sequence counts as a single run
+	if isWorkflowRunning(cs) {
+		return schedulerReconcile.progressWorkflow(cs)
+	}
+
+	timeRange := getTimeRange(cs)
 	if timeRange != nil {
 		endTime := timeRange.EndTime
-		if endTime != nil && metav1.Now().After(endTime.Time) {
+		if endTime != nil && schedulerReconcile.r.now().After(endTime.Time) {
 
 			schedulerReconcile.reqLogger.Info("end time already passed", "endTime", endTime)
 
+			// the Job watch requeues the schedule once the postRun hook of the last run is done
+			if isPostRunHookRunning(cs) {
+				return reconcile.Result{}, nil
+			}
+
 			if err := schedulerReconcile.UpdateSchedulerStatus(cs, request); err != nil {
 				schedulerReconcile.reqLogger.Error(err, "error updating status")
 				return reconcile.Result{}, err
@@ -50,21 +61,42 @@ func (schedulerReconcile *reconcileScheduler) createEngineRepeat(cs *types.Sched
 		return reconcile.Result{}, nil
 	}
 
-	cronString, duration, err := schedulerReconcile.scheduleRepeat(cs)
+	cronString, err := schedulerReconcile.getRecurrence(cs)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	scheduledTime, errNew := schedulerReconcile.getRecentUnmetScheduleTime(cs, cronString)
+	_, span := schedulerReconcile.startSpan(cs, "cron evaluation", attribute.String("recurrence", cronString))
+	now := schedulerReconcile.r.now()
+	scheduledTime, errNew := schedulerReconcile.getRecentUnmetScheduleTime(cs, cronString, now)
+	// the first run which is due right away is keyed on a time which does not depend on the reconcile
+	if errNew == nil && getLastHandledTime(cs) == nil && !scheduledTime.IsZero() && !scheduledTime.After(now) {
+		scheduledTime, errNew = schedulerReconcile.getFirstRunTime(cs, cronString, now)
+	}
+	span.SetAttributes(getScheduledTimeAttribute(scheduledTime))
+	endSpan(span, errNew)
 	if errNew != nil {
 		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "FailedNeedsStart", "Cannot determine if engine needs to be started: %v", errNew)
 		return reconcile.Result{}, errNew
 	}
+	// the rrule has no more occurrences once its COUNT or UNTIL is reached, the schedule
+	// is completed as soon as its last run is finished
+	if scheduledTime.IsZero() {
+		if len(cs.Instance.Status.Active) > 0 || isPostRunHookRunning(cs) {
+			return reconcile.Result{}, nil
+		}
+		schedulerReconcile.reqLogger.Info("No more runs left in the recurrence", "Recurrence", cronString)
+		if err := schedulerReconcile.UpdateSchedulerStatus(cs, request); err != nil {
+			schedulerReconcile.reqLogger.Error(err, "error updating status")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
 
-	wait := time.Until(scheduledTime)
+	wait := schedulerReconcile.r.until(scheduledTime)
 
-	if timeRange != nil && timeRange.EndTime != nil && time.Until(timeRange.EndTime.Time) < wait {
-		return reconcile.Result{RequeueAfter: time.Until(timeRange.EndTime.Time)}, nil
+	if timeRange != nil && timeRange.EndTime != nil && schedulerReconcile.r.until(timeRange.EndTime.Time) < wait {
+		return reconcile.Result{RequeueAfter: schedulerReconcile.r.until(timeRange.EndTime.Time)}, nil
 	}
 
 	if wait > 0 {
@@ -72,36 +104,105 @@ func (schedulerReconcile *reconcileScheduler) createEngineRepeat(cs *types.Sched
 		return reconcile.Result{RequeueAfter: wait}, nil
 	}
 
-	// TODO: set the concurencyPolicy and add the  different cases to be handled
-	// For now taking "Forbid" as by default
-	if len(cs.Instance.Status.Active) > 0 {
-		schedulerReconcile.reqLogger.Info("The next scheduled is delayed as the older chaosengine is not completed yet")
-		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "MissEngine", "Missed scheduled time to start an engine because of an active engine at: %s", scheduledTime.Format(time.RFC1123Z))
-		return reconcile.Result{RequeueAfter: wait}, nil
+	if len(cs.Instance.Status.Active) > 0 || isPostRunHookRunning(cs) {
+		switch schedulerReconcile.r.getConcurrencyPolicy(cs) {
+		case schedulerV1.AllowConcurrent:
+		case schedulerV1.ReplaceConcurrent:
+			stopped, err := schedulerReconcile.r.stopActiveEngines(schedulerReconcile.getContext(), cs)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if len(stopped) != 0 {
+				schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "ReplacedEngine", "Stopped engines %s to start the run scheduled at: %s", strings.Join(stopped, ", "), scheduledTime.Format(time.RFC1123Z))
+				schedulerReconcile.r.auditStoppedEngines(cs, audit.Stop, "concurrencyPolicy/Replace", "ReplacedEngine",
+					"stopped to start the run scheduled at "+scheduledTime.Format(time.RFC3339), stopped)
+			}
+		default:
+			schedulerReconcile.reqLogger.Info("The next scheduled is delayed as the older chaosengine is not completed yet")
+			schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "MissEngine", "Missed scheduled time to start an engine because of an active engine at: %s", scheduledTime.Format(time.RFC1123Z))
+			schedulerReconcile.r.auditHeldRun(cs, audit.Defer, scheduledTime, gateResult{
+				policy:  "concurrencyPolicy/" + string(schedulerReconcile.r.getConcurrencyPolicy(cs)),
+				reason:  "MissEngine",
+				message: fmt.Sprintf("%d engines of the earlier runs are active", len(cs.Instance.Status.Active)),
+			})
+			return reconcile.Result{RequeueAfter: wait}, nil
+		}
 	}
 
-	_, err = schedulerReconcile.createNewEngine(cs, scheduledTime)
+	gate, err := schedulerReconcile.evaluateRunGates(cs)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	return reconcile.Result{RequeueAfter: duration}, nil
+	if !gate.passed {
+		return schedulerReconcile.holdRun(cs, scheduledTime, gate, request)
+	}
+
+	result, err := schedulerReconcile.createNewEngine(cs, scheduledTime, request)
+	if err != nil || !result.IsZero() {
+		// the run is held by its preRun hook
+		return result, err
+	}
+	return schedulerReconcile.requeueForNextRun(cs, cronString)
+}
+
+// requeueForNextRun requeues the schedule at the next tick of the cron, or at the end of the time range if it is earlier
+// The requeue is derived from the cron rather than the interval, so that the delay of a run does not carry over to the next one
+func (schedulerReconcile *reconcileScheduler) requeueForNextRun(cs *types.SchedulerInfo, cronString string) (reconcile.Result, error) {
+
+	cronSchedule, err := schedulerReconcile.r.parseRecurrence(cs, cronString)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	nextTime := cronSchedule.Next(schedulerReconcile.r.now())
+	if nextTime.IsZero() {
+		// the completion of the last engine triggers the reconcile which completes the schedule
+		schedulerReconcile.reqLogger.Info("No more runs left in the recurrence", "Recurrence", cronString)
+		return reconcile.Result{}, nil
+	}
+	wait := schedulerReconcile.r.until(nextTime)
+
+	timeRange := getTimeRange(cs)
+	if timeRange != nil && timeRange.EndTime != nil && schedulerReconcile.r.until(timeRange.EndTime.Time) < wait {
+		wait = schedulerReconcile.r.until(timeRange.EndTime.Time)
+	}
+	schedulerReconcile.reqLogger.Info("Next run scheduled", "Duration(seconds)", wait.Seconds())
+	return reconcile.Result{RequeueAfter: wait}, nil
 }
 
-func (schedulerReconcile *reconcileScheduler) createNewEngine(cs *types.SchedulerInfo, scheduledTime time.Time) (reconcile.Result, error) {
+// createNewEngine starts the run scheduled at the given time once its preRun hook succeeded
+func (schedulerReconcile *reconcileScheduler) createNewEngine(cs *types.SchedulerInfo, scheduledTime time.Time, request reconcile.Request) (reconcile.Result, error) {
 
-	engineReq, err := schedulerReconcile.r.getEngineFromTemplate(cs)
+	gate, err := schedulerReconcile.checkPreRunHook(cs, scheduledTime)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !gate.passed {
+		return schedulerReconcile.holdRun(cs, scheduledTime, gate, request)
+	}
+
+	if len(cs.Instance.Spec.EngineTemplates) != 0 {
+		return schedulerReconcile.createNewWorkflow(cs, scheduledTime)
+	}
+
+	engineReq, err := schedulerReconcile.r.getEngineFromTemplate(schedulerReconcile.getContext(), cs)
 	if err != nil {
 		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "FailedCreate", "Failed to add controller references: %v", err)
 		return reconcile.Result{}, err
 	}
-	engineReq.Name = fmt.Sprintf("%s-%d", cs.Instance.Name, getTimeHash(scheduledTime))
+	engineReq.Name = getEngineName(cs, scheduledTime)
+	engineReq.Labels["chaosRunID"] = getRunID(scheduledTime)
 
-	errCreate := schedulerReconcile.r.Client.Create(context.TODO(), engineReq)
-	if errCreate != nil {
+	adopted, errCreate := schedulerReconcile.createOrAdoptEngine(cs, engineReq, getRunID(scheduledTime))
+	switch {
+	case errCreate != nil:
 		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "FailedCreate", "Error creating engine: %v", errCreate)
 		return reconcile.Result{}, errCreate
+	case adopted:
+		setFireTimeLag(cs, scheduledTime, engineReq.CreationTimestamp.Time)
+	default:
+		setFireTimeLag(cs, scheduledTime, schedulerReconcile.r.now())
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "SuccessfulCreate", "Created engine %v", engineReq.Name)
 	}
-	schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "SuccessfulCreate", "Created engine %v", engineReq.Name)
 
 	// ------------------------------------------------------------------ //
 
@@ -113,20 +214,48 @@ func (schedulerReconcile *reconcileScheduler) createNewEngine(cs *types.Schedule
 	// prevent us from making the engine twice (name the engine with hash of its
 	// scheduled time).
 
-	cs.Instance.Spec.ScheduleState = schedulerV1.StateActive
-	cs.Instance.Status.Schedule.Status = schedulerV1.StatusRunning
 	ref, errRef := schedulerReconcile.r.getRef(engineReq)
 	if errRef != nil {
 		schedulerReconcile.reqLogger.Error(errRef, "Unable to make object reference for ", "engine", engineReq.Name)
 	} else {
-		cs.Instance.Status.Active = append(cs.Instance.Status.Active, *ref)
+		addToActiveList(cs, getRunID(scheduledTime), *ref, schedulerReconcile.r.now())
+	}
+
+	if err := schedulerReconcile.updateStatusForNewRun(cs, scheduledTime); err != nil {
+		return reconcile.Result{}, err
 	}
+	schedulerReconcile.reqLogger.Info("ChaosEngine has been created", "ChaosEngine Name", engineReq.Name)
+	return reconcile.Result{}, nil
+}
+
+// createNewWorkflow starts a new run of the engineTemplates for the given scheduled time
+func (schedulerReconcile *reconcileScheduler) createNewWorkflow(cs *types.SchedulerInfo, scheduledTime time.Time) (reconcile.Result, error) {
+
+	// the engines of the steps are named after the scheduled time as well, which
+	// prevents a restarted reconcile from creating the first step twice
+	if err := schedulerReconcile.startWorkflow(cs, getRunID(scheduledTime)); err != nil {
+		return reconcile.Result{}, err
+	}
+	setFireTimeLag(cs, scheduledTime, schedulerReconcile.r.now())
+
+	if err := schedulerReconcile.updateStatusForNewRun(cs, scheduledTime); err != nil {
+		return reconcile.Result{}, err
+	}
+	schedulerReconcile.reqLogger.Info("Workflow has been started", "RunID", cs.Instance.Status.Workflow.RunID)
+	return reconcile.Result{}, nil
+}
+
+// updateStatusForNewRun updates the schedule status once the engine of a new run is created
+func (schedulerReconcile *reconcileScheduler) updateStatusForNewRun(cs *types.SchedulerInfo, scheduledTime time.Time) error {
+
+	cs.Instance.Spec.ScheduleState = schedulerV1.StateActive
+	cs.Instance.Status.Schedule.Status = schedulerV1.StatusRunning
 	cs.Instance.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
 	cs.Instance.Status.Schedule.RunInstances = cs.Instance.Status.Schedule.RunInstances + 1
 
 	var startTime *metav1.Time
-	if cs.Instance.Spec.Schedule.Repeat.TimeRange != nil {
-		startTime = cs.Instance.Spec.Schedule.Repeat.TimeRange.StartTime
+	if timeRange := getTimeRange(cs); timeRange != nil {
+		startTime = timeRange.StartTime
 	}
 
 	if startTime == nil {
@@ -134,62 +263,120 @@ func (schedulerReconcile *reconcileScheduler) createNewEngine(cs *types.Schedule
 	}
 	cs.Instance.Status.Schedule.StartTime = startTime
 
-	if err := schedulerReconcile.r.Client.Update(context.TODO(), cs.Instance); err != nil {
-		return reconcile.Result{}, err
+	if err := schedulerReconcile.r.emitRunCreated(schedulerReconcile.getContext(), cs, getRunID(scheduledTime), scheduledTime); err != nil {
+		return err
 	}
-	time.Sleep(1 * time.Second)
-	schedulerReconcile.reqLogger.Info("ChaosEngine has been created", "ChaosEngine Name", engineReq.Name)
-	return reconcile.Result{}, nil
+	if err := schedulerReconcile.updateSchedule(cs); err != nil {
+		return err
+	}
+	observeFireTimeLag(cs)
+	schedulerReconcile.r.auditRunCreated(cs, getRunID(scheduledTime), scheduledTime)
+	return nil
 }
 
-func (schedulerReconcile *reconcileScheduler) getRecentUnmetScheduleTime(cs *types.SchedulerInfo, cronString string) (time.Time, error) {
+// getRecentUnmetScheduleTime returns the scheduled time of the run which is due at the given time, or of the next run
+func (schedulerReconcile *reconcileScheduler) getRecentUnmetScheduleTime(cs *types.SchedulerInfo, cronString string, now time.Time) (time.Time, error) {
 
-	now := time.Now()
-	cronSchedule, err := cron.ParseStandard(cronString)
+	now = now.In(schedulerReconcile.r.Settings.Location())
+	cronSchedule, err := schedulerReconcile.r.parseRecurrence(cs, cronString)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("unparseable schedule: %s : %s", cronString, err)
+		return time.Time{}, err
 	}
-	timeRange := cs.Instance.Spec.Schedule.Repeat.TimeRange
 	// handles all the schedules except first schedule
-	if cs.Instance.Status.LastScheduleTime != nil {
-		var previousTime *time.Time
-		earliestTime := cs.Instance.Status.LastScheduleTime.Time
-		for t := cronSchedule.Next(earliestTime); !t.After(now); t = cronSchedule.Next(t) {
-			temp := t
-			previousTime = &temp
-		}
-		if previousTime == nil {
+	if lastTime := getLastHandledTime(cs); lastTime != nil {
+		earliestTime := *lastTime
+		previousTime, found := getMostRecentTick(cronSchedule, earliestTime, now)
+		if !found {
 			return cronSchedule.Next(earliestTime), nil
 		}
 		lastUpdatedTime := cs.Instance.Status.LastScheduleCompletionTime
 		if lastUpdatedTime != nil {
-			if lastUpdatedTime.Sub(*previousTime) >= 0 {
-				return cronSchedule.Next(*previousTime), nil
+			if lastUpdatedTime.Sub(previousTime) >= 0 {
+				return cronSchedule.Next(previousTime), nil
 			}
 		}
-		return *previousTime, nil
-	} else if timeRange != nil && timeRange.StartTime != nil && !cs.Instance.GetCreationTimestamp().Time.After(timeRange.StartTime.Time) {
-		return schedulerReconcile.firstScheduleTime(cs, timeRange.StartTime.Time, cronSchedule)
-	} else {
-		return schedulerReconcile.firstScheduleTime(cs, cs.Instance.GetCreationTimestamp().Time, cronSchedule)
+		return previousTime, nil
+	}
+
+	earliestTime := getEarliestTime(cs)
+	// the rrule has neither work days nor work hours, its first run is its most recent occurrence since the earliest time
+	if cs.Instance.Spec.Schedule.RRule != nil {
+		earliestTime = earliestTime.Add(-time.Second)
+		if previousTime, found := getMostRecentTick(cronSchedule, earliestTime, now); found {
+			return previousTime, nil
+		}
+		return cronSchedule.Next(earliestTime), nil
+	}
+	return schedulerReconcile.firstScheduleTime(cs, earliestTime, now, cronSchedule)
+}
+
+// getFirstRunTime returns the time the first run, which is due at the given time, is keyed on. The engine name and the
+// run id are derived from it rather than from the time of the reconcile, so that a reconcile which does not see the status
+// update of an earlier one, e.g. of the former leader, adopts its engine instead of creating another one.
+// The key is the most recent tick since the earliest time, or the earliest time itself when no tick has passed yet,
+// which leaves no tick between the key and the time of the reconcile for the next run to catch up with
+func (schedulerReconcile *reconcileScheduler) getFirstRunTime(cs *types.SchedulerInfo, cronString string, now time.Time) (time.Time, error) {
+
+	cronSchedule, err := schedulerReconcile.r.parseRecurrence(cs, cronString)
+	if err != nil {
+		return time.Time{}, err
+	}
+	earliestTime := getEarliestTime(cs)
+	if previousTime, found := getMostRecentTick(cronSchedule, earliestTime.Add(-time.Second), now); found {
+		return previousTime, nil
+	}
+	return earliestTime, nil
+}
+
+// getEarliestTime returns the time from which the schedule runs, either its creation or the start of its time range
+func getEarliestTime(cs *types.SchedulerInfo) time.Time {
+	earliestTime := cs.Instance.GetCreationTimestamp().Time
+	if timeRange := getTimeRange(cs); timeRange != nil && timeRange.StartTime != nil && !earliestTime.After(timeRange.StartTime.Time) {
+		earliestTime = timeRange.StartTime.Time
+	}
+	return earliestTime
+}
+
+// parseCronSchedule parses the cron string in the time zone of the scheduler
+func (r *ChaosScheduleReconciler) parseCronSchedule(cronString string) (cron.Schedule, error) {
+	cronSchedule, err := cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", r.Settings.Location(), cronString))
+	if err != nil {
+		return nil, fmt.Errorf("unparseable schedule: %s : %s", cronString, err)
+	}
+	return cronSchedule, nil
+}
+
+// getLastHandledTime returns the scheduled time of the last run which has either been started or skipped
+func getLastHandledTime(cs *types.SchedulerInfo) *time.Time {
+	var lastTime *time.Time
+	if cs.Instance.Status.LastScheduleTime != nil {
+		lastTime = &cs.Instance.Status.LastScheduleTime.Time
+	}
+	skipped := cs.Instance.Status.LastSkippedRun
+	if skipped != nil && !skipped.Deferred && skipped.ScheduledTime != nil {
+		if lastTime == nil || skipped.ScheduledTime.After(*lastTime) {
+			lastTime = &skipped.ScheduledTime.Time
+		}
 	}
+	return lastTime
 }
 
 // it derive the first scheduled time for the scheduler based on the cron string
-func (schedulerReconcile *reconcileScheduler) firstScheduleTime(cs *types.SchedulerInfo, earliestTime time.Time, cronSchedule cron.Schedule) (time.Time, error) {
+// the included days and hours are checked against now, in the time zone of the schedule
+func (schedulerReconcile *reconcileScheduler) firstScheduleTime(cs *types.SchedulerInfo, earliestTime, now time.Time, cronSchedule cron.Schedule) (time.Time, error) {
 
 	schedule := cs.Instance.Spec.Schedule.Repeat
 
 	// it checks if present day is included in the includedWeekdays list
 	if schedule.WorkDays != nil && schedule.WorkDays.IncludedDays != "" {
-		isPossibleNow, err := isWeekdayPossible(schedule.WorkDays.IncludedDays)
+		isPossibleNow, err := isWeekdayPossible(schedule.WorkDays.IncludedDays, now)
 		if err != nil {
 			return time.Time{}, err
 		}
 		if !isPossibleNow {
 			nextTime := cronSchedule.Next(earliestTime)
-			if !nextTime.After(time.Now()) {
-				nextTime = time.Now()
+			if !nextTime.After(now) {
+				nextTime = now
 			}
 			return nextTime, nil
 		}
@@ -197,28 +384,28 @@ func (schedulerReconcile *reconcileScheduler) firstScheduleTime(cs *types.Schedu
 
 	// it checks if current hour is included in the includedHours list
 	if schedule.WorkHours != nil && schedule.WorkHours.IncludedHours != "" {
-		isPossibleNow, err := isHoursPossible(schedule.WorkHours.IncludedHours)
+		isPossibleNow, err := isHoursPossible(schedule.WorkHours.IncludedHours, now)
 		if err != nil {
 			return time.Time{}, err
 		}
 		if !isPossibleNow {
 			nextTime := cronSchedule.Next(earliestTime)
-			if !nextTime.After(time.Now()) {
-				nextTime = time.Now()
+			if !nextTime.After(now) {
+				nextTime = now
 			}
 			return nextTime, nil
 		}
 	}
 
-	return time.Now(), nil
+	return now, nil
 }
 
 // it checks if provided week day is listed in the includeWeekdays list
-func isWeekdayPossible(includedDays string) (bool, error) {
+func isWeekdayPossible(includedDays string, now time.Time) (bool, error) {
 	finalDays := [7]int{}
 	days := strings.Split(includedDays, ",")
 	for _, d := range days {
-		start, end, err := parseWeekdays(d)
+		start, end, err := validation.ParseWeekdays(d)
 		if err != nil {
 			return false, err
 		}
@@ -227,7 +414,7 @@ func isWeekdayPossible(includedDays string) (bool, error) {
 		}
 	}
 
-	currWeekday := time.Now().Weekday()
+	currWeekday := now.Weekday()
 	if finalDays[currWeekday] == 1 {
 		return true, nil
 	}
@@ -235,11 +422,11 @@ func isWeekdayPossible(includedDays string) (bool, error) {
 }
 
 // it checks if provided hour is included in the includedHours list
-func isHoursPossible(includedHours string) (bool, error) {
+func isHoursPossible(includedHours string, now time.Time) (bool, error) {
 	finalHours := [24]int{}
 	hours := strings.Split(includedHours, ",")
 	for _, h := range hours {
-		start, end, err := parseCronData(h)
+		start, end, err := validation.ParseRange(h)
 		if err != nil {
 			return false, err
 		}
@@ -248,62 +435,14 @@ func isHoursPossible(includedHours string) (bool, error) {
 		}
 	}
 
-	currHour := time.Now().Hour()
+	currHour := now.Hour()
 	if finalHours[currHour] == 1 {
 		return true, nil
 	}
 	return false, nil
 }
 
-// it parse the possible weekdays included in the inputs
-func parseWeekdays(data string) (int, int, error) {
-	var start, end int
-	if strings.Contains(data, "-") {
-		if len(data) <= 3 {
-			return parseCronData(data)
-		}
-		fieldRange := strings.Split(data, "-")
-		if len(fieldRange) < 2 {
-			return 0, 0, fmt.Errorf("provided the correct input range, range: %v", data)
-		}
-		start = types.WeekDays[strings.ToLower(strings.TrimSpace(fieldRange[0]))]
-		end = types.WeekDays[strings.ToLower(strings.TrimSpace(fieldRange[1]))]
-	} else {
-		if len(data) <= 1 {
-			return parseCronData(data)
-		}
-		start = types.WeekDays[strings.ToLower(data)]
-		end = types.WeekDays[strings.ToLower(data)]
-	}
-	return start, end, nil
-}
-
-// it parse the input range provided in int format
-func parseCronData(data string) (int, int, error) {
-	var start, end string
-	if strings.Contains(data, "-") {
-		fieldRange := strings.Split(data, "-")
-		if len(fieldRange) < 2 {
-			return 0, 0, fmt.Errorf("provided the correct input range, range: %v", data)
-		}
-		start = strings.TrimSpace(fieldRange[0])
-		end = strings.TrimSpace(fieldRange[1])
-	} else {
-		start = data
-		end = data
-	}
-	startInt, err := strconv.Atoi(start)
-	if err != nil {
-		return 0, 0, err
-	}
-	endInt, err := strconv.Atoi(end)
-	if err != nil {
-		return 0, 0, err
-	}
-	return startInt, endInt, nil
-}
-
-func (schedulerReconcile *reconcileScheduler) scheduleRepeat(cs *types.SchedulerInfo) (string, time.Duration, error) {
+func (schedulerReconcile *reconcileScheduler) scheduleRepeat(cs *types.SchedulerInfo) (string, error) {
 
 	/* includedDays will be given in form comma seperated
 	 * list such as 0,2,4 or Mon,Wed,Sat
@@ -330,18 +469,26 @@ func (schedulerReconcile *reconcileScheduler) scheduleRepeat(cs *types.Scheduler
 		if minChaosInterval.Minute != nil {
 			cron := fmt.Sprintf("*/%d %s * * %s", minChaosInterval.Minute.EveryNthMinute, includedHours, includedDays)
 			schedulerReconcile.reqLogger.Info("CronString formed ", "Cron String", cron)
-			return cron, time.Minute * time.Duration(minChaosInterval.Minute.EveryNthMinute), nil
+			return cron, nil
 		}
 		if minChaosInterval.Hour != nil {
 			cron := fmt.Sprintf("%d %s/%d * * %s", minChaosInterval.Hour.MinuteOfTheHour, includedHours, minChaosInterval.Hour.EveryNthHour, includedDays)
 			schedulerReconcile.reqLogger.Info("CronString formed ", "Cron String", cron)
-			return cron, time.Hour * time.Duration(minChaosInterval.Hour.EveryNthHour), nil
+			return cron, nil
 		}
 	}
-	return "", time.Duration(0), errors.New("MinChaosInterval not found")
+	return "", errors.New("MinChaosInterval not found")
 }
 
 // getTimeHash returns Unix Epoch Time
 func getTimeHash(scheduledTime time.Time) int64 {
 	return scheduledTime.Unix()
 }
+
+// getConcurrencyPolicy returns the concurrency policy of the schedule, which defaults to the one of the scheduler
+func (r *ChaosScheduleReconciler) getConcurrencyPolicy(cs *types.SchedulerInfo) schedulerV1.ConcurrencyPolicy {
+	if cs.Instance.Spec.ConcurrencyPolicy != "" {
+		return cs.Instance.Spec.ConcurrencyPolicy
+	}
+	return r.Settings.ConcurrencyPolicy()
+}
@@ -0,0 +1,409 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	cron "github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	configV1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// newTestScheduler returns a reconcileScheduler evaluating the schedules in UTC
+func newTestScheduler(t *testing.T) *reconcileScheduler {
+	settings, err := NewSettings(&configV1.ChaosSchedulerConfig{Defaults: configV1.ScheduleDefaults{TimeZone: "UTC"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &reconcileScheduler{r: &ChaosScheduleReconciler{Settings: settings}, reqLogger: chaosTypes.Log}
+}
+
+// newRepeatSchedule returns a repeat schedule created at the given time
+func newRepeatSchedule(created time.Time, repeat schedulerV1.ScheduleRepeat) *chaosTypes.SchedulerInfo {
+	return &chaosTypes.SchedulerInfo{Instance: &schedulerV1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: created}},
+		Spec:       schedulerV1.ChaosScheduleSpec{Schedule: schedulerV1.Schedule{Repeat: &repeat}},
+	}}
+}
+
+// at returns the time of the day of Wednesday, the 6th of October 2021 in UTC
+func at(hour, minute int) time.Time {
+	return time.Date(2021, time.October, 6, hour, minute, 0, 0, time.UTC)
+}
+
+func metaTime(t time.Time) *metav1.Time {
+	return &metav1.Time{Time: t}
+}
+
+func TestGetRecentUnmetScheduleTime(t *testing.T) {
+	tests := []struct {
+		name       string
+		cronString string
+		now        time.Time
+		repeat     schedulerV1.ScheduleRepeat
+		status     schedulerV1.ChaosScheduleStatus
+		want       time.Time
+		wantErr    bool
+	}{
+		{
+			name:       "first run is due right away",
+			cronString: "*/10 * * * *",
+			now:        at(10, 5),
+			want:       at(10, 5),
+		},
+		{
+			name:       "next tick after the last run",
+			cronString: "*/10 * * * *",
+			now:        at(10, 5),
+			status:     schedulerV1.ChaosScheduleStatus{LastScheduleTime: metaTime(at(10, 0))},
+			want:       at(10, 10),
+		},
+		{
+			name:       "most recent missed tick",
+			cronString: "*/10 * * * *",
+			now:        at(10, 25),
+			status:     schedulerV1.ChaosScheduleStatus{LastScheduleTime: metaTime(at(10, 0))},
+			want:       at(10, 20),
+		},
+		{
+			name:       "tick due exactly now",
+			cronString: "*/10 * * * *",
+			now:        at(10, 10),
+			status:     schedulerV1.ChaosScheduleStatus{LastScheduleTime: metaTime(at(10, 0))},
+			want:       at(10, 10),
+		},
+		{
+			name:       "missed tick already covered by a completion",
+			cronString: "*/10 * * * *",
+			now:        at(10, 25),
+			status: schedulerV1.ChaosScheduleStatus{
+				LastScheduleTime:           metaTime(at(10, 0)),
+				LastScheduleCompletionTime: metaTime(at(10, 21)),
+			},
+			want: at(10, 30),
+		},
+		{
+			name:       "skipped run counts as handled",
+			cronString: "*/10 * * * *",
+			now:        at(10, 15),
+			status: schedulerV1.ChaosScheduleStatus{
+				LastScheduleTime: metaTime(at(10, 0)),
+				LastSkippedRun:   &schedulerV1.SkippedRun{ScheduledTime: metaTime(at(10, 10))},
+			},
+			want: at(10, 20),
+		},
+		{
+			name:       "deferred run is retried",
+			cronString: "*/10 * * * *",
+			now:        at(10, 15),
+			status: schedulerV1.ChaosScheduleStatus{
+				LastScheduleTime: metaTime(at(10, 0)),
+				LastSkippedRun:   &schedulerV1.SkippedRun{ScheduledTime: metaTime(at(10, 10)), Deferred: true},
+			},
+			want: at(10, 10),
+		},
+		{
+			name:       "first run outside of the included hours",
+			cronString: "0 12-14/1 * * *",
+			now:        at(10, 5),
+			repeat:     schedulerV1.ScheduleRepeat{WorkHours: &schedulerV1.WorkHours{IncludedHours: "12-14"}},
+			want:       at(12, 0),
+		},
+		{
+			name:       "first run from the start of the time range",
+			cronString: "0 12-14/1 * * *",
+			now:        at(10, 5),
+			repeat: schedulerV1.ScheduleRepeat{
+				WorkHours: &schedulerV1.WorkHours{IncludedHours: "12-14"},
+				TimeRange: &schedulerV1.TimeRange{StartTime: metaTime(at(13, 30))},
+			},
+			want: at(14, 0),
+		},
+		{
+			name:       "invalid cron",
+			cronString: "*/10 * *",
+			now:        at(10, 5),
+			wantErr:    true,
+		},
+	}
+
+	schedulerReconcile := newTestScheduler(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := newRepeatSchedule(at(9, 0), tt.repeat)
+			cs.Instance.Status = tt.status
+
+			got, err := schedulerReconcile.getRecentUnmetScheduleTime(cs, tt.cronString, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getRecentUnmetScheduleTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Fatalf("getRecentUnmetScheduleTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFirstRunTime(t *testing.T) {
+	tests := []struct {
+		name       string
+		cronString string
+		now        time.Time
+		repeat     schedulerV1.ScheduleRepeat
+		want       time.Time
+	}{
+		{
+			name:       "most recent tick since the creation",
+			cronString: "*/10 * * * *",
+			now:        at(10, 5),
+			want:       at(10, 0),
+		},
+		{
+			name:       "tick of the creation",
+			cronString: "*/10 * * * *",
+			now:        at(9, 0).Add(time.Second),
+			want:       at(9, 0),
+		},
+		{
+			name:       "creation when no tick has passed",
+			cronString: "15 * * * *",
+			now:        at(9, 5),
+			want:       at(9, 0),
+		},
+		{
+			name:       "start of the time range when no tick has passed",
+			cronString: "15 * * * *",
+			now:        at(9, 45),
+			repeat:     schedulerV1.ScheduleRepeat{TimeRange: &schedulerV1.TimeRange{StartTime: metaTime(at(9, 30))}},
+			want:       at(9, 30),
+		},
+	}
+
+	schedulerReconcile := newTestScheduler(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := newRepeatSchedule(at(9, 0), tt.repeat)
+
+			// the reconciles of the same first run get the same key
+			for _, now := range []time.Time{tt.now, tt.now.Add(3 * time.Second)} {
+				got, err := schedulerReconcile.getFirstRunTime(cs, tt.cronString, now)
+				if err != nil {
+					t.Fatalf("getFirstRunTime() error = %v", err)
+				}
+				if !got.Equal(tt.want) {
+					t.Fatalf("getFirstRunTime() at %v = %v, want %v", now, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFirstScheduleTime(t *testing.T) {
+	cronSchedule, err := cron.ParseStandard("0 * * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	saturday := time.Date(2021, time.October, 9, 10, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		repeat   schedulerV1.ScheduleRepeat
+		earliest time.Time
+		now      time.Time
+		want     time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "no restriction",
+			earliest: at(9, 0),
+			now:      at(10, 5),
+			want:     at(10, 5),
+		},
+		{
+			name:     "included day",
+			repeat:   schedulerV1.ScheduleRepeat{WorkDays: &schedulerV1.WorkDays{IncludedDays: "Mon-Fri"}},
+			earliest: at(9, 0),
+			now:      at(10, 5),
+			want:     at(10, 5),
+		},
+		{
+			name:     "excluded day waits for the next tick",
+			repeat:   schedulerV1.ScheduleRepeat{WorkDays: &schedulerV1.WorkDays{IncludedDays: "Mon-Fri"}},
+			earliest: saturday,
+			now:      saturday,
+			want:     time.Date(2021, time.October, 11, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "excluded day with a tick already passed",
+			repeat:   schedulerV1.ScheduleRepeat{WorkDays: &schedulerV1.WorkDays{IncludedDays: "Mon-Fri"}},
+			earliest: at(9, 30),
+			now:      saturday,
+			want:     saturday,
+		},
+		{
+			name:     "excluded hour waits for the next tick",
+			repeat:   schedulerV1.ScheduleRepeat{WorkHours: &schedulerV1.WorkHours{IncludedHours: "12-14"}},
+			earliest: at(10, 5),
+			now:      at(10, 5),
+			want:     at(11, 0),
+		},
+		{
+			name:     "included hour",
+			repeat:   schedulerV1.ScheduleRepeat{WorkHours: &schedulerV1.WorkHours{IncludedHours: "9,10"}},
+			earliest: at(9, 0),
+			now:      at(10, 5),
+			want:     at(10, 5),
+		},
+		{
+			name:     "invalid days",
+			repeat:   schedulerV1.ScheduleRepeat{WorkDays: &schedulerV1.WorkDays{IncludedDays: "1-x"}},
+			earliest: at(9, 0),
+			now:      at(10, 5),
+			wantErr:  true,
+		},
+		{
+			name:     "invalid hours",
+			repeat:   schedulerV1.ScheduleRepeat{WorkHours: &schedulerV1.WorkHours{IncludedHours: "noon"}},
+			earliest: at(9, 0),
+			now:      at(10, 5),
+			wantErr:  true,
+		},
+	}
+
+	schedulerReconcile := newTestScheduler(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := newRepeatSchedule(tt.earliest, tt.repeat)
+
+			got, err := schedulerReconcile.firstScheduleTime(cs, tt.earliest, tt.now, cronSchedule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("firstScheduleTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Fatalf("firstScheduleTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsWeekdayPossible(t *testing.T) {
+	tests := []struct {
+		includedDays string
+		now          time.Time
+		want         bool
+		wantErr      bool
+	}{
+		{includedDays: "Mon-Fri", now: at(10, 0), want: true},
+		{includedDays: "Mon-Tue", now: at(10, 0), want: false},
+		{includedDays: "0,3", now: at(10, 0), want: true},
+		{includedDays: "Mon,Wed,Sat", now: at(10, 0), want: true},
+		{includedDays: "4-6", now: at(10, 0), want: false},
+		{includedDays: "1-x", now: at(10, 0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.includedDays, func(t *testing.T) {
+			got, err := isWeekdayPossible(tt.includedDays, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("isWeekdayPossible() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("isWeekdayPossible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHoursPossible(t *testing.T) {
+	tests := []struct {
+		includedHours string
+		now           time.Time
+		want          bool
+		wantErr       bool
+	}{
+		{includedHours: "9-17", now: at(10, 0), want: true},
+		{includedHours: "9-17", now: at(18, 0), want: false},
+		{includedHours: "9,12", now: at(12, 30), want: true},
+		{includedHours: "0", now: at(0, 59), want: true},
+		{includedHours: "9-x", now: at(10, 0), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.includedHours, func(t *testing.T) {
+			got, err := isHoursPossible(tt.includedHours, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("isHoursPossible() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("isHoursPossible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequeueForNextRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		cronString string
+		now        time.Time
+		endTime    *metav1.Time
+		want       time.Duration
+	}{
+		{
+			name:       "between two ticks",
+			cronString: "*/10 * * * *",
+			now:        at(10, 3).Add(30 * time.Second),
+			want:       6*time.Minute + 30*time.Second,
+		},
+		{
+			name:       "right on a tick",
+			cronString: "*/10 * * * *",
+			now:        at(10, 10),
+			want:       10 * time.Minute,
+		},
+		{
+			name:       "next tick on the next working day",
+			cronString: "0 9-17 * * 1-5",
+			now:        at(17, 30),
+			want:       15*time.Hour + 30*time.Minute,
+		},
+		{
+			name:       "end time before the next tick",
+			cronString: "*/10 * * * *",
+			now:        at(10, 3),
+			endTime:    metaTime(at(10, 5)),
+			want:       2 * time.Minute,
+		},
+		{
+			name:       "end time after the next tick",
+			cronString: "*/10 * * * *",
+			now:        at(10, 3),
+			endTime:    metaTime(at(12, 0)),
+			want:       7 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(t)
+			s.r.Clock = clocktesting.NewFakeClock(tt.now)
+			cs := newRepeatSchedule(at(9, 0), schedulerV1.ScheduleRepeat{TimeRange: &schedulerV1.TimeRange{EndTime: tt.endTime}})
+
+			result, err := s.requeueForNextRun(cs, tt.cronString)
+			if err != nil {
+				t.Fatalf("requeueForNextRun() error = %v", err)
+			}
+			if result.RequeueAfter != tt.want || result.Requeue {
+				t.Fatalf("requeueForNextRun() = %+v, want a requeue after %v", result, tt.want)
+			}
+			// the schedule is requeued right on the tick
+			if next := tt.now.Add(result.RequeueAfter); tt.endTime == nil && (next.Minute()%10 != 0 || next.Second() != 0) {
+				t.Fatalf("requeued at %v, want a tick of the schedule", next)
+			}
+		})
+	}
+}
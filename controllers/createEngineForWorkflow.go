@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// createWorkflowForNowAndOnce runs the engineTemplates a single time for the now and once schedules
+func (schedulerReconcile *reconcileScheduler) createWorkflowForNowAndOnce(cs *chaosTypes.SchedulerInfo, request reconcile.Request) (reconcile.Result, error) {
+
+	workflow := cs.Instance.Status.Workflow
+	switch {
+	case workflow == nil:
+		gate, err := schedulerReconcile.evaluateRunGates(cs)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !gate.passed {
+			return schedulerReconcile.holdRun(cs, getNowAndOnceScheduledTime(cs), gate, request)
+		}
+		gate, err = schedulerReconcile.checkPreRunHook(cs, getNowAndOnceScheduledTime(cs))
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !gate.passed {
+			return schedulerReconcile.holdRun(cs, getNowAndOnceScheduledTime(cs), gate, request)
+		}
+
+		currentTime := metav1.NewTime(schedulerReconcile.r.now())
+		if err := schedulerReconcile.startWorkflow(cs, getRunID(getNowAndOnceScheduledTime(cs))); err != nil {
+			return reconcile.Result{}, err
+		}
+		cs.Instance.Spec.ScheduleState = schedulerV1.StateActive
+		cs.Instance.Status.Schedule.Status = schedulerV1.StatusRunning
+		cs.Instance.Status.Schedule.StartTime = &currentTime
+		cs.Instance.Status.LastScheduleTime = &metav1.Time{Time: getNowAndOnceScheduledTime(cs)}
+		setFireTimeLag(cs, getNowAndOnceScheduledTime(cs), schedulerReconcile.r.now())
+		if err := schedulerReconcile.r.emitRunCreated(schedulerReconcile.getContext(), cs, getRunID(getNowAndOnceScheduledTime(cs)), getNowAndOnceScheduledTime(cs)); err != nil {
+			return reconcile.Result{}, err
+		}
+		if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+			return reconcile.Result{}, err
+		}
+		observeFireTimeLag(cs)
+		schedulerReconcile.r.auditRunCreated(cs, getRunID(getNowAndOnceScheduledTime(cs)), getNowAndOnceScheduledTime(cs))
+		schedulerReconcile.reqLogger.Info("Workflow started successfully", "RunID", cs.Instance.Status.Workflow.RunID)
+	case workflow.Phase == schedulerV1.WorkflowRunning:
+		return schedulerReconcile.progressWorkflow(cs)
+	case isPostRunHookRunning(cs):
+		// the Job watch requeues the schedule once the postRun hook is done
+		return reconcile.Result{}, nil
+	default:
+		cs.Instance.Spec.ScheduleState = schedulerV1.StateCompleted
+		if err := schedulerReconcile.UpdateSchedulerStatus(cs, request); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// startWorkflow starts a new run of the engineTemplates by creating the engine of the first step
+func (schedulerReconcile *reconcileScheduler) startWorkflow(cs *chaosTypes.SchedulerInfo, runID string) error {
+
+	cs.Instance.Status.Workflow = &schedulerV1.WorkflowStatus{
+		RunID:       runID,
+		Phase:       schedulerV1.WorkflowRunning,
+		CurrentStep: 0,
+	}
+	return schedulerReconcile.createStageEngines(cs, 0)
+}
+
+// progressWorkflow moves the current run to the next step once the engine of the current step is finished
+func (schedulerReconcile *reconcileScheduler) progressWorkflow(cs *chaosTypes.SchedulerInfo) (reconcile.Result, error) {
+
+	workflow := cs.Instance.Status.Workflow
+	templates := cs.Instance.Spec.EngineTemplates
+
+	// the engine of the current step is still running, the engine watch requeues the schedule once it is finished
+	if len(cs.Instance.Status.Active) != 0 || cs.Instance.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if workflow.NextStepTime == nil {
+		failed, err := schedulerReconcile.recordStageVerdicts(cs)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if failed {
+			return schedulerReconcile.finishWorkflow(cs, schedulerV1.WorkflowAborted)
+		}
+		next := getStageEnd(templates, workflow.CurrentStep)
+		if next >= len(templates) {
+			return schedulerReconcile.finishWorkflow(cs, schedulerV1.WorkflowCompleted)
+		}
+		nextStepTime := schedulerReconcile.r.now()
+		if delay := templates[next].Delay; delay != nil {
+			nextStepTime = nextStepTime.Add(delay.Duration)
+		}
+		workflow.NextStepTime = &metav1.Time{Time: nextStepTime}
+	}
+
+	if wait := schedulerReconcile.r.until(workflow.NextStepTime.Time); wait > 0 {
+		if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+			return reconcile.Result{}, err
+		}
+		schedulerReconcile.reqLogger.Info("Hold on, time left to start the next step", "Duration(seconds)", wait.Seconds())
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	workflow.CurrentStep = getStageEnd(templates, workflow.CurrentStep)
+	workflow.NextStepTime = nil
+	if workflow.CurrentStep >= len(templates) {
+		return schedulerReconcile.finishWorkflow(cs, schedulerV1.WorkflowCompleted)
+	}
+	if err := schedulerReconcile.createStageEngines(cs, workflow.CurrentStep); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// finishWorkflow marks the current run as finished
+func (schedulerReconcile *reconcileScheduler) finishWorkflow(cs *chaosTypes.SchedulerInfo, phase schedulerV1.WorkflowPhase) (reconcile.Result, error) {
+
+	workflow := cs.Instance.Status.Workflow
+	workflow.Phase = phase
+	workflow.NextStepTime = nil
+	cs.Instance.Status.LastScheduleCompletionTime = &metav1.Time{Time: schedulerReconcile.r.now()}
+
+	verdict := "Pass"
+	for _, step := range workflow.Steps {
+		if step.Verdict != "Pass" {
+			verdict = "Fail"
+		}
+	}
+	if err := schedulerReconcile.r.completeRun(schedulerReconcile.getContext(), cs, workflow.RunID, verdict); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if phase == schedulerV1.WorkflowAborted {
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "WorkflowAborted", "Aborted run %s after the failure of step %d", workflow.RunID, workflow.CurrentStep)
+	} else {
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "WorkflowCompleted", "Completed all the steps of run %s", workflow.RunID)
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// recordStageVerdicts records the verdicts of the current steps and returns whether the run is to be aborted
+func (schedulerReconcile *reconcileScheduler) recordStageVerdicts(cs *chaosTypes.SchedulerInfo) (bool, error) {
+
+	workflow := cs.Instance.Status.Workflow
+	templates := cs.Instance.Spec.EngineTemplates
+
+	abort := false
+	for index := workflow.CurrentStep; index < len(workflow.Steps); index++ {
+		step := &workflow.Steps[index]
+		engine := &operatorV1.ChaosEngine{}
+		err := schedulerReconcile.r.Client.Get(schedulerReconcile.getContext(), types.NamespacedName{Name: step.Engine, Namespace: cs.Instance.Namespace}, engine)
+		switch {
+		case k8serrors.IsNotFound(err):
+			step.Verdict = "Missing"
+		case err != nil:
+			return false, err
+		default:
+			step.Verdict = getEngineVerdict(engine)
+		}
+		step.EndTime = &metav1.Time{Time: schedulerReconcile.r.now()}
+
+		// the steps may have been removed from the spec in the middle of the run
+		if step.Verdict != "Pass" && index < len(templates) && templates[index].OnFailure == schedulerV1.AbortOnFailure {
+			abort = true
+		}
+	}
+	return abort, nil
+}
+
+// createStageEngines creates the engines of the step at the given index along with the parallel steps following it
+func (schedulerReconcile *reconcileScheduler) createStageEngines(cs *chaosTypes.SchedulerInfo, start int) error {
+
+	for index := start; index < getStageEnd(cs.Instance.Spec.EngineTemplates, start); index++ {
+		if err := schedulerReconcile.createStepEngine(cs, index); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createStepEngine creates the engine of the given step, an already existing engine of the step is adopted
+func (schedulerReconcile *reconcileScheduler) createStepEngine(cs *chaosTypes.SchedulerInfo, index int) error {
+
+	workflow := cs.Instance.Status.Workflow
+	template := cs.Instance.Spec.EngineTemplates[index]
+
+	engine, err := schedulerReconcile.r.getEngineFromSpec(cs, template.Spec)
+	if err != nil {
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "FailedCreate", "Failed to add controller references: %v", err)
+		return err
+	}
+	engine.Name = fmt.Sprintf("%s-%s-%s", cs.Instance.Name, workflow.RunID, template.Name)
+	engine.Labels["chaosRunID"] = workflow.RunID
+	engine.Labels["chaosStep"] = template.Name
+
+	adopted, err := schedulerReconcile.createOrAdoptEngine(cs, engine, workflow.RunID)
+	if err != nil {
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "FailedCreate", "Error creating engine: %v", err)
+		return err
+	}
+	if !adopted {
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "SuccessfulCreate", "Created engine %v for step %v", engine.Name, template.Name)
+	}
+
+	ref, err := schedulerReconcile.r.getRef(engine)
+	if err != nil {
+		return err
+	}
+	addToActiveList(cs, workflow.RunID, *ref, schedulerReconcile.r.now())
+
+	if len(workflow.Steps) > index {
+		workflow.Steps = workflow.Steps[:index]
+	}
+	workflow.Steps = append(workflow.Steps, schedulerV1.StepStatus{
+		Name:      template.Name,
+		Engine:    engine.Name,
+		StartTime: &metav1.Time{Time: schedulerReconcile.r.now()},
+	})
+	schedulerReconcile.reqLogger.Info("ChaosEngine has been created for the step", "ChaosEngine Name", engine.Name, "Step", template.Name)
+	return nil
+}
+
+// getStageEnd returns the index following the last of the parallel steps starting at the given index
+func getStageEnd(templates []schedulerV1.EngineTemplate, start int) int {
+	end := start + 1
+	for end < len(templates) && templates[end].Parallel {
+		end++
+	}
+	return end
+}
+
+// isWorkflowRunning checks whether the steps of a run are still being executed
+func isWorkflowRunning(cs *chaosTypes.SchedulerInfo) bool {
+	return len(cs.Instance.Spec.EngineTemplates) != 0 &&
+		cs.Instance.Status.Workflow != nil &&
+		cs.Instance.Status.Workflow.Phase == schedulerV1.WorkflowRunning
+}
+
+// getEngineVerdict derives the verdict of a finished engine from the verdicts of its experiments
+func getEngineVerdict(engine *operatorV1.ChaosEngine) string {
+	if engine.Status.EngineStatus == operatorV1.EngineStatusStopped {
+		return "Stopped"
+	}
+	if len(engine.Status.Experiments) == 0 {
+		return "Awaited"
+	}
+	for _, experiment := range engine.Status.Experiments {
+		if !strings.EqualFold(experiment.Verdict, "Pass") {
+			if experiment.Verdict == "" {
+				return "Awaited"
+			}
+			return experiment.Verdict
+		}
+	}
+	return "Pass"
+}
+
+// getNowAndOnceScheduledTime returns the time at which the now and once schedules are supposed to run
+func getNowAndOnceScheduledTime(cs *chaosTypes.SchedulerInfo) time.Time {
+	if cs.Instance.Spec.Schedule.Once != nil {
+		return cs.Instance.Spec.Schedule.Once.ExecutionTime.Time
+	}
+	return cs.Instance.CreationTimestamp.Time
+}
+
+// getRunID derives the id of a run from its scheduled time
+func getRunID(scheduledTime time.Time) string {
+	return strconv.FormatInt(getTimeHash(scheduledTime), 10)
+}
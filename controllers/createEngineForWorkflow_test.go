@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// workflowTest drives the steps of a workflow schedule through the reconciler with a fake client and a fake clock
+type workflowTest struct {
+	t     *testing.T
+	clock *clocktesting.FakeClock
+	r     *ChaosScheduleReconciler
+	s     *reconcileScheduler
+	cs    *chaosTypes.SchedulerInfo
+}
+
+// newWorkflowTest creates a now schedule running the given steps
+func newWorkflowTest(t *testing.T, templates ...schedulerV1.EngineTemplate) *workflowTest {
+	schedule := newTestSchedule("schedule", schedulerV1.Schedule{Now: true})
+	schedule.UID = "schedule-uid"
+	for i := range templates {
+		templates[i].Spec = schedule.Spec.EngineTemplateSpec
+	}
+	schedule.Spec.EngineTemplates = templates
+
+	r := newFakeReconciler(t, schedule)
+	fakeClock := clocktesting.NewFakeClock(at(10, 0))
+	r.Clock = fakeClock
+	// the engines of the steps are told apart by their uids
+	r.Client = &clockedClient{Client: r.Client, clock: fakeClock}
+	w := &workflowTest{t: t, clock: fakeClock, r: r, s: &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}}
+	w.cs = &chaosTypes.SchedulerInfo{Instance: w.schedule()}
+	return w
+}
+
+// schedule returns the latest version of the schedule
+func (w *workflowTest) schedule() *schedulerV1.ChaosSchedule {
+	schedule := &schedulerV1.ChaosSchedule{}
+	if err := w.r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, schedule); err != nil {
+		w.t.Fatal(err)
+	}
+	return schedule
+}
+
+// engineNames returns the names of the engines created for the schedule
+func (w *workflowTest) engineNames() []string {
+	var engineList operatorV1.ChaosEngineList
+	if err := w.r.Client.List(context.TODO(), &engineList); err != nil {
+		w.t.Fatal(err)
+	}
+	var names []string
+	for _, engine := range engineList.Items {
+		names = append(names, engine.Name)
+	}
+	return names
+}
+
+// finishEngines finishes the active engines with the verdicts of their steps, the way the chaos-operator does, and
+// updates the active list of the schedule
+func (w *workflowTest) finishEngines(verdicts map[string]string) {
+	for _, ref := range w.cs.Instance.Status.Active {
+		engine := &operatorV1.ChaosEngine{}
+		if err := w.r.Client.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, engine); err != nil {
+			w.t.Fatal(err)
+		}
+		engine.Status.EngineStatus = operatorV1.EngineStatusCompleted
+		engine.Status.Experiments = []operatorV1.ExperimentStatuses{{Name: "pod-delete", Verdict: verdicts[engine.Labels["chaosStep"]]}}
+		if err := w.r.Client.Update(context.TODO(), engine); err != nil {
+			w.t.Fatal(err)
+		}
+	}
+	if err := w.r.updateActiveStatus(context.TODO(), w.cs); err != nil {
+		w.t.Fatalf("updateActiveStatus() error = %v", err)
+	}
+}
+
+// progress moves the workflow forward, as the reconcile following the completion of the engines does
+func (w *workflowTest) progress() reconcile.Result {
+	result, err := w.s.progressWorkflow(w.cs)
+	if err != nil {
+		w.t.Fatalf("progressWorkflow() error = %v", err)
+	}
+	return result
+}
+
+func TestWorkflowProgression(t *testing.T) {
+	w := newWorkflowTest(t,
+		schedulerV1.EngineTemplate{Name: "first"},
+		schedulerV1.EngineTemplate{Name: "second", Parallel: true},
+		schedulerV1.EngineTemplate{Name: "third", Delay: &metav1.Duration{Duration: time.Minute}},
+	)
+
+	if err := w.s.startWorkflow(w.cs, "100"); err != nil {
+		t.Fatalf("startWorkflow() error = %v", err)
+	}
+	// the parallel steps are started along with the first one
+	if names := w.engineNames(); !equalStrings(names, []string{"schedule-100-first", "schedule-100-second"}) {
+		t.Fatalf("engines = %v, want the engines of the first stage", names)
+	}
+	if err := w.r.Client.Update(context.TODO(), w.cs.Instance); err != nil {
+		t.Fatal(err)
+	}
+
+	w.finishEngines(map[string]string{"first": "Pass", "second": "Pass"})
+	if len(w.cs.Instance.Status.ActiveRuns) != 1 {
+		t.Fatalf("activeRuns = %v, want the run kept active between the steps", w.cs.Instance.Status.ActiveRuns)
+	}
+	// the next step is delayed
+	if result := w.progress(); result.RequeueAfter != time.Minute {
+		t.Fatalf("progressWorkflow() = %+v, want a requeue after the delay of the next step", result)
+	}
+	if names := w.engineNames(); len(names) != 2 {
+		t.Fatalf("engines = %v, want the next step to wait for its delay", names)
+	}
+
+	w.clock.Step(time.Minute)
+	w.progress()
+	workflow := w.cs.Instance.Status.Workflow
+	if workflow.CurrentStep != 2 || len(workflow.Steps) != 3 || workflow.Steps[0].Verdict != "Pass" || workflow.Steps[1].Verdict != "Pass" {
+		t.Fatalf("workflow = %+v, want the third step started after the first stage passed", workflow)
+	}
+	if names := w.engineNames(); !equalStrings(names, []string{"schedule-100-first", "schedule-100-second", "schedule-100-third"}) {
+		t.Fatalf("engines = %v, want the engine of the third step", names)
+	}
+
+	w.finishEngines(map[string]string{"third": "Pass"})
+	if result := w.progress(); !result.Requeue {
+		t.Fatalf("progressWorkflow() = %+v, want a requeue once the run is finished", result)
+	}
+	cs := w.schedule()
+	if cs.Status.Workflow.Phase != schedulerV1.WorkflowCompleted || len(cs.Status.ActiveRuns) != 0 {
+		t.Fatalf("workflow = %+v with active runs %v, want it completed", cs.Status.Workflow, cs.Status.ActiveRuns)
+	}
+	if len(cs.Status.History) != 1 || cs.Status.History[0].RunID != "100" || cs.Status.History[0].Verdict != "Pass" {
+		t.Fatalf("history = %+v, want the passed run", cs.Status.History)
+	}
+}
+
+func TestWorkflowFailedStep(t *testing.T) {
+	tests := []struct {
+		name        string
+		onFailure   schedulerV1.StepFailurePolicy
+		wantPhase   schedulerV1.WorkflowPhase
+		wantEngines []string
+		wantEvent   string
+	}{
+		{
+			name:        "abort",
+			onFailure:   schedulerV1.AbortOnFailure,
+			wantPhase:   schedulerV1.WorkflowAborted,
+			wantEngines: []string{"schedule-100-first"},
+			wantEvent:   "WorkflowAborted",
+		},
+		{
+			name:        "continue",
+			onFailure:   schedulerV1.ContinueOnFailure,
+			wantPhase:   schedulerV1.WorkflowCompleted,
+			wantEngines: []string{"schedule-100-first", "schedule-100-second"},
+			wantEvent:   "WorkflowCompleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newWorkflowTest(t,
+				schedulerV1.EngineTemplate{Name: "first", OnFailure: tt.onFailure},
+				schedulerV1.EngineTemplate{Name: "second"},
+			)
+			if err := w.s.startWorkflow(w.cs, "100"); err != nil {
+				t.Fatalf("startWorkflow() error = %v", err)
+			}
+			if err := w.r.Client.Update(context.TODO(), w.cs.Instance); err != nil {
+				t.Fatal(err)
+			}
+
+			w.finishEngines(map[string]string{"first": "Fail", "second": "Pass"})
+			w.progress()
+			// the next step is started right away when the run goes on
+			if w.cs.Instance.Status.Workflow.Phase == schedulerV1.WorkflowRunning {
+				w.finishEngines(map[string]string{"first": "Fail", "second": "Pass"})
+				w.progress()
+			}
+
+			if names := w.engineNames(); !equalStrings(names, tt.wantEngines) {
+				t.Fatalf("engines = %v, want %v", names, tt.wantEngines)
+			}
+			cs := w.schedule()
+			if cs.Status.Workflow.Phase != tt.wantPhase || cs.Status.Workflow.Steps[0].Verdict != "Fail" {
+				t.Fatalf("workflow = %+v, want %s after the failure of the first step", cs.Status.Workflow, tt.wantPhase)
+			}
+			// a run with a failed step fails, whatever the policy of the step
+			if len(cs.Status.History) != 1 || cs.Status.History[0].Verdict != "Fail" {
+				t.Fatalf("history = %+v, want the failed run", cs.Status.History)
+			}
+			events := getEvents(w.r)
+			if len(events) == 0 || !strings.Contains(events[len(events)-1], tt.wantEvent) {
+				t.Fatalf("events = %v, want %s", events, tt.wantEvent)
+			}
+		})
+	}
+}
+
+// TestWorkflowEngineNames reconciles the start of a run again, once the status update of the first reconcile is lost,
+// and checks that the engines named after the schedule, the run and the step are adopted rather than created twice
+func TestWorkflowEngineNames(t *testing.T) {
+	w := newWorkflowTest(t,
+		schedulerV1.EngineTemplate{Name: "first"},
+		schedulerV1.EngineTemplate{Name: "second", Parallel: true},
+	)
+	if err := w.s.startWorkflow(w.cs, "100"); err != nil {
+		t.Fatalf("startWorkflow() error = %v", err)
+	}
+
+	// the status update was lost, the next reconcile starts from the schedule stored before the run
+	w.cs = &chaosTypes.SchedulerInfo{Instance: w.schedule()}
+	if err := w.s.startWorkflow(w.cs, "100"); err != nil {
+		t.Fatalf("startWorkflow() error = %v on the re-reconcile", err)
+	}
+
+	if names := w.engineNames(); !equalStrings(names, []string{"schedule-100-first", "schedule-100-second"}) {
+		t.Fatalf("engines = %v, want a single engine per step", names)
+	}
+	steps := w.cs.Instance.Status.Workflow.Steps
+	if len(steps) != 2 || steps[0].Engine != "schedule-100-first" || steps[1].Engine != "schedule-100-second" {
+		t.Fatalf("steps = %+v, want the adopted engines", steps)
+	}
+	if len(w.cs.Instance.Status.Active) != 2 || len(w.cs.Instance.Status.ActiveRuns) != 1 {
+		t.Fatalf("active = %v and activeRuns = %v, want the adopted engines in the run", w.cs.Instance.Status.Active, w.cs.Instance.Status.ActiveRuns)
+	}
+	adopted := 0
+	for _, event := range getEvents(w.r) {
+		if strings.Contains(event, "AdoptedEngine") {
+			adopted++
+		}
+	}
+	if adopted != 2 {
+		t.Fatalf("%d engines adopted, want 2", adopted)
+	}
+}
+
+func TestGetStageEnd(t *testing.T) {
+	steps := func(parallel ...bool) []schedulerV1.EngineTemplate {
+		var templates []schedulerV1.EngineTemplate
+		for _, p := range parallel {
+			templates = append(templates, schedulerV1.EngineTemplate{Parallel: p})
+		}
+		return templates
+	}
+
+	tests := []struct {
+		name      string
+		templates []schedulerV1.EngineTemplate
+		start     int
+		want      int
+	}{
+		{name: "sequential steps", templates: steps(false, false, false), start: 0, want: 1},
+		{name: "last sequential step", templates: steps(false, false, false), start: 2, want: 3},
+		{name: "parallel group at the start", templates: steps(false, true, true, false), start: 0, want: 3},
+		{name: "parallel group at the end", templates: steps(false, false, true, true), start: 1, want: 4},
+		{name: "step following a parallel group", templates: steps(false, true, false, true), start: 2, want: 4},
+		{name: "first step flagged parallel", templates: steps(true, false), start: 0, want: 1},
+		{name: "single step", templates: steps(false), start: 0, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getStageEnd(tt.templates, tt.start); got != tt.want {
+				t.Fatalf("getStageEnd(%d) = %d, want %d", tt.start, got, tt.want)
+			}
+		})
+	}
+}
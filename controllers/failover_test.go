@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// errLeaderKilled is returned by the writes of the leader once it has been killed
+var errLeaderKilled = errors.New("leader killed")
+
+// killedLeaderClient kills the leader right after its first engine is created: the engine is stored, while none of
+// the following writes of the reconcile, e.g. the status update recording the engine, reach the api server
+type killedLeaderClient struct {
+	client.Client
+	killed bool
+}
+
+func (c *killedLeaderClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if c.killed {
+		return errLeaderKilled
+	}
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	_, c.killed = obj.(*operatorV1.ChaosEngine)
+	return nil
+}
+
+func (c *killedLeaderClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.killed {
+		return errLeaderKilled
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *killedLeaderClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if c.killed {
+		return errLeaderKilled
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *killedLeaderClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if c.killed {
+		return errLeaderKilled
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestFailoverCreatesASingleEngine(t *testing.T) {
+	start := time.Date(2021, time.October, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule *schedulerV1.ChaosSchedule
+	}{
+		{
+			name:     "now",
+			schedule: newTestSchedule("failover", schedulerV1.Schedule{Now: true}),
+		},
+		{
+			name: "once",
+			schedule: newTestSchedule("failover", schedulerV1.Schedule{
+				Once: &schedulerV1.ScheduleOnce{ExecutionTime: metav1.Time{Time: start}},
+			}),
+		},
+		{
+			name:     "repeat",
+			schedule: newTestSchedule("failover", everyMinute()),
+		},
+		{
+			name: "workflow",
+			schedule: func() *schedulerV1.ChaosSchedule {
+				schedule := newTestSchedule("failover", schedulerV1.Schedule{Now: true})
+				schedule.Spec.EngineTemplates = []schedulerV1.EngineTemplate{
+					{Name: "first", Spec: schedule.Spec.EngineTemplateSpec},
+					{Name: "second", Spec: schedule.Spec.EngineTemplateSpec},
+				}
+				return schedule
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newScheduleHarness(t, tt.schedule, start)
+			store := h.r.Client
+
+			// the leader is killed in the middle of the reconcile, once its engine is created
+			leader := *h.r
+			leader.Client = &killedLeaderClient{Client: store}
+			if _, err := leader.Reconcile(context.TODO(), h.request); !errors.Is(err, errLeaderKilled) {
+				t.Fatalf("reconcile of the leader returned %v, want it to be killed after the engine is created", err)
+			}
+			engines := h.engines()
+			if len(engines) != 1 {
+				t.Fatalf("the leader created %d engines, want 1", len(engines))
+			}
+			if cs := h.schedule(); len(cs.Status.Active) != 0 {
+				t.Fatalf("the status update of the killed leader has been stored: %+v", cs.Status)
+			}
+
+			// the new leader reconciles the same tick, a few seconds later
+			h.clock.Step(15 * time.Second)
+			for i := 0; i < 2; i++ {
+				if _, err := h.r.Reconcile(context.TODO(), h.request); err != nil {
+					t.Fatalf("reconcile of the new leader failed, err: %v", err)
+				}
+			}
+
+			engines = h.engines()
+			if len(engines) != 1 {
+				t.Fatalf("%d engines created for a single tick, want 1", len(engines))
+			}
+			cs := h.schedule()
+			if len(cs.Status.Active) != 1 || cs.Status.Active[0].UID != engines[0].UID {
+				t.Fatalf("active = %+v, want the engine %s of the killed leader", cs.Status.Active, engines[0].Name)
+			}
+			if cs.Status.Schedule.Status != schedulerV1.StatusRunning {
+				t.Fatalf("status = %s, want %s", cs.Status.Schedule.Status, schedulerV1.StatusRunning)
+			}
+			if len(cs.Status.ActiveRuns) != 1 || cs.Status.ActiveRuns[0].RunID != engines[0].Labels["chaosRunID"] {
+				t.Fatalf("activeRuns = %+v, want the run of the engine %s", cs.Status.ActiveRuns, engines[0].Name)
+			}
+		})
+	}
+}
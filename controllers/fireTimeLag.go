@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// fireTimeLag is the delay between the scheduled time of the runs and the creation of their engines
+var fireTimeLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "chaos_scheduler_fire_time_lag_seconds",
+	Help:    "Delay between the scheduled time of a run and the creation of its engine",
+	Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900},
+}, []string{"schedule_type"})
+
+func init() {
+	metrics.Registry.MustRegister(fireTimeLag)
+}
+
+// setFireTimeLag records the creation time of the engine of the run and its delay from the scheduled time
+// The lag is observed by observeFireTimeLag once the status is persisted, so that a retried run is not counted twice
+func setFireTimeLag(cs *chaosTypes.SchedulerInfo, scheduledTime, creationTime time.Time) {
+	lag := creationTime.Sub(scheduledTime)
+	if lag < 0 {
+		lag = 0
+	}
+	cs.Instance.Status.LastEngineCreationTime = &metav1.Time{Time: creationTime}
+	cs.Instance.Status.LastScheduleLag = &metav1.Duration{Duration: lag}
+}
+
+// observeFireTimeLag adds the last recorded lag of the schedule to the histogram
+func observeFireTimeLag(cs *chaosTypes.SchedulerInfo) {
+	if cs.Instance.Status.LastScheduleLag == nil {
+		return
+	}
+	fireTimeLag.WithLabelValues(getScheduleType(cs)).Observe(cs.Instance.Status.LastScheduleLag.Seconds())
+}
+
+// getScheduleType returns the type of the schedule, one of ('now', 'once', 'repeat', 'rrule')
+func getScheduleType(cs *chaosTypes.SchedulerInfo) string {
+	switch {
+	case cs.Instance.Spec.Schedule.Now:
+		return "now"
+	case cs.Instance.Spec.Schedule.Once != nil:
+		return "once"
+	case cs.Instance.Spec.Schedule.RRule != nil:
+		return "rrule"
+	}
+	return "repeat"
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// getFireTimeLagHistogram returns the samples of the fire time lag observed so far for the schedule type
+func getFireTimeLagHistogram(t *testing.T, scheduleType string) *dto.Histogram {
+	metric := &dto.Metric{}
+	if err := fireTimeLag.WithLabelValues(scheduleType).(prometheus.Histogram).Write(metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.Histogram
+}
+
+func TestSetFireTimeLag(t *testing.T) {
+	tests := []struct {
+		name          string
+		scheduledTime time.Time
+		creationTime  time.Time
+		want          time.Duration
+	}{
+		{name: "on time", scheduledTime: at(10, 0), creationTime: at(10, 0), want: 0},
+		{name: "late", scheduledTime: at(10, 0), creationTime: at(10, 0).Add(1500 * time.Millisecond), want: 1500 * time.Millisecond},
+		{name: "missed tick", scheduledTime: at(10, 0), creationTime: at(10, 7), want: 7 * time.Minute},
+		// the engine of a run is created ahead of its scheduled time when the clocks of the replicas drift
+		{name: "early is clamped", scheduledTime: at(10, 0), creationTime: at(10, 0).Add(-time.Second), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &chaosTypes.SchedulerInfo{Instance: newTestSchedule("schedule", everyMinute())}
+			setFireTimeLag(cs, tt.scheduledTime, tt.creationTime)
+
+			if lag := cs.Instance.Status.LastScheduleLag; lag == nil || lag.Duration != tt.want {
+				t.Fatalf("lastScheduleLag = %v, want %v", lag, tt.want)
+			}
+			if created := cs.Instance.Status.LastEngineCreationTime; created == nil || !created.Time.Equal(tt.creationTime) {
+				t.Fatalf("lastEngineCreationTime = %v, want %v", created, tt.creationTime)
+			}
+		})
+	}
+}
+
+func TestObserveFireTimeLag(t *testing.T) {
+	cs := &chaosTypes.SchedulerInfo{Instance: newTestSchedule("schedule", schedulerV1.Schedule{Once: &schedulerV1.ScheduleOnce{}})}
+	before := getFireTimeLagHistogram(t, "once")
+
+	// nothing is observed until the lag is recorded
+	observeFireTimeLag(cs)
+	if after := getFireTimeLagHistogram(t, "once"); after.GetSampleCount() != before.GetSampleCount() {
+		t.Fatalf("%d samples observed without any lag, want none", after.GetSampleCount()-before.GetSampleCount())
+	}
+
+	cs.Instance.Status.LastScheduleLag = &metav1.Duration{Duration: 2 * time.Second}
+	observeFireTimeLag(cs)
+	after := getFireTimeLagHistogram(t, "once")
+	if after.GetSampleCount() != before.GetSampleCount()+1 || after.GetSampleSum()-before.GetSampleSum() != 2 {
+		t.Fatalf("histogram went from %d samples summing %v to %d summing %v, want a single sample of 2s",
+			before.GetSampleCount(), before.GetSampleSum(), after.GetSampleCount(), after.GetSampleSum())
+	}
+	if getBucketCount(after, 1) != getBucketCount(before, 1) || getBucketCount(after, 2.5) != getBucketCount(before, 2.5)+1 {
+		t.Fatalf("buckets = %v, want the 2s lag counted from the 2.5s bucket", after.Bucket)
+	}
+}
+
+func TestNowAndOnceLastScheduleTime(t *testing.T) {
+	start := time.Date(2021, time.October, 3, 12, 0, 0, 0, time.UTC)
+	executionTime := start.Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		schedule  *schedulerV1.ChaosSchedule
+		reconcile time.Time
+		want      time.Time
+	}{
+		{
+			name:      "now",
+			schedule:  newTestSchedule("now", schedulerV1.Schedule{Now: true}),
+			reconcile: start.Add(3 * time.Second),
+			want:      start,
+		},
+		{
+			name: "once",
+			schedule: newTestSchedule("once", schedulerV1.Schedule{
+				Once: &schedulerV1.ScheduleOnce{ExecutionTime: metav1.Time{Time: executionTime}},
+			}),
+			reconcile: executionTime.Add(3 * time.Second),
+			want:      executionTime,
+		},
+		{
+			name: "workflow",
+			schedule: func() *schedulerV1.ChaosSchedule {
+				schedule := newTestSchedule("workflow", schedulerV1.Schedule{Now: true})
+				schedule.Spec.EngineTemplates = []schedulerV1.EngineTemplate{{Name: "first", Spec: schedule.Spec.EngineTemplateSpec}}
+				return schedule
+			}(),
+			reconcile: start.Add(3 * time.Second),
+			want:      start,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newScheduleHarness(t, tt.schedule, start)
+			h.clock.SetTime(tt.reconcile)
+			for i := 0; i < 2; i++ {
+				if _, err := h.r.Reconcile(context.TODO(), h.request); err != nil {
+					t.Fatalf("reconcile failed, err: %v", err)
+				}
+			}
+
+			// the run is recorded at its scheduled time, the delay of its creation is the lag
+			status := h.schedule().Status
+			if status.LastScheduleTime == nil || !status.LastScheduleTime.Time.Equal(tt.want) {
+				t.Fatalf("lastScheduleTime = %v, want %v", status.LastScheduleTime, tt.want)
+			}
+			if lag := status.LastScheduleLag; lag == nil || lag.Duration != 3*time.Second {
+				t.Fatalf("lastScheduleLag = %v, want 3s", lag)
+			}
+		})
+	}
+}
+
+// getBucketCount returns the cumulative count of the bucket of the histogram with the given upper bound
+func getBucketCount(histogram *dto.Histogram, upperBound float64) uint64 {
+	for _, bucket := range histogram.Bucket {
+		if bucket.GetUpperBound() == upperBound {
+			return bucket.GetCumulativeCount()
+		}
+	}
+	return 0
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	operatorScheme "github.com/litmuschaos/chaos-operator/pkg/client/clientset/versioned/scheme"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/config"
+)
+
+// startTestEnv starts a control plane with the CRDs of the schedules and the engines, along with the scheduler
+// The test is skipped when the binaries of the control plane are not available
+func startTestEnv(t *testing.T, maxConcurrentReconciles int) client.Client {
+
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, skipping the envtest")
+	}
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "deploy", "crds")},
+		CRDs:                  []apiextensionsv1.CustomResourceDefinition{chaosEngineCRD()},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("unable to start the test environment, err: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := testEnv.Stop(); err != nil {
+			t.Logf("unable to stop the test environment, err: %v", err)
+		}
+	})
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(schedulerV1.AddToScheme(scheme))
+	utilruntime.Must(operatorScheme.AddToScheme(scheme))
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: scheme, MetricsBindAddress: "0"})
+	if err != nil {
+		t.Fatalf("unable to create the manager, err: %v", err)
+	}
+	settings, err := NewSettings(config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconciler := &ChaosScheduleReconciler{
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Recorder:                mgr.GetEventRecorderFor("chaos-scheduler"),
+		Settings:                settings,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+	}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
+		t.Fatalf("unable to set up the controller, err: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := mgr.Start(ctx); err != nil {
+			t.Errorf("unable to start the manager, err: %v", err)
+		}
+	}()
+	// the manager is stopped before the control plane, the cleanups run in reverse order
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return mgr.GetClient()
+}
+
+// chaosEngineCRD is a schemaless ChaosEngine CRD, the engines are only created and never run in the tests
+func chaosEngineCRD() apiextensionsv1.CustomResourceDefinition {
+	preserveUnknownFields := true
+	return apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "chaosengines.litmuschaos.io"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "litmuschaos.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     "ChaosEngine",
+				ListKind: "ChaosEngineList",
+				Plural:   "chaosengines",
+				Singular: "chaosengine",
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1alpha1",
+					Served:  true,
+					Storage: true,
+					Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+							Type:                   "object",
+							XPreserveUnknownFields: &preserveUnknownFields,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newTestSchedule returns a schedule with a minimal engine template
+func newTestSchedule(name string, schedule schedulerV1.Schedule) *schedulerV1.ChaosSchedule {
+	return &schedulerV1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: schedulerV1.ChaosScheduleSpec{
+			Schedule: schedule,
+			EngineTemplateSpec: operatorV1.ChaosEngineSpec{
+				Appinfo: operatorV1.ApplicationParams{
+					Appns:    "default",
+					Applabel: "app=nginx",
+					AppKind:  "deployment",
+				},
+				ChaosServiceAccount: "pod-delete-sa",
+				Experiments:         []operatorV1.ExperimentList{{Name: "pod-delete"}},
+			},
+		},
+	}
+}
+
+// TestFireTimeAccuracyWith500Schedules checks that the engines of many schedules firing at the same time
+// are created close to their scheduled time
+func TestFireTimeAccuracyWith500Schedules(t *testing.T) {
+
+	const (
+		schedules = 500
+		maxLag    = 10 * time.Second
+		p99Lag    = 5 * time.Second
+	)
+	if testing.Short() {
+		t.Skip("skipping the fire time benchmark in short mode")
+	}
+	c := startTestEnv(t, 10)
+
+	// the schedules are created ahead of the fire time, so that the creation itself is not measured
+	fireTime := time.Now().Add(30 * time.Second).Truncate(time.Second)
+	for i := 0; i < schedules; i++ {
+		schedule := newTestSchedule(fmt.Sprintf("fire-time-%03d", i), schedulerV1.Schedule{
+			Once: &schedulerV1.ScheduleOnce{ExecutionTime: metav1.Time{Time: fireTime}},
+		})
+		if err := c.Create(context.TODO(), schedule); err != nil {
+			t.Fatalf("unable to create the schedule, err: %v", err)
+		}
+	}
+	if time.Now().After(fireTime) {
+		t.Fatalf("the schedules took more than 30s to be created")
+	}
+
+	var engineList operatorV1.ChaosEngineList
+	deadline := fireTime.Add(time.Minute)
+	for time.Now().Before(deadline) {
+		if err := c.List(context.TODO(), &engineList, client.InNamespace("default"), client.MatchingLabels{"app": "chaos-engine"}); err != nil {
+			t.Fatalf("unable to list the engines, err: %v", err)
+		}
+		if len(engineList.Items) >= schedules {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	if len(engineList.Items) < schedules {
+		t.Fatalf("only %d of the %d engines have been created a minute after the fire time", len(engineList.Items), schedules)
+	}
+
+	lags := make([]time.Duration, 0, len(engineList.Items))
+	for _, engine := range engineList.Items {
+		lags = append(lags, engine.CreationTimestamp.Sub(fireTime))
+	}
+	sort.Slice(lags, func(i, j int) bool { return lags[i] < lags[j] })
+	p99 := lags[len(lags)*99/100]
+	slowest := lags[len(lags)-1]
+	t.Logf("fire time lag of %d engines: median %v, p99 %v, max %v", len(lags), lags[len(lags)/2], p99, slowest)
+
+	if p99 > p99Lag || slowest > maxLag {
+		t.Fatalf("fire time lag is too high: p99 %v (limit %v), max %v (limit %v)", p99, p99Lag, slowest, maxLag)
+	}
+}
@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+const (
+	// defaultJobHookTimeout is the activeDeadlineSeconds of the hook Job when neither the hook nor the template sets it
+	defaultJobHookTimeout = 10 * time.Minute
+	// defaultWebhookTimeout is the timeout of the webhook call when it is not set
+	defaultWebhookTimeout = 10 * time.Second
+	// hookJobGracePeriod is the time after which a missing hook Job fails the hook, the cache of the Jobs may not
+	// have seen a Job created right before
+	hookJobGracePeriod = time.Minute
+
+	// preRunHook and postRunHook name the hooks in the Jobs, their labels and the webhook calls
+	preRunHook  = "pre-run"
+	postRunHook = "post-run"
+)
+
+// hookPayload is the json body of the webhook calls
+type hookPayload struct {
+	Hook      string `json:"hook"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	RunID     string `json:"runID"`
+	// Verdict of the finished run, it is only sent to the postRun hook
+	Verdict string `json:"verdict,omitempty"`
+}
+
+// checkPreRunHook runs the preRun hook of the run scheduled at the given time, the engines of the run are only
+// created once the hook succeeded. The run is deferred while the Job of the hook is running
+func (schedulerReconcile *reconcileScheduler) checkPreRunHook(cs *chaosTypes.SchedulerInfo, scheduledTime time.Time) (gateResult, error) {
+
+	hook := getPreRunHook(cs)
+	if hook == nil {
+		return gateResult{passed: true}, nil
+	}
+
+	runID := getRunID(scheduledTime)
+	status := cs.Instance.Status.PreRunHook
+	if status == nil || status.RunID != runID {
+		started, err := schedulerReconcile.r.startHook(schedulerReconcile.getContext(), cs, hook, preRunHook, runID, "")
+		if err != nil {
+			return gateResult{}, err
+		}
+		status = started
+		cs.Instance.Status.PreRunHook = status
+	} else if err := schedulerReconcile.r.refreshHookStatus(schedulerReconcile.getContext(), cs, preRunHook, status); err != nil {
+		return gateResult{}, err
+	}
+
+	switch status.Phase {
+	case schedulerV1.HookRunning:
+		// the Job watch requeues the schedule once the Job is finished
+		return gateResult{policy: "hooks.preRun", reason: "PreRunHookRunning", message: fmt.Sprintf("job %s of the preRun hook is running", status.Job)}, nil
+	case schedulerV1.HookFailed:
+		message := "preRun hook failed: " + status.Message
+		switch hook.FailurePolicy {
+		case schedulerV1.ContinueOnHookFailure:
+			return gateResult{passed: true}, nil
+		case schedulerV1.SkipOnHookFailure:
+			return gateResult{skip: true, policy: "hooks.preRun/skip", reason: "PreRunHookFailed", message: message}, nil
+		default:
+			return gateResult{abort: true, policy: "hooks.preRun/abort", reason: "PreRunHookFailed", message: message}, nil
+		}
+	}
+	return gateResult{passed: true}, nil
+}
+
+// completeRun runs the postRun hook of the run whose engines are all finished, the run is moved to the history
+// once the hook is done. The verdict is kept in the active run meanwhile
+func (r *ChaosScheduleReconciler) completeRun(ctx context.Context, cs *chaosTypes.SchedulerInfo, runID, verdict string) error {
+
+	hook := getPostRunHook(cs)
+	if hook == nil {
+		return r.finishRun(ctx, cs, runID, verdict)
+	}
+
+	status := cs.Instance.Status.PostRunHook
+	switch {
+	case status != nil && status.RunID != runID && isPostRunHookRunning(cs):
+		// the hooks are executed one run at a time, the hook of the earlier run is still running
+		setRunVerdict(cs, runID, verdict)
+		return nil
+	case status == nil || status.RunID != runID:
+		started, err := r.startHook(ctx, cs, hook, postRunHook, runID, verdict)
+		if err != nil {
+			return err
+		}
+		status = started
+		cs.Instance.Status.PostRunHook = status
+	default:
+		if err := r.refreshHookStatus(ctx, cs, postRunHook, status); err != nil {
+			return err
+		}
+	}
+
+	switch status.Phase {
+	case schedulerV1.HookRunning:
+		setRunVerdict(cs, runID, verdict)
+		return nil
+	case schedulerV1.HookFailed:
+		if hook.FailurePolicy != schedulerV1.ContinueOnHookFailure {
+			verdict = "Aborted"
+		}
+	}
+	return r.finishRun(ctx, cs, runID, verdict)
+}
+
+// startHook creates the Job of the hook or calls its webhook, the webhook is called right away and the hook is
+// finished by the time it returns
+func (r *ChaosScheduleReconciler) startHook(ctx context.Context, cs *chaosTypes.SchedulerInfo, hook *schedulerV1.RunHook, name, runID, verdict string) (*schedulerV1.HookStatus, error) {
+
+	status := &schedulerV1.HookStatus{
+		RunID:     runID,
+		Phase:     schedulerV1.HookRunning,
+		StartTime: &metav1.Time{Time: r.now()},
+	}
+
+	if hook.Job != nil {
+		job, err := r.createHookJob(ctx, cs, hook, name, runID, verdict)
+		if err != nil {
+			return nil, err
+		}
+		status.Job = job
+		return status, nil
+	}
+
+	timeout := defaultWebhookTimeout
+	if hook.Timeout != nil {
+		timeout = hook.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payload := hookPayload{
+		Hook:      name,
+		Namespace: cs.Instance.Namespace,
+		Name:      cs.Instance.Name,
+		RunID:     runID,
+		Verdict:   verdict,
+	}
+	if err := callWebhook(ctx, hook.Webhook, payload); err != nil {
+		r.finishHook(cs, name, status, schedulerV1.HookFailed, err.Error())
+	} else {
+		r.finishHook(cs, name, status, schedulerV1.HookSucceeded, "")
+	}
+	return status, nil
+}
+
+// refreshHookStatus updates the phase of the running hook from the conditions of its Job
+func (r *ChaosScheduleReconciler) refreshHookStatus(ctx context.Context, cs *chaosTypes.SchedulerInfo, name string, status *schedulerV1.HookStatus) error {
+
+	if status.Phase != schedulerV1.HookRunning {
+		return nil
+	}
+
+	job := &batchv1.Job{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: status.Job, Namespace: cs.Instance.Namespace}, job)
+	switch {
+	case k8serrors.IsNotFound(err):
+		if status.StartTime == nil || r.since(status.StartTime.Time) >= hookJobGracePeriod {
+			r.finishHook(cs, name, status, schedulerV1.HookFailed, fmt.Sprintf("job %s not found", status.Job))
+		}
+		return nil
+	case err != nil:
+		return err
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			r.finishHook(cs, name, status, schedulerV1.HookSucceeded, "")
+			return nil
+		case batchv1.JobFailed:
+			r.finishHook(cs, name, status, schedulerV1.HookFailed, fmt.Sprintf("job %s failed: %s", job.Name, condition.Message))
+			return nil
+		}
+	}
+	return nil
+}
+
+// finishHook records the outcome of the hook
+func (r *ChaosScheduleReconciler) finishHook(cs *chaosTypes.SchedulerInfo, name string, status *schedulerV1.HookStatus, phase schedulerV1.HookPhase, message string) {
+
+	status.Phase = phase
+	status.CompletionTime = &metav1.Time{Time: r.now()}
+	status.Message = message
+	if phase == schedulerV1.HookFailed {
+		r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "HookFailed", "The %s hook of run %s failed: %s", name, status.RunID, message)
+		return
+	}
+	r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "HookSucceeded", "The %s hook of run %s succeeded", name, status.RunID)
+}
+
+// createHookJob creates the Job of the hook from its template and returns its name
+// The Job is named after the run, a restarted reconcile adopts the Job it created before its status update was lost
+func (r *ChaosScheduleReconciler) createHookJob(ctx context.Context, cs *chaosTypes.SchedulerInfo, hook *schedulerV1.RunHook, name, runID, verdict string) (string, error) {
+
+	template := hook.Job
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%s-%s", cs.Instance.Name, runID, name),
+			Namespace:   cs.Instance.Namespace,
+			Labels:      map[string]string{},
+			Annotations: template.Annotations,
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	for key, value := range template.Labels {
+		job.Labels[key] = value
+	}
+	job.Labels["chaosUID"] = string(cs.Instance.UID)
+	job.Labels["chaosRunID"] = runID
+	job.Labels["chaosHook"] = name
+
+	if job.Spec.ActiveDeadlineSeconds == nil {
+		timeout := defaultJobHookTimeout
+		if hook.Timeout != nil {
+			timeout = hook.Timeout.Duration
+		}
+		seconds := int64(math.Ceil(timeout.Seconds()))
+		job.Spec.ActiveDeadlineSeconds = &seconds
+	}
+
+	podSpec := &job.Spec.Template.Spec
+	if podSpec.RestartPolicy == "" {
+		podSpec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	env := []corev1.EnvVar{
+		{Name: "CHAOS_SCHEDULE_NAME", Value: cs.Instance.Name},
+		{Name: "CHAOS_SCHEDULE_NAMESPACE", Value: cs.Instance.Namespace},
+		{Name: "CHAOS_RUN_ID", Value: runID},
+		{Name: "CHAOS_HOOK", Value: name},
+	}
+	if verdict != "" {
+		env = append(env, corev1.EnvVar{Name: "CHAOS_RUN_VERDICT", Value: verdict})
+	}
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Env = append(podSpec.Containers[i].Env, env...)
+	}
+
+	if err := controllerutil.SetControllerReference(cs.Instance, job, r.Scheme); err != nil {
+		return "", err
+	}
+
+	err := r.Client.Create(ctx, job)
+	switch {
+	case k8serrors.IsAlreadyExists(err):
+		// the Job has been created by an earlier reconcile whose status update was lost
+	case err != nil:
+		r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "FailedCreate", "Error creating job for the %s hook: %v", name, err)
+		return "", err
+	default:
+		r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "SuccessfulCreate", "Created job %v for the %s hook", job.Name, name)
+	}
+	return job.Name, nil
+}
+
+// callWebhook sends the payload to the webhook, any response other than 2xx fails the hook
+func callWebhook(ctx context.Context, webhook *schedulerV1.WebhookHook, payload hookPayload) error {
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	method := webhook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range webhook.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to call the webhook, err: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// isPostRunHookRunning checks whether the Job of the postRun hook of a finished run is still running, the schedule
+// is not completed until it is done
+func isPostRunHookRunning(cs *chaosTypes.SchedulerInfo) bool {
+	status := cs.Instance.Status.PostRunHook
+	if status == nil || status.Phase != schedulerV1.HookRunning {
+		return false
+	}
+	for _, run := range cs.Instance.Status.ActiveRuns {
+		if run.RunID == status.RunID {
+			return true
+		}
+	}
+	return false
+}
+
+// setRunVerdict records the verdict of the active run until the run is finished
+func setRunVerdict(cs *chaosTypes.SchedulerInfo, runID, verdict string) {
+	for i := range cs.Instance.Status.ActiveRuns {
+		if cs.Instance.Status.ActiveRuns[i].RunID == runID {
+			cs.Instance.Status.ActiveRuns[i].Verdict = verdict
+		}
+	}
+}
+
+func getPreRunHook(cs *chaosTypes.SchedulerInfo) *schedulerV1.RunHook {
+	if cs.Instance.Spec.Hooks == nil {
+		return nil
+	}
+	return cs.Instance.Spec.Hooks.PreRun
+}
+
+func getPostRunHook(cs *chaosTypes.SchedulerInfo) *schedulerV1.RunHook {
+	if cs.Instance.Spec.Hooks == nil {
+		return nil
+	}
+	return cs.Instance.Spec.Hooks.PostRun
+}
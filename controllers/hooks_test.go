@@ -0,0 +1,301 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// newHookSchedule returns a schedule repeated every 10 minutes with the given hooks
+func newHookSchedule(hooks *schedulerV1.RunHooks) *schedulerV1.ChaosSchedule {
+	return &schedulerV1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "schedule", Namespace: "default", UID: "schedule-uid"},
+		Spec: schedulerV1.ChaosScheduleSpec{
+			Schedule: schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+				Properties: schedulerV1.ScheduleRepeatProperties{MinChaosInterval: &schedulerV1.MinChaosInterval{Minute: &schedulerV1.Minute{EveryNthMinute: 10}}},
+			}},
+			Hooks: hooks,
+		},
+	}
+}
+
+// newHookJob returns the template of a hook Job
+func newHookJob() *batchv1.JobTemplateSpec {
+	return &batchv1.JobTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "sre"}},
+		Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "snapshot", Image: "busybox"}},
+		}}},
+	}
+}
+
+// finishJob sets the condition of the Job to the given type
+func finishJob(t *testing.T, c client.Client, name string, conditionType batchv1.JobConditionType) {
+	job := &batchv1.Job{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, job); err != nil {
+		t.Fatal(err)
+	}
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{Type: conditionType, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"})
+	if err := c.Status().Update(context.TODO(), job); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckPreRunHookWithWebhook(t *testing.T) {
+	scheduledTime := at(10, 0)
+
+	tests := []struct {
+		name      string
+		status    int
+		policy    schedulerV1.HookFailurePolicy
+		wantGate  gateResult
+		wantPhase schedulerV1.HookPhase
+	}{
+		{
+			name:      "succeeded webhook",
+			status:    http.StatusNoContent,
+			wantGate:  gateResult{passed: true},
+			wantPhase: schedulerV1.HookSucceeded,
+		},
+		{
+			name:      "failed webhook continues the run",
+			status:    http.StatusInternalServerError,
+			policy:    schedulerV1.ContinueOnHookFailure,
+			wantGate:  gateResult{passed: true},
+			wantPhase: schedulerV1.HookFailed,
+		},
+		{
+			name:      "failed webhook skips the run",
+			status:    http.StatusInternalServerError,
+			policy:    schedulerV1.SkipOnHookFailure,
+			wantGate:  gateResult{skip: true, reason: "PreRunHookFailed"},
+			wantPhase: schedulerV1.HookFailed,
+		},
+		{
+			name:      "failed webhook aborts the run by default",
+			status:    http.StatusInternalServerError,
+			wantGate:  gateResult{abort: true, reason: "PreRunHookFailed"},
+			wantPhase: schedulerV1.HookFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload hookPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Method != http.MethodPost || req.Header.Get("Authorization") != "Bearer token" {
+					t.Errorf("unexpected request %s with headers %v", req.Method, req.Header)
+				}
+				if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+					t.Error(err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			schedule := newHookSchedule(&schedulerV1.RunHooks{PreRun: &schedulerV1.RunHook{
+				Webhook:       &schedulerV1.WebhookHook{URL: server.URL, Headers: map[string]string{"Authorization": "Bearer token"}},
+				FailurePolicy: tt.policy,
+			}})
+			s := &reconcileScheduler{r: newFakeReconciler(t), reqLogger: chaosTypes.Log}
+			cs := &chaosTypes.SchedulerInfo{Instance: schedule}
+
+			gate, err := s.checkPreRunHook(cs, scheduledTime)
+			if err != nil {
+				t.Fatalf("checkPreRunHook() error = %v", err)
+			}
+			if gate.passed != tt.wantGate.passed || gate.skip != tt.wantGate.skip || gate.abort != tt.wantGate.abort || gate.reason != tt.wantGate.reason {
+				t.Fatalf("checkPreRunHook() = %+v, want %+v", gate, tt.wantGate)
+			}
+			want := hookPayload{Hook: preRunHook, Namespace: "default", Name: "schedule", RunID: getRunID(scheduledTime)}
+			if payload != want {
+				t.Fatalf("webhook payload = %+v, want %+v", payload, want)
+			}
+			status := cs.Instance.Status.PreRunHook
+			if status == nil || status.Phase != tt.wantPhase || status.RunID != want.RunID || status.CompletionTime == nil {
+				t.Fatalf("preRunHook status = %+v, want phase %s", status, tt.wantPhase)
+			}
+		})
+	}
+}
+
+func TestCheckPreRunHookWithJob(t *testing.T) {
+	scheduledTime := at(10, 0)
+	runID := getRunID(scheduledTime)
+	jobName := "schedule-" + runID + "-pre-run"
+
+	tests := []struct {
+		name      string
+		condition batchv1.JobConditionType
+		wantGate  gateResult
+	}{
+		{name: "complete job", condition: batchv1.JobComplete, wantGate: gateResult{passed: true}},
+		{name: "failed job", condition: batchv1.JobFailed, wantGate: gateResult{skip: true, reason: "PreRunHookFailed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeout := metav1.Duration{Duration: time.Minute}
+			schedule := newHookSchedule(&schedulerV1.RunHooks{PreRun: &schedulerV1.RunHook{
+				Job:           newHookJob(),
+				Timeout:       &timeout,
+				FailurePolicy: schedulerV1.SkipOnHookFailure,
+			}})
+			s := &reconcileScheduler{r: newFakeReconciler(t), reqLogger: chaosTypes.Log}
+			cs := &chaosTypes.SchedulerInfo{Instance: schedule}
+
+			// the run is deferred while the job is running
+			for i := 0; i < 2; i++ {
+				gate, err := s.checkPreRunHook(cs, scheduledTime)
+				if err != nil {
+					t.Fatalf("checkPreRunHook() error = %v", err)
+				}
+				if gate.passed || gate.skip || gate.reason != "PreRunHookRunning" {
+					t.Fatalf("checkPreRunHook() = %+v, want the run to be deferred", gate)
+				}
+			}
+
+			job := &batchv1.Job{}
+			if err := s.r.Client.Get(context.TODO(), types.NamespacedName{Name: jobName, Namespace: "default"}, job); err != nil {
+				t.Fatalf("hook job not created: %v", err)
+			}
+			if job.Labels["chaosRunID"] != runID || job.Labels["chaosHook"] != preRunHook || job.Labels["team"] != "sre" {
+				t.Fatalf("job labels = %v", job.Labels)
+			}
+			if job.Spec.ActiveDeadlineSeconds == nil || *job.Spec.ActiveDeadlineSeconds != 60 {
+				t.Fatalf("job activeDeadlineSeconds = %v, want 60", job.Spec.ActiveDeadlineSeconds)
+			}
+			if len(job.OwnerReferences) != 1 || job.OwnerReferences[0].UID != schedule.UID {
+				t.Fatalf("job ownerReferences = %v", job.OwnerReferences)
+			}
+			env := map[string]string{}
+			for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+				env[e.Name] = e.Value
+			}
+			if env["CHAOS_RUN_ID"] != runID || env["CHAOS_HOOK"] != preRunHook || env["CHAOS_SCHEDULE_NAME"] != "schedule" {
+				t.Fatalf("job env = %v", env)
+			}
+
+			finishJob(t, s.r.Client, jobName, tt.condition)
+			gate, err := s.checkPreRunHook(cs, scheduledTime)
+			if err != nil {
+				t.Fatalf("checkPreRunHook() error = %v", err)
+			}
+			if gate.passed != tt.wantGate.passed || gate.skip != tt.wantGate.skip || gate.reason != tt.wantGate.reason {
+				t.Fatalf("checkPreRunHook() = %+v, want %+v", gate, tt.wantGate)
+			}
+		})
+	}
+}
+
+func TestAbortRun(t *testing.T) {
+	schedule := newHookSchedule(nil)
+	s := &reconcileScheduler{r: newFakeReconciler(t, schedule), reqLogger: chaosTypes.Log}
+	cs := &chaosTypes.SchedulerInfo{Instance: schedule.DeepCopy()}
+	if err := s.r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, cs.Instance); err != nil {
+		t.Fatal(err)
+	}
+
+	gate := gateResult{abort: true, reason: "PreRunHookFailed", message: "preRun hook failed"}
+	result, err := s.holdRun(cs, at(10, 0), gate, reconcile.Request{})
+	if err != nil {
+		t.Fatalf("holdRun() error = %v", err)
+	}
+	if !result.Requeue {
+		t.Fatalf("holdRun() = %+v, want a requeue", result)
+	}
+
+	updated := &schedulerV1.ChaosSchedule{}
+	if err := s.r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, updated); err != nil {
+		t.Fatal(err)
+	}
+	status := updated.Status
+	if len(status.History) != 1 || status.History[0].Verdict != "Aborted" || status.History[0].RunID != getRunID(at(10, 0)) {
+		t.Fatalf("history = %+v, want the aborted run", status.History)
+	}
+	if len(status.ActiveRuns) != 0 || status.Schedule.RunInstances != 1 {
+		t.Fatalf("activeRuns = %+v, runInstances = %d", status.ActiveRuns, status.Schedule.RunInstances)
+	}
+	if status.LastScheduleTime == nil || !status.LastScheduleTime.Time.Equal(at(10, 0)) {
+		t.Fatalf("lastScheduleTime = %v, want %v", status.LastScheduleTime, at(10, 0))
+	}
+}
+
+func TestUpdateActiveStatusWithPostRunHook(t *testing.T) {
+	jobName := "schedule-100-post-run"
+
+	tests := []struct {
+		name        string
+		condition   batchv1.JobConditionType
+		policy      schedulerV1.HookFailurePolicy
+		wantVerdict string
+	}{
+		{name: "complete job", condition: batchv1.JobComplete, wantVerdict: "Pass"},
+		{name: "failed job continues", condition: batchv1.JobFailed, policy: schedulerV1.ContinueOnHookFailure, wantVerdict: "Pass"},
+		{name: "failed job aborts", condition: batchv1.JobFailed, policy: schedulerV1.AbortOnHookFailure, wantVerdict: "Aborted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeReconciler(t, newTestEngine("e1", "Pass"))
+			cs := &chaosTypes.SchedulerInfo{Instance: newHookSchedule(&schedulerV1.RunHooks{PostRun: &schedulerV1.RunHook{
+				Job:           newHookJob(),
+				FailurePolicy: tt.policy,
+			}})}
+			run := newTestRun("100", "e1")
+			cs.Instance.Status.ActiveRuns = []schedulerV1.RunStatus{run}
+			cs.Instance.Status.Active = run.Engines
+
+			// the run stays active along with its verdict until the job is finished
+			for i := 0; i < 2; i++ {
+				if err := r.updateActiveStatus(context.TODO(), cs); err != nil {
+					t.Fatalf("updateActiveStatus() error = %v", err)
+				}
+				if len(cs.Instance.Status.History) != 0 || len(cs.Instance.Status.ActiveRuns) != 1 || cs.Instance.Status.ActiveRuns[0].Verdict != "Pass" {
+					t.Fatalf("activeRuns = %+v, history = %+v, want the run to wait for its hook", cs.Instance.Status.ActiveRuns, cs.Instance.Status.History)
+				}
+				if !isPostRunHookRunning(cs) {
+					t.Fatalf("postRunHook = %+v, want it running", cs.Instance.Status.PostRunHook)
+				}
+			}
+
+			job := &batchv1.Job{}
+			if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: jobName, Namespace: "default"}, job); err != nil {
+				t.Fatalf("hook job not created: %v", err)
+			}
+			verdict := ""
+			for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+				if e.Name == "CHAOS_RUN_VERDICT" {
+					verdict = e.Value
+				}
+			}
+			if verdict != "Pass" {
+				t.Fatalf("CHAOS_RUN_VERDICT = %q, want Pass", verdict)
+			}
+
+			finishJob(t, r.Client, jobName, tt.condition)
+			if err := r.updateActiveStatus(context.TODO(), cs); err != nil {
+				t.Fatalf("updateActiveStatus() error = %v", err)
+			}
+			if len(cs.Instance.Status.ActiveRuns) != 0 || len(cs.Instance.Status.History) != 1 || cs.Instance.Status.History[0].Verdict != tt.wantVerdict {
+				t.Fatalf("activeRuns = %+v, history = %+v, want verdict %s", cs.Instance.Status.ActiveRuns, cs.Instance.Status.History, tt.wantVerdict)
+			}
+			if isPostRunHookRunning(cs) {
+				t.Fatalf("postRunHook = %+v, want it finished", cs.Instance.Status.PostRunHook)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/audit"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// PausedCondition is the condition of the schedules which are paused by the kill switch
+const PausedCondition = "Paused"
+
+// globalPause is the state of the kill switch
+type globalPause struct {
+	paused bool
+	reason string
+	// stopEngines stops the active engines of the paused schedules
+	stopEngines bool
+}
+
+// getGlobalPause reads the kill switch ConfigMap, the schedules are not paused if it does not exist
+func (r *ChaosScheduleReconciler) getGlobalPause(ctx context.Context) (globalPause, error) {
+
+	if r.KillSwitch.Name == "" {
+		return globalPause{}, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Client.Get(ctx, r.KillSwitch, configMap)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return globalPause{}, nil
+	case err != nil:
+		return globalPause{}, err
+	}
+
+	paused, _ := strconv.ParseBool(strings.TrimSpace(configMap.Data["globalPause"]))
+	stopEngines, _ := strconv.ParseBool(strings.TrimSpace(configMap.Data["stopActiveEngines"]))
+	return globalPause{
+		paused:      paused,
+		reason:      configMap.Data["reason"],
+		stopEngines: stopEngines,
+	}, nil
+}
+
+// reconcileForGlobalPause treats the schedule as halted while the kill switch is set, the scheduleState is left as is
+func (schedulerReconcile *reconcileScheduler) reconcileForGlobalPause(cs *chaosTypes.SchedulerInfo, pause globalPause) (reconcile.Result, error) {
+
+	if pause.stopEngines && len(cs.Instance.Status.Active) != 0 {
+		stopped, err := schedulerReconcile.r.stopActiveEngines(schedulerReconcile.getContext(), cs)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if len(stopped) != 0 {
+			schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "GlobalPause", "Stopped engines %s: %s", strings.Join(stopped, ", "), pause.reason)
+			schedulerReconcile.r.auditStoppedEngines(cs, audit.Stop, "killSwitch", "GlobalPause", pause.reason, stopped)
+		}
+	}
+
+	current := meta.FindStatusCondition(cs.Instance.Status.Conditions, PausedCondition)
+	if current != nil && current.Status == metav1.ConditionTrue && current.Message == pause.reason {
+		return reconcile.Result{}, nil
+	}
+
+	meta.SetStatusCondition(&cs.Instance.Status.Conditions, metav1.Condition{
+		Type:               PausedCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cs.Instance.Generation,
+		Reason:             "GlobalPause",
+		LastTransitionTime: metav1.NewTime(schedulerReconcile.r.now()),
+		Message:            pause.reason,
+	})
+	if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	schedulerReconcile.reqLogger.Info("Schedule paused by the kill switch", "Reason", pause.reason)
+	schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "SchedulePaused", "Schedule paused by the kill switch: %s", pause.reason)
+	return reconcile.Result{}, nil
+}
+
+// resumeFromGlobalPause records the unpause of the schedule once the kill switch is unset
+func (schedulerReconcile *reconcileScheduler) resumeFromGlobalPause(cs *chaosTypes.SchedulerInfo) error {
+
+	if !meta.IsStatusConditionTrue(cs.Instance.Status.Conditions, PausedCondition) {
+		return nil
+	}
+
+	meta.SetStatusCondition(&cs.Instance.Status.Conditions, metav1.Condition{
+		Type:               PausedCondition,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: cs.Instance.Generation,
+		Reason:             "GlobalPauseLifted",
+		LastTransitionTime: metav1.NewTime(schedulerReconcile.r.now()),
+		Message:            "The kill switch has been unset",
+	})
+	if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+		return err
+	}
+	schedulerReconcile.reqLogger.Info("Schedule resumed after the kill switch has been unset")
+	schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "ScheduleResumed", "Schedule resumed after the kill switch has been unset")
+	return nil
+}
+
+// mapKillSwitchToSchedules requeues all the schedules whenever the kill switch ConfigMap changes
+func (r *ChaosScheduleReconciler) mapKillSwitchToSchedules(object client.Object) []reconcile.Request {
+
+	if (types.NamespacedName{Name: object.GetName(), Namespace: object.GetNamespace()}) != r.KillSwitch {
+		return nil
+	}
+
+	// the map functions of the watches are not given the context of the controller
+	var scheduleList schedulerV1.ChaosScheduleList
+	if err := r.Client.List(context.Background(), &scheduleList); err != nil {
+		chaosTypes.Log.Error(err, "unable to list the schedules for the kill switch")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(scheduleList.Items))
+	for _, schedule := range scheduleList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: schedule.Name, Namespace: schedule.Namespace}})
+	}
+	return requests
+}
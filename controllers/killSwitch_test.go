@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// newKillSwitch returns the kill switch ConfigMap with the given data
+func newKillSwitch(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "chaos-kill-switch", Namespace: "litmus"},
+		Data:       data,
+	}
+}
+
+// hasEvent checks whether an event of the given reason has been recorded
+func hasEvent(events []string, reason string) bool {
+	for _, event := range events {
+		if strings.Contains(event, " "+reason+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGlobalPause(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        map[string]string
+		wantPaused  bool
+		wantStopped bool
+	}{
+		{
+			name:       "paused",
+			data:       map[string]string{"globalPause": "true", "reason": "incident INC-42"},
+			wantPaused: true,
+		},
+		{
+			name:        "paused with the active engines stopped",
+			data:        map[string]string{"globalPause": "true", "reason": "incident INC-42", "stopActiveEngines": "true"},
+			wantPaused:  true,
+			wantStopped: true,
+		},
+		{
+			name:       "active engines left running",
+			data:       map[string]string{"globalPause": "true", "reason": "incident INC-42", "stopActiveEngines": "false"},
+			wantPaused: true,
+		},
+		{
+			name: "not paused",
+			data: map[string]string{"globalPause": "false", "stopActiveEngines": "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := newTestSchedule("schedule", everyMinute())
+			schedule.UID = "schedule-uid"
+			schedule.Spec.ScheduleState = schedulerV1.StateActive
+			schedule.Status.Schedule.Status = schedulerV1.StatusRunning
+			run := newTestRun("100", "e1")
+			schedule.Status.ActiveRuns = []schedulerV1.RunStatus{run}
+			schedule.Status.Active = run.Engines
+			killSwitch := newKillSwitch(tt.data)
+
+			r := newFakeReconciler(t, schedule, newTestEngine("e1", ""), killSwitch)
+			r.Clock = clocktesting.NewFakeClock(at(10, 0))
+			r.KillSwitch = types.NamespacedName{Name: killSwitch.Name, Namespace: killSwitch.Namespace}
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "schedule", Namespace: "default"}}
+
+			if _, err := r.Reconcile(context.TODO(), request); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			cs := &schedulerV1.ChaosSchedule{}
+			if err := r.Client.Get(context.TODO(), request.NamespacedName, cs); err != nil {
+				t.Fatal(err)
+			}
+			condition := meta.FindStatusCondition(cs.Status.Conditions, PausedCondition)
+			if paused := condition != nil && condition.Status == metav1.ConditionTrue; paused != tt.wantPaused {
+				t.Fatalf("paused condition = %+v, want paused %v", condition, tt.wantPaused)
+			}
+			if tt.wantPaused && (condition.Reason != "GlobalPause" || condition.Message != "incident INC-42") {
+				t.Fatalf("paused condition = %+v, want the reason of the kill switch", condition)
+			}
+			events := getEvents(r)
+			if hasEvent(events, "SchedulePaused") != tt.wantPaused {
+				t.Fatalf("events = %v, want SchedulePaused %v", events, tt.wantPaused)
+			}
+
+			engine := &operatorV1.ChaosEngine{}
+			if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "engine-e1", Namespace: "default"}, engine); err != nil {
+				t.Fatal(err)
+			}
+			if stopped := engine.Spec.EngineState == operatorV1.EngineStateStop; stopped != tt.wantStopped {
+				t.Fatalf("engine stopped = %v, want %v", stopped, tt.wantStopped)
+			}
+			if hasEvent(events, "GlobalPause") != tt.wantStopped {
+				t.Fatalf("events = %v, want the GlobalPause event %v", events, tt.wantStopped)
+			}
+			if !tt.wantPaused {
+				return
+			}
+
+			// the schedule is paused once, whatever the number of reconciles
+			if _, err := r.Reconcile(context.TODO(), request); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if events := getEvents(r); hasEvent(events, "SchedulePaused") {
+				t.Fatalf("events = %v, want the schedule paused once", events)
+			}
+
+			// the schedule is resumed once the kill switch is unset
+			killSwitch = &corev1.ConfigMap{}
+			if err := r.Client.Get(context.TODO(), r.KillSwitch, killSwitch); err != nil {
+				t.Fatal(err)
+			}
+			killSwitch.Data["globalPause"] = "false"
+			if err := r.Client.Update(context.TODO(), killSwitch); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := r.Reconcile(context.TODO(), request); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := r.Client.Get(context.TODO(), request.NamespacedName, cs); err != nil {
+				t.Fatal(err)
+			}
+			condition = meta.FindStatusCondition(cs.Status.Conditions, PausedCondition)
+			if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "GlobalPauseLifted" {
+				t.Fatalf("paused condition = %+v, want it lifted", condition)
+			}
+			if events := getEvents(r); !hasEvent(events, "ScheduleResumed") {
+				t.Fatalf("events = %v, want ScheduleResumed", events)
+			}
+		})
+	}
+}
+
+func TestMapKillSwitchToSchedules(t *testing.T) {
+	r := newFakeReconciler(t, newTestSchedule("first", everyMinute()), newTestSchedule("second", everyMinute()))
+	r.KillSwitch = types.NamespacedName{Name: "chaos-kill-switch", Namespace: "litmus"}
+
+	if requests := r.mapKillSwitchToSchedules(newKillSwitch(nil)); len(requests) != 2 {
+		t.Fatalf("requests = %v, want all the schedules", requests)
+	}
+	other := newKillSwitch(nil)
+	other.Namespace = "default"
+	if requests := r.mapKillSwitchToSchedules(other); len(requests) != 0 {
+		t.Fatalf("requests = %v, want none for another ConfigMap", requests)
+	}
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// mutexLeaseDuration is the time a schedule holds its mutex group after acquiring it, even before its engine shows up
+const mutexLeaseDuration = time.Minute
+
+// apiReader returns the reader of the objects which should not be read from the cache
+func (r *ChaosScheduleReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// acquireMutexGroup takes the Lease of the mutex group for the schedule
+// The Lease is read from the api server and updated with its resourceVersion, so that two schedules of the group
+// reconciled at the same time, whatever the number of workers or of replicas, cannot both acquire it. The holder keeps
+// the group while any of its engines of the group is active, or during mutexLeaseDuration after it acquired the group
+func (schedulerReconcile *reconcileScheduler) acquireMutexGroup(cs *chaosTypes.SchedulerInfo, group *schedulerV1.MutexGroup) (gateResult, error) {
+
+	r := schedulerReconcile.r
+	ctx := schedulerReconcile.getContext()
+	now := metav1.NewMicroTime(r.now())
+	holder := string(cs.Instance.UID)
+	busy := func(message string) gateResult {
+		return gateResult{
+			skip:    group.Policy == schedulerV1.SkipRun,
+			policy:  "mutexGroup/" + string(getMutexPolicy(group)),
+			reason:  "MutexGroupBusy",
+			message: message,
+		}
+	}
+
+	key := r.getMutexLeaseKey(cs, group)
+	lease := &coordinationv1.Lease{}
+	err := r.apiReader().Get(ctx, key, lease)
+	switch {
+	case k8serrors.IsNotFound(err):
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{"app": "chaos-scheduler", "chaosMutexGroup": group.Name},
+			},
+		}
+		setMutexLeaseHolder(lease, holder, now)
+		if err := r.Client.Create(ctx, lease); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				return busy(fmt.Sprintf("mutex group %s has just been acquired by another schedule", group.Name)), nil
+			}
+			return gateResult{}, err
+		}
+		return gateResult{passed: true}, nil
+	case err != nil:
+		return gateResult{}, err
+	}
+
+	current := ""
+	if lease.Spec.HolderIdentity != nil {
+		current = *lease.Spec.HolderIdentity
+	}
+	if current != holder && current != "" {
+		if lease.Spec.RenewTime != nil && r.since(lease.Spec.RenewTime.Time) < mutexLeaseDuration {
+			return busy(fmt.Sprintf("mutex group %s has been acquired by schedule %s at %s", group.Name, current, lease.Spec.RenewTime.Format(time.RFC3339))), nil
+		}
+		active, err := schedulerReconcile.getActiveMutexEngine(cs, group, current)
+		if err != nil {
+			return gateResult{}, err
+		}
+		if active != nil {
+			return busy(fmt.Sprintf("engine %s/%s of mutex group %s is active", active.Namespace, active.Name, group.Name)), nil
+		}
+		lease.Spec.LeaseTransitions = int32Ptr(getLeaseTransitions(lease) + 1)
+	}
+	// the update fails with a conflict when another schedule acquired the group in the meantime
+	setMutexLeaseHolder(lease, holder, now)
+	if err := r.Client.Update(ctx, lease); err != nil {
+		return gateResult{}, err
+	}
+	return gateResult{passed: true}, nil
+}
+
+// getActiveMutexEngine returns an active engine of the mutex group created by the given schedule, read from the api server
+func (schedulerReconcile *reconcileScheduler) getActiveMutexEngine(cs *chaosTypes.SchedulerInfo, group *schedulerV1.MutexGroup, scheduleUID string) (*operatorV1.ChaosEngine, error) {
+
+	optsList := []client.ListOption{
+		client.MatchingLabels{
+			"app":             "chaos-engine",
+			"chaosMutexGroup": group.Name,
+			"chaosUID":        scheduleUID,
+		},
+	}
+	if group.Scope != schedulerV1.ClusterMutex {
+		optsList = append(optsList, client.InNamespace(cs.Instance.Namespace))
+	}
+
+	var engineList operatorV1.ChaosEngineList
+	if err := schedulerReconcile.r.apiReader().List(schedulerReconcile.getContext(), &engineList, optsList...); err != nil {
+		return nil, err
+	}
+	for i := range engineList.Items {
+		if !isEngineDone(&engineList.Items[i]) {
+			return &engineList.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// getMutexLeaseKey returns the Lease of the mutex group, in the namespace of the schedule or, for the groups spanning
+// the cluster, in the namespace of the scheduler
+func (r *ChaosScheduleReconciler) getMutexLeaseKey(cs *chaosTypes.SchedulerInfo, group *schedulerV1.MutexGroup) types.NamespacedName {
+	namespace := cs.Instance.Namespace
+	if group.Scope == schedulerV1.ClusterMutex {
+		namespace = r.MutexLeaseNamespace
+	}
+	// the names of the groups are label values, which may hold upper case letters and underscores
+	name := strings.ReplaceAll(strings.ToLower(group.Name), "_", "-")
+	return types.NamespacedName{Name: "chaos-mutex-" + name, Namespace: namespace}
+}
+
+// setMutexLeaseHolder records the schedule as the holder of the mutex group
+func setMutexLeaseHolder(lease *coordinationv1.Lease, holder string, now metav1.MicroTime) {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != holder {
+		lease.Spec.AcquireTime = &now
+	}
+	lease.Spec.HolderIdentity = &holder
+	lease.Spec.RenewTime = &now
+	lease.Spec.LeaseDurationSeconds = int32Ptr(int32(mutexLeaseDuration.Seconds()))
+}
+
+// getLeaseTransitions returns the number of times the mutex group changed hands
+func getLeaseTransitions(lease *coordinationv1.Lease) int32 {
+	if lease.Spec.LeaseTransitions == nil {
+		return 0
+	}
+	return *lease.Spec.LeaseTransitions
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
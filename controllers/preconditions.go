@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	appsV1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// defaultHTTPPreconditionTimeout is the timeout of the http probe when it is not set
+const defaultHTTPPreconditionTimeout = 5 * time.Second
+
+// maxHTTPPreconditionTimeout caps the timeout of the http probe, which is run in the reconcile and holds its worker
+const maxHTTPPreconditionTimeout = 10 * time.Second
+
+// targetWorkload contains the details of a target workload needed by the preconditions
+type targetWorkload struct {
+	name        string
+	ready       bool
+	podSelector labels.Selector
+}
+
+// checkPreconditions checks the steady state of the targets of the upcoming run
+func (schedulerReconcile *reconcileScheduler) checkPreconditions(cs *chaosTypes.SchedulerInfo) (gateResult, error) {
+
+	preconditions := cs.Instance.Spec.Preconditions
+	if preconditions == nil {
+		return gateResult{passed: true}, nil
+	}
+
+	if preconditions.TargetReady || preconditions.NoCrashLoopBackOff {
+		targets, err := schedulerReconcile.r.getRunTargets(schedulerReconcile.getContext(), cs)
+		if err != nil {
+			return gateResult{}, err
+		}
+		for _, target := range targets {
+			if message, err := schedulerReconcile.r.checkTarget(schedulerReconcile.getContext(), preconditions, target); err != nil || message != "" {
+				return preconditionFailed(message), err
+			}
+		}
+	}
+
+	for _, pdb := range preconditions.PodDisruptionBudgets {
+		if message, err := schedulerReconcile.r.checkPodDisruptionBudget(schedulerReconcile.getContext(), cs, pdb); err != nil || message != "" {
+			return preconditionFailed(message), err
+		}
+	}
+
+	if preconditions.HTTPProbe != nil {
+		if message := checkHTTPPrecondition(schedulerReconcile.getContext(), preconditions.HTTPProbe); message != "" {
+			return preconditionFailed(message), nil
+		}
+	}
+	return gateResult{passed: true}, nil
+}
+
+// preconditionFailed returns the gate result for a failed precondition
+func preconditionFailed(message string) gateResult {
+	return gateResult{skip: true, policy: "preconditions", reason: "PreconditionFailed", message: message}
+}
+
+// getRunTargets returns the targets of the engines of the upcoming run
+func (r *ChaosScheduleReconciler) getRunTargets(ctx context.Context, cs *chaosTypes.SchedulerInfo) ([]schedulerV1.RotationTarget, error) {
+
+	var targets []schedulerV1.RotationTarget
+	switch {
+	case len(cs.Instance.Spec.EngineTemplates) != 0:
+		for _, template := range cs.Instance.Spec.EngineTemplates {
+			targets = append(targets, schedulerV1.RotationTarget{
+				Appns:    template.Spec.Appinfo.Appns,
+				Applabel: template.Spec.Appinfo.Applabel,
+				AppKind:  template.Spec.Appinfo.AppKind,
+			})
+		}
+	case cs.Instance.Spec.TargetRotation != nil:
+		target, _, err := r.resolveTarget(ctx, cs)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	default:
+		appinfo := cs.Instance.Spec.EngineTemplateSpec.Appinfo
+		targets = append(targets, schedulerV1.RotationTarget{
+			Appns:    appinfo.Appns,
+			Applabel: appinfo.Applabel,
+			AppKind:  appinfo.AppKind,
+		})
+	}
+
+	for i := range targets {
+		if targets[i].Appns == "" {
+			targets[i].Appns = cs.Instance.Namespace
+		}
+	}
+	return targets, nil
+}
+
+// checkTarget checks the readiness and the pods of the target, it returns the reason of the failure if any
+func (r *ChaosScheduleReconciler) checkTarget(ctx context.Context, preconditions *schedulerV1.Preconditions, target schedulerV1.RotationTarget) (string, error) {
+
+	// the targets defined through the selectors of the engine spec are not checked
+	if target.Applabel == "" && target.Name == "" {
+		return "", nil
+	}
+
+	workloads, err := r.getTargetWorkloads(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	if len(workloads) == 0 {
+		return fmt.Sprintf("no %s found in namespace %s for the target %s%s", target.AppKind, target.Appns, target.Applabel, target.Name), nil
+	}
+
+	for _, workload := range workloads {
+		if preconditions.TargetReady && !workload.ready {
+			return fmt.Sprintf("%s %s/%s does not have all the replicas ready", target.AppKind, target.Appns, workload.name), nil
+		}
+		if preconditions.NoCrashLoopBackOff {
+			pod, err := r.getCrashLoopingPod(ctx, target.Appns, workload.podSelector)
+			if err != nil {
+				return "", err
+			}
+			if pod != "" {
+				return fmt.Sprintf("pod %s/%s of %s %s is in CrashLoopBackOff", target.Appns, pod, target.AppKind, workload.name), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// getTargetWorkloads looks up the workloads of the target, either by name or by label
+func (r *ChaosScheduleReconciler) getTargetWorkloads(ctx context.Context, target schedulerV1.RotationTarget) ([]targetWorkload, error) {
+
+	opts := []client.ListOption{client.InNamespace(target.Appns)}
+	if target.Applabel != "" {
+		selector, err := labels.Parse(target.Applabel)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the target label %q, err: %v", target.Applabel, err)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	var workloads []targetWorkload
+	switch strings.ToLower(target.AppKind) {
+	case "", "deployment":
+		var list appsV1.DeploymentList
+		if err := r.Client.List(ctx, &list, opts...); err != nil {
+			return nil, err
+		}
+		for _, d := range list.Items {
+			workloads = append(workloads, newTargetWorkload(d.Name, d.Spec.Selector, getReplicas(d.Spec.Replicas) <= d.Status.ReadyReplicas))
+		}
+	case "statefulset":
+		var list appsV1.StatefulSetList
+		if err := r.Client.List(ctx, &list, opts...); err != nil {
+			return nil, err
+		}
+		for _, s := range list.Items {
+			workloads = append(workloads, newTargetWorkload(s.Name, s.Spec.Selector, getReplicas(s.Spec.Replicas) <= s.Status.ReadyReplicas))
+		}
+	case "daemonset":
+		var list appsV1.DaemonSetList
+		if err := r.Client.List(ctx, &list, opts...); err != nil {
+			return nil, err
+		}
+		for _, d := range list.Items {
+			workloads = append(workloads, newTargetWorkload(d.Name, d.Spec.Selector, d.Status.DesiredNumberScheduled <= d.Status.NumberReady))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported target kind %q for the preconditions, should be one of ('deployment', 'statefulset', 'daemonset')", target.AppKind)
+	}
+
+	if target.Name == "" {
+		return workloads, nil
+	}
+	for _, workload := range workloads {
+		if workload.name == target.Name {
+			return []targetWorkload{workload}, nil
+		}
+	}
+	return nil, nil
+}
+
+// newTargetWorkload builds the target workload, the pods are selected through the selector of the workload
+func newTargetWorkload(name string, selector *metav1.LabelSelector, ready bool) targetWorkload {
+	podSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		podSelector = labels.Nothing()
+	}
+	return targetWorkload{name: name, ready: ready, podSelector: podSelector}
+}
+
+// getReplicas returns the desired replicas of a workload, which defaults to 1
+func getReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// getCrashLoopingPod returns the name of a pod matching the selector which is in CrashLoopBackOff, if any
+func (r *ChaosScheduleReconciler) getCrashLoopingPod(ctx context.Context, namespace string, selector labels.Selector) (string, error) {
+
+	var podList corev1.PodList
+	if err := r.Client.List(ctx, &podList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return "", err
+	}
+
+	for _, pod := range podList.Items {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			if status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff" {
+				return pod.Name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// checkPodDisruptionBudget checks that the PodDisruptionBudget allows disruptions, it returns the reason of the failure if any
+func (r *ChaosScheduleReconciler) checkPodDisruptionBudget(ctx context.Context, cs *chaosTypes.SchedulerInfo, ref schedulerV1.PodDisruptionBudgetReference) (string, error) {
+
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = cs.Instance.Namespace
+	}
+
+	pdb := &policyV1.PodDisruptionBudget{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, pdb)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return fmt.Sprintf("PodDisruptionBudget %s/%s not found", namespace, ref.Name), nil
+	case err != nil:
+		return "", err
+	}
+	if pdb.Status.DisruptionsAllowed < 1 {
+		return fmt.Sprintf("PodDisruptionBudget %s/%s does not allow disruptions", namespace, ref.Name), nil
+	}
+	return "", nil
+}
+
+// checkHTTPPrecondition probes the http endpoint, it returns the reason of the failure if any
+func checkHTTPPrecondition(ctx context.Context, probe *schedulerV1.HTTPPrecondition) string {
+
+	ctx, cancel := context.WithTimeout(ctx, getHTTPPreconditionTimeout(probe))
+	defer cancel()
+	expectedStatus := http.StatusOK
+	if probe.ExpectedStatus != 0 {
+		expectedStatus = probe.ExpectedStatus
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		return fmt.Sprintf("invalid http probe url %s, err: %v", probe.URL, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Sprintf("http probe to %s failed, err: %v", probe.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		return fmt.Sprintf("http probe to %s returned status %d, expected %d", probe.URL, resp.StatusCode, expectedStatus)
+	}
+	return ""
+}
+
+// getHTTPPreconditionTimeout returns the timeout of the http probe, capped to maxHTTPPreconditionTimeout
+func getHTTPPreconditionTimeout(probe *schedulerV1.HTTPPrecondition) time.Duration {
+	switch {
+	case probe.Timeout == nil || probe.Timeout.Duration <= 0:
+		return defaultHTTPPreconditionTimeout
+	case probe.Timeout.Duration > maxHTTPPreconditionTimeout:
+		return maxHTTPPreconditionTimeout
+	}
+	return probe.Timeout.Duration
+}
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appsV1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// newTestDeployment returns the nginx deployment targeted by the test schedules, with the given ready replicas out of 2
+func newTestDeployment(readyReplicas int32) *appsV1.Deployment {
+	replicas := int32(2)
+	return &appsV1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default", Labels: map[string]string{"app": "nginx"}},
+		Spec: appsV1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+		},
+		Status: appsV1.DeploymentStatus{ReadyReplicas: readyReplicas},
+	}
+}
+
+// newTestPod returns a pod of the nginx deployment whose container waits for the given reason, if any
+func newTestPod(name, waitingReason string) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "nginx"}}}
+	status := corev1.ContainerStatus{Name: "nginx", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	if waitingReason != "" {
+		status.State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: waitingReason}}
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{status}
+	return pod
+}
+
+// newTestPDB returns the PodDisruptionBudget of the nginx deployment allowing the given disruptions
+func newTestPDB(disruptionsAllowed int32) *policyV1.PodDisruptionBudget {
+	return &policyV1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx-pdb", Namespace: "default"},
+		Status:     policyV1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+// newFakeProbe serves the given status, after the given delay
+func newFakeProbe(t *testing.T, status int, delay time.Duration) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	healthy := newFakeProbe(t, http.StatusOK, 0)
+	unhealthy := newFakeProbe(t, http.StatusServiceUnavailable, 0)
+	slow := newFakeProbe(t, http.StatusOK, time.Second)
+
+	tests := []struct {
+		name          string
+		preconditions schedulerV1.Preconditions
+		objects       []client.Object
+		wantMessage   string
+	}{
+		{
+			name:          "target ready",
+			preconditions: schedulerV1.Preconditions{TargetReady: true},
+			objects:       []client.Object{newTestDeployment(2)},
+		},
+		{
+			name:          "target not ready",
+			preconditions: schedulerV1.Preconditions{TargetReady: true},
+			objects:       []client.Object{newTestDeployment(1)},
+			wantMessage:   "deployment default/nginx does not have all the replicas ready",
+		},
+		{
+			name:          "target not found",
+			preconditions: schedulerV1.Preconditions{TargetReady: true},
+			wantMessage:   "no deployment found in namespace default",
+		},
+		{
+			name:          "no pod in CrashLoopBackOff",
+			preconditions: schedulerV1.Preconditions{NoCrashLoopBackOff: true},
+			objects:       []client.Object{newTestDeployment(1), newTestPod("nginx-a", ""), newTestPod("nginx-b", "ContainerCreating")},
+		},
+		{
+			name:          "pod in CrashLoopBackOff",
+			preconditions: schedulerV1.Preconditions{NoCrashLoopBackOff: true},
+			objects:       []client.Object{newTestDeployment(2), newTestPod("nginx-a", ""), newTestPod("nginx-b", "CrashLoopBackOff")},
+			wantMessage:   "pod default/nginx-b of deployment nginx is in CrashLoopBackOff",
+		},
+		{
+			name:          "PodDisruptionBudget allowing disruptions",
+			preconditions: schedulerV1.Preconditions{PodDisruptionBudgets: []schedulerV1.PodDisruptionBudgetReference{{Name: "nginx-pdb"}}},
+			objects:       []client.Object{newTestPDB(1)},
+		},
+		{
+			name:          "PodDisruptionBudget not allowing disruptions",
+			preconditions: schedulerV1.Preconditions{PodDisruptionBudgets: []schedulerV1.PodDisruptionBudgetReference{{Name: "nginx-pdb"}}},
+			objects:       []client.Object{newTestPDB(0)},
+			wantMessage:   "PodDisruptionBudget default/nginx-pdb does not allow disruptions",
+		},
+		{
+			name:          "PodDisruptionBudget not found",
+			preconditions: schedulerV1.Preconditions{PodDisruptionBudgets: []schedulerV1.PodDisruptionBudgetReference{{Name: "nginx-pdb", Namespace: "litmus"}}},
+			objects:       []client.Object{newTestPDB(1)},
+			wantMessage:   "PodDisruptionBudget litmus/nginx-pdb not found",
+		},
+		{
+			name:          "healthy endpoint",
+			preconditions: schedulerV1.Preconditions{HTTPProbe: &schedulerV1.HTTPPrecondition{URL: healthy.URL}},
+		},
+		{
+			name:          "unhealthy endpoint",
+			preconditions: schedulerV1.Preconditions{HTTPProbe: &schedulerV1.HTTPPrecondition{URL: unhealthy.URL}},
+			wantMessage:   "returned status 503, expected 200",
+		},
+		{
+			name:          "expected status",
+			preconditions: schedulerV1.Preconditions{HTTPProbe: &schedulerV1.HTTPPrecondition{URL: unhealthy.URL, ExpectedStatus: http.StatusServiceUnavailable}},
+		},
+		{
+			name: "endpoint timing out",
+			preconditions: schedulerV1.Preconditions{HTTPProbe: &schedulerV1.HTTPPrecondition{
+				URL:     slow.URL,
+				Timeout: &metav1.Duration{Duration: 50 * time.Millisecond},
+			}},
+			wantMessage: "http probe to " + slow.URL + " failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := newTestSchedule("schedule", everyMinute())
+			schedule.Spec.EngineTemplateSpec.Appinfo.AppKind = "deployment"
+			schedule.Spec.Preconditions = &tt.preconditions
+			s := &reconcileScheduler{r: newFakeReconciler(t, tt.objects...), reqLogger: chaosTypes.Log}
+
+			gate, err := s.checkPreconditions(&chaosTypes.SchedulerInfo{Instance: schedule})
+			if err != nil {
+				t.Fatalf("checkPreconditions() error = %v", err)
+			}
+			if tt.wantMessage == "" {
+				if !gate.passed {
+					t.Fatalf("checkPreconditions() = %+v, want it passed", gate)
+				}
+				return
+			}
+			if gate.passed || !gate.skip || gate.reason != "PreconditionFailed" || !strings.Contains(gate.message, tt.wantMessage) {
+				t.Fatalf("checkPreconditions() = %+v, want the run skipped with %q", gate, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestGetHTTPPreconditionTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout *metav1.Duration
+		want    time.Duration
+	}{
+		{name: "default", want: defaultHTTPPreconditionTimeout},
+		{name: "set", timeout: &metav1.Duration{Duration: 2 * time.Second}, want: 2 * time.Second},
+		{name: "capped", timeout: &metav1.Duration{Duration: time.Minute}, want: maxHTTPPreconditionTimeout},
+		{name: "zero", timeout: &metav1.Duration{}, want: defaultHTTPPreconditionTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getHTTPPreconditionTimeout(&schedulerV1.HTTPPrecondition{Timeout: tt.timeout}); got != tt.want {
+				t.Fatalf("getHTTPPreconditionTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
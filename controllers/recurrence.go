@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	cron "github.com/robfig/cron/v3"
+	"github.com/teambition/rrule-go"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// rruleSchedule adapts the recurrence set of an rrule schedule to the cron.Schedule interface, so that the rrule
+// schedules go through the same missed-run, time range and concurrency handling as the repeat schedules
+// The iterator over the occurrences is kept across the calls, the callers walk the ticks forward and the set would
+// otherwise be expanded from its DTSTART on every call
+type rruleSchedule struct {
+	set *rrule.Set
+	// next returns the occurrences of the set in order, current is the last one returned and previous the one before
+	next        rrule.Next
+	current     time.Time
+	previous    time.Time
+	hasPrevious bool
+	done        bool
+}
+
+// Next returns the first occurrence after the given time, or the zero time once the COUNT or UNTIL of the rule is reached
+func (s *rruleSchedule) Next(t time.Time) time.Time {
+	// the iteration is restarted for a time before the occurrences already walked past
+	if s.next == nil || s.hasPrevious && t.Before(s.previous) {
+		s.next = s.set.Iterator()
+		s.hasPrevious = false
+		s.advance()
+	}
+	for !s.done && !s.current.After(t) {
+		s.previous, s.hasPrevious = s.current, true
+		s.advance()
+	}
+	if s.done {
+		return time.Time{}
+	}
+	return s.current
+}
+
+// advance moves the iterator to the next occurrence of the set
+func (s *rruleSchedule) advance() {
+	var ok bool
+	s.current, ok = s.next()
+	s.done = !ok
+}
+
+// getRecurrence returns the recurrence of the repeat and rrule schedules, i.e. the cron string formed from the
+// repeat properties or the rule of the rrule
+func (schedulerReconcile *reconcileScheduler) getRecurrence(cs *types.SchedulerInfo) (string, error) {
+	if rule := cs.Instance.Spec.Schedule.RRule; rule != nil {
+		return rule.Rule, nil
+	}
+	return schedulerReconcile.scheduleRepeat(cs)
+}
+
+// parseRecurrence parses the recurrence returned by getRecurrence in the time zone of the scheduler
+func (r *ChaosScheduleReconciler) parseRecurrence(cs *types.SchedulerInfo, recurrence string) (cron.Schedule, error) {
+	if cs.Instance.Spec.Schedule.RRule == nil {
+		return r.parseCronSchedule(recurrence)
+	}
+
+	location := r.Settings.Location()
+	option, err := rrule.StrToROptionInLocation(recurrence, location)
+	if err != nil {
+		return nil, fmt.Errorf("unparseable rrule: %s : %s", recurrence, err)
+	}
+
+	// the BYHOUR and BYDAY of the rule are evaluated in the time zone of the DTSTART
+	spec := cs.Instance.Spec.Schedule.RRule
+	switch {
+	case spec.StartTime != nil:
+		option.Dtstart = spec.StartTime.In(location)
+	case option.Dtstart.IsZero():
+		created := cs.Instance.CreationTimestamp.In(location)
+		option.Dtstart = time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, location)
+	}
+
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule: %s : %s", recurrence, err)
+	}
+	set := &rrule.Set{}
+	set.RRule(rule)
+	for _, excluded := range spec.ExcludedTimes {
+		set.ExDate(excluded.Time)
+	}
+	return &rruleSchedule{set: set}, nil
+}
+
+// getTimeRange returns the time range of the repeat and rrule schedules
+func getTimeRange(cs *types.SchedulerInfo) *schedulerV1.TimeRange {
+	switch {
+	case cs.Instance.Spec.Schedule.RRule != nil:
+		return cs.Instance.Spec.Schedule.RRule.TimeRange
+	case cs.Instance.Spec.Schedule.Repeat != nil:
+		return cs.Instance.Spec.Schedule.Repeat.TimeRange
+	}
+	return nil
+}
+
+// getMostRecentTick returns the most recent tick of the schedule after the earliest time which is due at the given time
+func getMostRecentTick(schedule cron.Schedule, earliestTime, now time.Time) (time.Time, bool) {
+	var previousTime time.Time
+	found := false
+	for t := schedule.Next(earliestTime); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		previousTime = t
+		found = true
+	}
+	return previousTime, found
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configV1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// newRRuleSchedule returns an rrule schedule created at the given time
+func newRRuleSchedule(created time.Time, rrule schedulerV1.ScheduleRRule) *chaosTypes.SchedulerInfo {
+	return &chaosTypes.SchedulerInfo{Instance: &schedulerV1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: created}},
+		Spec:       schedulerV1.ChaosScheduleSpec{Schedule: schedulerV1.Schedule{RRule: &rrule}},
+	}}
+}
+
+func TestGetRecentUnmetScheduleTimeForRRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rrule   schedulerV1.ScheduleRRule
+		now     time.Time
+		status  schedulerV1.ChaosScheduleStatus
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "first occurrence after the creation",
+			rrule: schedulerV1.ScheduleRRule{Rule: "FREQ=DAILY;BYHOUR=10"},
+			now:   at(9, 30),
+			want:  at(10, 0),
+		},
+		{
+			name:  "occurrences before the creation are not run",
+			rrule: schedulerV1.ScheduleRRule{Rule: "FREQ=HOURLY;INTERVAL=6"},
+			now:   at(9, 30),
+			want:  at(12, 0),
+		},
+		{
+			name:  "most recent missed occurrence",
+			rrule: schedulerV1.ScheduleRRule{Rule: "FREQ=HOURLY"},
+			now:   at(12, 30),
+			want:  at(12, 0),
+		},
+		{
+			name:   "next occurrence after the last run",
+			rrule:  schedulerV1.ScheduleRRule{Rule: "FREQ=HOURLY"},
+			now:    at(10, 30),
+			status: schedulerV1.ChaosScheduleStatus{LastScheduleTime: metaTime(at(10, 0))},
+			want:   at(11, 0),
+		},
+		{
+			name:   "excluded occurrence",
+			rrule:  schedulerV1.ScheduleRRule{Rule: "FREQ=HOURLY", ExcludedTimes: []metav1.Time{{Time: at(11, 0)}}},
+			now:    at(10, 30),
+			status: schedulerV1.ChaosScheduleStatus{LastScheduleTime: metaTime(at(10, 0))},
+			want:   at(12, 0),
+		},
+		{
+			name:  "weekly game days",
+			rrule: schedulerV1.ScheduleRRule{Rule: "FREQ=WEEKLY;BYDAY=TU,TH;BYHOUR=10"},
+			now:   at(9, 30),
+			want:  at(10, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:  "occurrences relative to the start time",
+			rrule: schedulerV1.ScheduleRRule{Rule: "FREQ=HOURLY", StartTime: metaTime(at(9, 15))},
+			now:   at(9, 20),
+			want:  at(9, 15),
+		},
+		{
+			name:  "start time within the rule",
+			rrule: schedulerV1.ScheduleRRule{Rule: "DTSTART:20211006T091500Z\nRRULE:FREQ=HOURLY"},
+			now:   at(9, 20),
+			want:  at(9, 15),
+		},
+		{
+			name:  "first run from the start of the time range",
+			rrule: schedulerV1.ScheduleRRule{Rule: "FREQ=HOURLY", TimeRange: &schedulerV1.TimeRange{StartTime: metaTime(at(13, 30))}},
+			now:   at(10, 0),
+			want:  at(14, 0),
+		},
+		{
+			name:  "count reached before the creation",
+			rrule: schedulerV1.ScheduleRRule{Rule: "FREQ=HOURLY;COUNT=2"},
+			now:   at(9, 30),
+		},
+		{
+			name:   "until reached",
+			rrule:  schedulerV1.ScheduleRRule{Rule: "FREQ=HOURLY;UNTIL=20211006T110000Z"},
+			now:    at(11, 30),
+			status: schedulerV1.ChaosScheduleStatus{LastScheduleTime: metaTime(at(11, 0))},
+		},
+		{
+			name:    "invalid rule",
+			rrule:   schedulerV1.ScheduleRRule{Rule: "FREQ=SOMETIMES"},
+			now:     at(9, 30),
+			wantErr: true,
+		},
+	}
+
+	schedulerReconcile := newTestScheduler(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := newRRuleSchedule(at(9, 0), tt.rrule)
+			cs.Instance.Status = tt.status
+
+			recurrence, err := schedulerReconcile.getRecurrence(cs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := schedulerReconcile.getRecentUnmetScheduleTime(cs, recurrence, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getRecentUnmetScheduleTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Fatalf("getRecentUnmetScheduleTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceInTimeZone(t *testing.T) {
+	settings, err := NewSettings(&configV1.ChaosSchedulerConfig{Defaults: configV1.ScheduleDefaults{TimeZone: "Europe/Berlin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &ChaosScheduleReconciler{Settings: settings}
+
+	// the rule is evaluated from the midnight of the creation day in Berlin, which is in CEST
+	cs := newRRuleSchedule(at(9, 0), schedulerV1.ScheduleRRule{Rule: "FREQ=DAILY;BYHOUR=10"})
+	recurrence, err := r.parseRecurrence(cs, cs.Instance.Spec.Schedule.RRule.Rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := recurrence.Next(at(7, 0)); !got.Equal(at(8, 0)) {
+		t.Fatalf("Next() = %v, want %v", got, at(8, 0))
+	}
+}
+
+// TestRRuleScheduleNext checks that the occurrences walked with the cached iterator are the ones of the set, whatever
+// the order of the calls
+func TestRRuleScheduleNext(t *testing.T) {
+	r := newTestScheduler(t).r
+	cs := newRRuleSchedule(at(9, 0), schedulerV1.ScheduleRRule{
+		Rule:          "FREQ=MINUTELY;INTERVAL=7;COUNT=100",
+		ExcludedTimes: []metav1.Time{{Time: at(0, 21)}},
+	})
+	recurrence, err := r.parseRecurrence(cs, cs.Instance.Spec.Schedule.RRule.Rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := recurrence.(*rruleSchedule).set
+
+	// forward, as walked by getMostRecentTick, then backward, then past the COUNT of the rule
+	var times []time.Time
+	for minute := 0; minute < 12*60; minute += 5 {
+		times = append(times, at(0, 0).Add(time.Duration(minute)*time.Minute))
+	}
+	times = append(times, at(3, 0), at(0, 14), at(0, 14), at(0, 0).Add(-time.Minute), at(20, 0), at(1, 0))
+	for _, tick := range times {
+		if got, want := recurrence.Next(tick), set.After(tick, false); !got.Equal(want) {
+			t.Fatalf("Next(%v) = %v, want %v", tick, got, want)
+		}
+	}
+
+	// all the occurrences but the excluded one are walked, up to the COUNT of the rule
+	count := 0
+	for tick := recurrence.Next(at(0, 0).Add(-time.Minute)); !tick.IsZero(); tick = recurrence.Next(tick) {
+		count++
+	}
+	if count != 99 {
+		t.Fatalf("%d occurrences walked, want 99", count)
+	}
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/audit"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// gateRetryInterval is the interval after which a deferred run is evaluated again
+const gateRetryInterval = 30 * time.Second
+
+// gateResult is the outcome of the checks done right before the engine of a new run is created
+type gateResult struct {
+	passed bool
+	// skip drops the run, otherwise it is deferred until the gate passes
+	skip bool
+	// abort finishes the run right away with the Aborted verdict
+	abort bool
+	// policy is the part of the spec which held the run, as recorded in the audit log
+	policy  string
+	reason  string
+	message string
+}
+
+// evaluateRunGates checks whether the engine of a new run can be created
+func (schedulerReconcile *reconcileScheduler) evaluateRunGates(cs *chaosTypes.SchedulerInfo) (gateResult, error) {
+
+	gate, err := schedulerReconcile.checkDependencies(cs)
+	if err != nil || !gate.passed {
+		return gate, err
+	}
+	gate, err = schedulerReconcile.checkMutexGroup(cs)
+	if err != nil || !gate.passed {
+		return gate, err
+	}
+	gate, err = schedulerReconcile.checkPreconditions(cs)
+	if err != nil || !gate.passed {
+		return gate, err
+	}
+	return schedulerReconcile.checkSLOGuard(cs)
+}
+
+// checkDependencies checks whether the most recent run of each of the schedules this schedule depends on succeeded
+func (schedulerReconcile *reconcileScheduler) checkDependencies(cs *chaosTypes.SchedulerInfo) (gateResult, error) {
+
+	for _, dependency := range cs.Instance.Spec.DependsOn {
+		namespace := dependency.Namespace
+		if namespace == "" {
+			namespace = cs.Instance.Namespace
+		}
+
+		other := &schedulerV1.ChaosSchedule{}
+		err := schedulerReconcile.r.Client.Get(schedulerReconcile.getContext(), types.NamespacedName{Name: dependency.Name, Namespace: namespace}, other)
+		switch {
+		case k8serrors.IsNotFound(err):
+			return gateResult{policy: "dependsOn", reason: "DependencyNotFound", message: fmt.Sprintf("schedule %s/%s not found", namespace, dependency.Name)}, nil
+		case err != nil:
+			return gateResult{}, err
+		case len(other.Status.Active) != 0:
+			return gateResult{policy: "dependsOn", reason: "DependencyRunning", message: fmt.Sprintf("schedule %s/%s is running", namespace, dependency.Name)}, nil
+		case len(other.Status.History) == 0:
+			return gateResult{policy: "dependsOn", reason: "DependencyNotRun", message: fmt.Sprintf("schedule %s/%s has not run yet", namespace, dependency.Name)}, nil
+		}
+
+		if last := other.Status.History[len(other.Status.History)-1]; last.Verdict != "Pass" {
+			return gateResult{policy: "dependsOn", reason: "DependencyFailed", message: fmt.Sprintf("most recent run %s of schedule %s/%s has verdict %q", last.RunID, namespace, dependency.Name, last.Verdict)}, nil
+		}
+	}
+	return gateResult{passed: true}, nil
+}
+
+// checkMutexGroup checks whether the engines of any other schedule of the mutex group are active
+func (schedulerReconcile *reconcileScheduler) checkMutexGroup(cs *chaosTypes.SchedulerInfo) (gateResult, error) {
+
+	group := cs.Instance.Spec.MutexGroup
+	if group == nil {
+		return gateResult{passed: true}, nil
+	}
+
+	optsList := []client.ListOption{
+		client.MatchingLabels{
+			"app":             "chaos-engine",
+			"chaosMutexGroup": group.Name,
+		},
+	}
+	if group.Scope != schedulerV1.ClusterMutex {
+		optsList = append(optsList, client.InNamespace(cs.Instance.Namespace))
+	}
+
+	var engineList operatorV1.ChaosEngineList
+	if err := schedulerReconcile.r.Client.List(schedulerReconcile.getContext(), &engineList, optsList...); err != nil {
+		return gateResult{}, err
+	}
+
+	for _, engine := range engineList.Items {
+		if engine.Labels["chaosUID"] == string(cs.Instance.UID) || isEngineDone(&engine) {
+			continue
+		}
+		return gateResult{
+			skip:    group.Policy == schedulerV1.SkipRun,
+			policy:  "mutexGroup/" + string(getMutexPolicy(group)),
+			reason:  "MutexGroupBusy",
+			message: fmt.Sprintf("engine %s/%s of mutex group %s is active", engine.Namespace, engine.Name, group.Name),
+		}, nil
+	}
+	// the cache may not hold the engine another worker or replica has just created, the group is acquired on the api server
+	return schedulerReconcile.acquireMutexGroup(cs, group)
+}
+
+// holdRun skips or defers the run which did not pass the gates and records the reason
+func (schedulerReconcile *reconcileScheduler) holdRun(cs *chaosTypes.SchedulerInfo, scheduledTime time.Time, gate gateResult, request reconcile.Request) (reconcile.Result, error) {
+
+	if gate.abort {
+		return schedulerReconcile.abortRun(cs, scheduledTime, gate, request)
+	}
+
+	last := cs.Instance.Status.LastSkippedRun
+	changed := last == nil || last.Reason != gate.reason || last.Deferred == gate.skip ||
+		last.ScheduledTime == nil || !last.ScheduledTime.Time.Equal(scheduledTime)
+
+	cs.Instance.Status.LastSkippedRun = &schedulerV1.SkippedRun{
+		ScheduledTime: &metav1.Time{Time: scheduledTime},
+		Time:          metav1.NewTime(schedulerReconcile.r.now()),
+		Deferred:      !gate.skip,
+		Reason:        gate.reason,
+		Message:       gate.message,
+	}
+
+	if !gate.skip {
+		schedulerReconcile.reqLogger.Info("Deferring the run", "Reason", gate.reason, "Message", gate.message)
+		// the deferred run is evaluated again periodically, status and event are recorded once per reason
+		if !changed {
+			return reconcile.Result{RequeueAfter: gateRetryInterval}, nil
+		}
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "DeferredRun", "Deferred run scheduled at %s: %s", scheduledTime.Format(time.RFC1123Z), gate.message)
+		schedulerReconcile.r.auditHeldRun(cs, audit.Defer, scheduledTime, gate)
+		if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{RequeueAfter: gateRetryInterval}, nil
+	}
+
+	schedulerReconcile.reqLogger.Info("Skipping the run", "Reason", gate.reason, "Message", gate.message)
+	schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "SkippedRun", "Skipped run scheduled at %s: %s", scheduledTime.Format(time.RFC1123Z), gate.message)
+	if err := schedulerReconcile.r.emitRunSkipped(schedulerReconcile.getContext(), cs, scheduledTime, gate); err != nil {
+		return reconcile.Result{}, err
+	}
+	schedulerReconcile.r.auditHeldRun(cs, audit.Skip, scheduledTime, gate)
+
+	// the now and once schedules have a single run, skipping it completes the schedule
+	if cs.Instance.Spec.Schedule.Repeat == nil && cs.Instance.Spec.Schedule.RRule == nil {
+		cs.Instance.Spec.ScheduleState = schedulerV1.StateCompleted
+		if err := schedulerReconcile.UpdateSchedulerStatus(cs, request); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// the repeat and rrule schedules wait for the tick following the skipped run, see getRecentUnmetScheduleTime
+	if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// abortRun finishes the run which did not pass the gates with the Aborted verdict, without creating its engines
+// Unlike the skipped run, the aborted run is recorded in the history and counts as a run of the schedule
+func (schedulerReconcile *reconcileScheduler) abortRun(cs *chaosTypes.SchedulerInfo, scheduledTime time.Time, gate gateResult, request reconcile.Request) (reconcile.Result, error) {
+
+	runID := getRunID(scheduledTime)
+	schedulerReconcile.reqLogger.Info("Aborting the run", "RunID", runID, "Reason", gate.reason, "Message", gate.message)
+	schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "AbortedRun", "Aborted run scheduled at %s: %s", scheduledTime.Format(time.RFC1123Z), gate.message)
+	schedulerReconcile.r.auditHeldRun(cs, audit.Abort, scheduledTime, gate)
+
+	now := metav1.NewTime(schedulerReconcile.r.now())
+	cs.Instance.Status.ActiveRuns = append(cs.Instance.Status.ActiveRuns, schedulerV1.RunStatus{RunID: runID, StartTime: &now})
+	if err := schedulerReconcile.r.finishRun(schedulerReconcile.getContext(), cs, runID, "Aborted"); err != nil {
+		return reconcile.Result{}, err
+	}
+	cs.Instance.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	cs.Instance.Status.LastScheduleCompletionTime = &now
+
+	// the now and once schedules have a single run, aborting it completes the schedule
+	if cs.Instance.Spec.Schedule.Repeat == nil && cs.Instance.Spec.Schedule.RRule == nil {
+		if err := schedulerReconcile.UpdateSchedulerStatus(cs, request); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	cs.Instance.Status.Schedule.RunInstances = cs.Instance.Status.Schedule.RunInstances + 1
+	if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// getMutexPolicy returns the policy of the mutex group, which defaults to defer
+func getMutexPolicy(group *schedulerV1.MutexGroup) schedulerV1.MutexPolicy {
+	if group.Policy == "" {
+		return schedulerV1.DeferRun
+	}
+	return group.Policy
+}
+
+// isEngineDone checks whether the engine is either completed or stopped
+func isEngineDone(engine *operatorV1.ChaosEngine) bool {
+	return IsEngineFinished(engine) || engine.Status.EngineStatus == operatorV1.EngineStatusStopped
+}
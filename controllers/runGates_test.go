@@ -0,0 +1,377 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// getEvents returns the events recorded so far by the fake recorder
+func getEvents(r *ChaosScheduleReconciler) []string {
+	var events []string
+	for {
+		select {
+		case event := <-r.Recorder.(*record.FakeRecorder).Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+// newMutexSchedule returns a repeat schedule of the mutex group
+func newMutexSchedule(name string, group schedulerV1.MutexGroup) *schedulerV1.ChaosSchedule {
+	schedule := newTestSchedule(name, everyMinute())
+	schedule.UID = types.UID(name + "-uid")
+	schedule.Spec.MutexGroup = &group
+	return schedule
+}
+
+// newMutexEngine returns an engine of the schedule labelled with the mutex group, finished with the given verdict if any
+func newMutexEngine(schedule *schedulerV1.ChaosSchedule, verdict string) *operatorV1.ChaosEngine {
+	engine := newTestEngine(schedule.Name+"-engine", verdict)
+	engine.Labels["chaosUID"] = string(schedule.UID)
+	engine.Labels["chaosMutexGroup"] = schedule.Spec.MutexGroup.Name
+	return engine
+}
+
+// staleReader serves the objects as they were when it was created, the way a worker which read them right before
+// another one updated them sees them
+type staleReader struct {
+	client.Reader
+	objects map[types.NamespacedName]client.Object
+}
+
+func (r *staleReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if stale, found := r.objects[key]; found {
+		stale.(*coordinationv1.Lease).DeepCopyInto(obj.(*coordinationv1.Lease))
+		return nil
+	}
+	return r.Reader.Get(ctx, key, obj)
+}
+
+func TestCheckDependencies(t *testing.T) {
+	tests := []struct {
+		name       string
+		dependency *schedulerV1.ChaosSchedule
+		wantPassed bool
+		wantReason string
+	}{
+		{
+			name:       "dependency not found",
+			wantReason: "DependencyNotFound",
+		},
+		{
+			name: "dependency running",
+			dependency: &schedulerV1.ChaosSchedule{
+				Status: schedulerV1.ChaosScheduleStatus{Active: newTestRun("100", "a").Engines},
+			},
+			wantReason: "DependencyRunning",
+		},
+		{
+			name:       "dependency never run",
+			dependency: &schedulerV1.ChaosSchedule{},
+			wantReason: "DependencyNotRun",
+		},
+		{
+			name: "most recent run of the dependency failed",
+			dependency: &schedulerV1.ChaosSchedule{Status: schedulerV1.ChaosScheduleStatus{History: []schedulerV1.RunStatus{
+				{RunID: "100", Verdict: "Pass"},
+				{RunID: "200", Verdict: "Fail"},
+			}}},
+			wantReason: "DependencyFailed",
+		},
+		{
+			name: "most recent run of the dependency passed",
+			dependency: &schedulerV1.ChaosSchedule{Status: schedulerV1.ChaosScheduleStatus{History: []schedulerV1.RunStatus{
+				{RunID: "100", Verdict: "Fail"},
+				{RunID: "200", Verdict: "Pass"},
+			}}},
+			wantPassed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := newTestSchedule("dependent", everyMinute())
+			schedule.Spec.DependsOn = []schedulerV1.ScheduleReference{{Name: "upstream", Namespace: "litmus"}}
+			var objects []client.Object
+			if tt.dependency != nil {
+				tt.dependency.Name, tt.dependency.Namespace = "upstream", "litmus"
+				objects = append(objects, tt.dependency)
+			}
+			s := &reconcileScheduler{r: newFakeReconciler(t, objects...), reqLogger: chaosTypes.Log}
+
+			gate, err := s.checkDependencies(&chaosTypes.SchedulerInfo{Instance: schedule})
+			if err != nil {
+				t.Fatalf("checkDependencies() error = %v", err)
+			}
+			if gate.passed != tt.wantPassed || gate.reason != tt.wantReason {
+				t.Fatalf("checkDependencies() = %+v, want passed %v with reason %q", gate, tt.wantPassed, tt.wantReason)
+			}
+			if !gate.passed && (gate.skip || gate.policy != "dependsOn" || !strings.Contains(gate.message, "litmus/upstream")) {
+				t.Fatalf("checkDependencies() = %+v, want the run deferred by the dependsOn policy", gate)
+			}
+		})
+	}
+}
+
+func TestCheckMutexGroup(t *testing.T) {
+	start := at(10, 0)
+	group := schedulerV1.MutexGroup{Name: "Storage_Chaos"}
+	leaseKey := types.NamespacedName{Name: "chaos-mutex-storage-chaos", Namespace: "default"}
+
+	tests := []struct {
+		name string
+		// the policy of the group of the schedule under test
+		policy schedulerV1.MutexPolicy
+		// engines are created for the holder of the group
+		holderEngine string
+		// elapsed is the time elapsed since the holder acquired the group
+		elapsed    time.Duration
+		wantPassed bool
+		wantSkip   bool
+		wantReason string
+	}{
+		{
+			name:         "group held by a schedule whose engine is active",
+			holderEngine: "Initialized",
+			elapsed:      10 * time.Minute,
+			wantReason:   "MutexGroupBusy",
+		},
+		{
+			name:         "group held by a schedule whose engine is active, with the skip policy",
+			policy:       schedulerV1.SkipRun,
+			holderEngine: "Initialized",
+			elapsed:      10 * time.Minute,
+			wantSkip:     true,
+			wantReason:   "MutexGroupBusy",
+		},
+		{
+			name:       "group just acquired by a schedule whose engine is not visible yet",
+			elapsed:    10 * time.Second,
+			wantReason: "MutexGroupBusy",
+		},
+		{
+			name:         "group held by a schedule whose engine is done",
+			holderEngine: "Pass",
+			elapsed:      10 * time.Minute,
+			wantPassed:   true,
+		},
+		{
+			name:       "group held by a schedule without any engine",
+			elapsed:    10 * time.Minute,
+			wantPassed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holder := newMutexSchedule("holder", group)
+			schedule := newMutexSchedule("waiter", schedulerV1.MutexGroup{Name: group.Name, Policy: tt.policy})
+			objects := []client.Object{holder, schedule}
+			if tt.holderEngine != "" {
+				verdict := tt.holderEngine
+				if verdict == "Initialized" {
+					verdict = ""
+				}
+				objects = append(objects, newMutexEngine(holder, verdict))
+			}
+			r := newFakeReconciler(t, objects...)
+			fakeClock := clocktesting.NewFakeClock(start)
+			r.Clock = fakeClock
+
+			// the holder acquires the group first
+			holderScheduler := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}
+			if gate, err := holderScheduler.acquireMutexGroup(&chaosTypes.SchedulerInfo{Instance: holder}, holder.Spec.MutexGroup); err != nil || !gate.passed {
+				t.Fatalf("the holder did not acquire the free group: %+v, %v", gate, err)
+			}
+			fakeClock.Step(tt.elapsed)
+
+			s := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}
+			gate, err := s.checkMutexGroup(&chaosTypes.SchedulerInfo{Instance: schedule})
+			if err != nil {
+				t.Fatalf("checkMutexGroup() error = %v", err)
+			}
+			if gate.passed != tt.wantPassed || gate.skip != tt.wantSkip || gate.reason != tt.wantReason {
+				t.Fatalf("checkMutexGroup() = %+v, want passed %v, skip %v and reason %q", gate, tt.wantPassed, tt.wantSkip, tt.wantReason)
+			}
+
+			lease := &coordinationv1.Lease{}
+			if err := r.Client.Get(context.TODO(), leaseKey, lease); err != nil {
+				t.Fatal(err)
+			}
+			wantHolder, wantTransitions := "holder-uid", int32(0)
+			if tt.wantPassed {
+				wantHolder, wantTransitions = "waiter-uid", 1
+			}
+			if *lease.Spec.HolderIdentity != wantHolder || getLeaseTransitions(lease) != wantTransitions {
+				t.Fatalf("lease held by %s after %d transitions, want %s after %d", *lease.Spec.HolderIdentity, getLeaseTransitions(lease), wantHolder, wantTransitions)
+			}
+		})
+	}
+}
+
+func TestCheckMutexGroupConcurrently(t *testing.T) {
+	group := schedulerV1.MutexGroup{Name: "storage", Scope: schedulerV1.ClusterMutex}
+	holder := newMutexSchedule("holder", group)
+	first := newMutexSchedule("first", group)
+	second := newMutexSchedule("second", group)
+	r := newFakeReconciler(t, holder, first, second)
+	r.MutexLeaseNamespace = "litmus"
+	fakeClock := clocktesting.NewFakeClock(at(10, 0))
+	r.Clock = fakeClock
+
+	holderScheduler := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}
+	if gate, err := holderScheduler.checkMutexGroup(&chaosTypes.SchedulerInfo{Instance: holder}); err != nil || !gate.passed {
+		t.Fatalf("the holder did not acquire the free group: %+v, %v", gate, err)
+	}
+	fakeClock.Step(10 * time.Minute)
+
+	// both workers read the lease of the group before either of them updates it
+	leaseKey := types.NamespacedName{Name: "chaos-mutex-storage", Namespace: "litmus"}
+	lease := &coordinationv1.Lease{}
+	if err := r.Client.Get(context.TODO(), leaseKey, lease); err != nil {
+		t.Fatalf("the lease of a cluster group is not in the namespace of the scheduler: %v", err)
+	}
+	r.APIReader = &staleReader{Reader: r.Client, objects: map[types.NamespacedName]client.Object{leaseKey: lease}}
+
+	firstScheduler := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}
+	if gate, err := firstScheduler.checkMutexGroup(&chaosTypes.SchedulerInfo{Instance: first}); err != nil || !gate.passed {
+		t.Fatalf("the first worker did not acquire the group: %+v, %v", gate, err)
+	}
+	secondScheduler := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}
+	if _, err := secondScheduler.checkMutexGroup(&chaosTypes.SchedulerInfo{Instance: second}); !k8serrors.IsConflict(err) {
+		t.Fatalf("the second worker got %v, want a conflict as the group has been acquired in the meantime", err)
+	}
+
+	// the second worker reconciles the schedule again, and sees the group acquired by the first one
+	r.APIReader = nil
+	gate, err := secondScheduler.checkMutexGroup(&chaosTypes.SchedulerInfo{Instance: second})
+	if err != nil || gate.passed || gate.reason != "MutexGroupBusy" || !strings.Contains(gate.message, "first-uid") {
+		t.Fatalf("checkMutexGroup() = %+v, %v, want the group busy with the first schedule", gate, err)
+	}
+}
+
+func TestCheckMutexGroupWithActiveEngine(t *testing.T) {
+	group := schedulerV1.MutexGroup{Name: "storage"}
+	other := newMutexSchedule("other", group)
+	schedule := newMutexSchedule("schedule", group)
+	// the engine of the schedule itself does not hold the group
+	r := newFakeReconciler(t, other, schedule, newMutexEngine(other, ""), newMutexEngine(schedule, ""))
+	s := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}
+
+	gate, err := s.checkMutexGroup(&chaosTypes.SchedulerInfo{Instance: schedule})
+	if err != nil || gate.passed || !strings.Contains(gate.message, "engine default/engine-other-engine") {
+		t.Fatalf("checkMutexGroup() = %+v, %v, want the group busy with the engine of the other schedule", gate, err)
+	}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "chaos-mutex-storage", Namespace: "default"}, &coordinationv1.Lease{}); !k8serrors.IsNotFound(err) {
+		t.Fatalf("the lease of the busy group has been created: %v", err)
+	}
+}
+
+func TestHoldRun(t *testing.T) {
+	scheduledTime := at(10, 0)
+	busy := gateResult{policy: "mutexGroup/defer", reason: "MutexGroupBusy", message: "engine default/other of mutex group storage is active"}
+
+	tests := []struct {
+		name     string
+		schedule schedulerV1.Schedule
+		skip     bool
+		// last is the run skipped or deferred by an earlier reconcile
+		last         *schedulerV1.SkippedRun
+		wantResult   reconcile.Result
+		wantEvent    string
+		wantState    schedulerV1.ScheduleState
+		wantDeferred bool
+	}{
+		{
+			name:         "deferred run",
+			schedule:     everyMinute(),
+			wantResult:   reconcile.Result{RequeueAfter: gateRetryInterval},
+			wantEvent:    "DeferredRun",
+			wantDeferred: true,
+		},
+		{
+			name:     "run deferred again for the same reason",
+			schedule: everyMinute(),
+			last: &schedulerV1.SkippedRun{
+				ScheduledTime: &metav1.Time{Time: scheduledTime},
+				Deferred:      true,
+				Reason:        "MutexGroupBusy",
+			},
+			wantResult:   reconcile.Result{RequeueAfter: gateRetryInterval},
+			wantDeferred: true,
+		},
+		{
+			name:       "skipped run of a repeat schedule",
+			schedule:   everyMinute(),
+			skip:       true,
+			wantResult: reconcile.Result{Requeue: true},
+			wantEvent:  "SkippedRun",
+		},
+		{
+			name:       "skipped run of a now schedule",
+			schedule:   schedulerV1.Schedule{Now: true},
+			skip:       true,
+			wantResult: reconcile.Result{},
+			wantEvent:  "SkippedRun",
+			wantState:  schedulerV1.StateCompleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := newTestSchedule("schedule", tt.schedule)
+			schedule.Status.LastSkippedRun = tt.last
+			r := newFakeReconciler(t, schedule)
+			r.Clock = clocktesting.NewFakeClock(scheduledTime.Add(time.Second))
+			s := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}
+			cs := &chaosTypes.SchedulerInfo{Instance: &schedulerV1.ChaosSchedule{}}
+			if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, cs.Instance); err != nil {
+				t.Fatal(err)
+			}
+
+			gate := busy
+			gate.skip = tt.skip
+			result, err := s.holdRun(cs, scheduledTime, gate, reconcile.Request{})
+			if err != nil {
+				t.Fatalf("holdRun() error = %v", err)
+			}
+			if result != tt.wantResult {
+				t.Fatalf("holdRun() = %+v, want %+v", result, tt.wantResult)
+			}
+
+			events := getEvents(r)
+			if tt.wantEvent == "" && len(events) != 0 || tt.wantEvent != "" && (len(events) != 1 || !strings.Contains(events[0], tt.wantEvent)) {
+				t.Fatalf("events = %v, want %q", events, tt.wantEvent)
+			}
+
+			updated := &schedulerV1.ChaosSchedule{}
+			if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, updated); err != nil {
+				t.Fatal(err)
+			}
+			skipped := updated.Status.LastSkippedRun
+			if skipped == nil || skipped.Deferred != tt.wantDeferred || skipped.Reason != "MutexGroupBusy" || !skipped.ScheduledTime.Time.Equal(scheduledTime) {
+				t.Fatalf("lastSkippedRun = %+v, want the run held by the mutex group", skipped)
+			}
+			if updated.Spec.ScheduleState != tt.wantState {
+				t.Fatalf("scheduleState = %q, want %q", updated.Spec.ScheduleState, tt.wantState)
+			}
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package controllers
 
 import (
+	"context"
 	"errors"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"fmt"
 	"time"
 
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	ref "k8s.io/client-go/tools/reference"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -23,7 +28,7 @@ func schedule(schedulerReconcile *reconcileScheduler, scheduler *chaosTypes.Sche
 
 	} else if scheduler.Instance.Spec.Schedule.Once != nil {
 		schedulerReconcile.reqLogger.Info("Current scheduler type derived is ", "schedulerType", "once")
-		scheduleTime := time.Now()
+		scheduleTime := schedulerReconcile.r.now()
 		startDuration := scheduler.Instance.Spec.Schedule.Once.ExecutionTime.Local().Sub(scheduleTime)
 
 		if startDuration.Seconds() < 0 {
@@ -34,8 +39,8 @@ func schedule(schedulerReconcile *reconcileScheduler, scheduler *chaosTypes.Sche
 		schedulerReconcile.reqLogger.Info("Time left to schedule the engine", "Duration", startDuration)
 		return reconcile.Result{RequeueAfter: startDuration}, nil
 
-	} else if scheduler.Instance.Spec.Schedule.Repeat != nil {
-		schedulerReconcile.reqLogger.Info("Current scheduler type derived is ", "schedulerType", "repeat")
+	} else if scheduler.Instance.Spec.Schedule.Repeat != nil || scheduler.Instance.Spec.Schedule.RRule != nil {
+		schedulerReconcile.reqLogger.Info("Current scheduler type derived is ", "schedulerType", getScheduleType(scheduler))
 		/* StartDuration is the duration between current time
 		 * and the scheduled time to start the chaos which is
 		 * being used by reconciler to reque this resource after
@@ -44,15 +49,15 @@ func schedule(schedulerReconcile *reconcileScheduler, scheduler *chaosTypes.Sche
 		 */
 
 		var startTime *metav1.Time
-		if scheduler.Instance.Spec.Schedule.Repeat.TimeRange != nil {
-			startTime = scheduler.Instance.Spec.Schedule.Repeat.TimeRange.StartTime
+		if timeRange := getTimeRange(scheduler); timeRange != nil {
+			startTime = timeRange.StartTime
 		}
 
 		if startTime == nil {
 			startTime = &scheduler.Instance.CreationTimestamp
 		}
 
-		scheduleTime := time.Now()
+		scheduleTime := schedulerReconcile.r.now()
 		startDuration := startTime.Local().Sub(scheduleTime)
 		if startDuration.Seconds() < 0 {
 			return schedulerReconcile.createEngineRepeat(scheduler, request)
@@ -62,7 +67,7 @@ func schedule(schedulerReconcile *reconcileScheduler, scheduler *chaosTypes.Sche
 
 	}
 
-	return reconcile.Result{}, errors.New("ScheduleType should be one of ('now', 'once', 'repeat')")
+	return reconcile.Result{}, errors.New("ScheduleType should be one of ('now', 'once', 'repeat', 'rrule')")
 }
 
 func (r *ChaosScheduleReconciler) getRef(object runtime.Object) (*corev1.ObjectReference, error) {
@@ -70,7 +75,21 @@ func (r *ChaosScheduleReconciler) getRef(object runtime.Object) (*corev1.ObjectR
 }
 
 // getEngineFromTemplate makes an Engine from a Schedule
-func (r *ChaosScheduleReconciler) getEngineFromTemplate(cs *chaosTypes.SchedulerInfo) (*operatorV1.ChaosEngine, error) {
+func (r *ChaosScheduleReconciler) getEngineFromTemplate(ctx context.Context, cs *chaosTypes.SchedulerInfo) (*operatorV1.ChaosEngine, error) {
+
+	engine, err := r.getEngineFromSpec(cs, cs.Instance.Spec.EngineTemplateSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.applyTargetRotation(ctx, cs, engine); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// getEngineFromSpec makes an Engine owned by the Schedule from the given engine spec
+func (r *ChaosScheduleReconciler) getEngineFromSpec(cs *chaosTypes.SchedulerInfo, spec operatorV1.ChaosEngineSpec) (*operatorV1.ChaosEngine, error) {
 
 	labels := map[string]string{
 		"app":      "chaos-engine",
@@ -79,6 +98,9 @@ func (r *ChaosScheduleReconciler) getEngineFromTemplate(cs *chaosTypes.Scheduler
 	for index, element := range cs.Instance.Labels {
 		labels[index] = element
 	}
+	if cs.Instance.Spec.MutexGroup != nil {
+		labels["chaosMutexGroup"] = cs.Instance.Spec.MutexGroup.Name
+	}
 
 	engine := &operatorV1.ChaosEngine{}
 
@@ -88,11 +110,47 @@ func (r *ChaosScheduleReconciler) getEngineFromTemplate(cs *chaosTypes.Scheduler
 	engine.Namespace = cs.Instance.Namespace
 	engine.Labels = labels
 	engine.Annotations = cs.Instance.Annotations
-	engine.Spec = cs.Instance.Spec.EngineTemplateSpec
+	engine.Spec = *spec.DeepCopy()
 	engine.Spec.EngineState = operatorV1.EngineStateActive
-	
+
 	if err := controllerutil.SetControllerReference(cs.Instance, engine, r.Scheme); err != nil {
 		return nil, err
 	}
 	return engine, nil
 }
+
+// getEngineName returns the name of the engine of the run scheduled at the given time
+// The name is derived from the scheduled time so that the engine acts as a lock: a reconcile which does not see the
+// status update of an earlier one, e.g. after a failover of the leader, adopts the engine instead of creating another one
+func getEngineName(cs *chaosTypes.SchedulerInfo, scheduledTime time.Time) string {
+	return fmt.Sprintf("%s-%d", cs.Instance.Name, getTimeHash(scheduledTime))
+}
+
+// createOrAdoptEngine creates the engine of the run, or adopts the engine of the same name created by an earlier
+// reconcile whose status update was lost. It returns whether the engine has been adopted
+func (schedulerReconcile *reconcileScheduler) createOrAdoptEngine(cs *chaosTypes.SchedulerInfo, engine *operatorV1.ChaosEngine, runID string) (bool, error) {
+
+	err := schedulerReconcile.createEngine(cs, engine, runID)
+	if !k8serrors.IsAlreadyExists(err) {
+		return false, err
+	}
+	if err := schedulerReconcile.r.Client.Get(schedulerReconcile.getContext(), types.NamespacedName{Name: engine.Name, Namespace: engine.Namespace}, engine); err != nil {
+		return false, err
+	}
+	if err := schedulerReconcile.adoptEngine(cs, engine, runID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// adoptEngine adopts the engine of the run created by an earlier reconcile whose status update was lost
+func (schedulerReconcile *reconcileScheduler) adoptEngine(cs *chaosTypes.SchedulerInfo, engine *operatorV1.ChaosEngine, runID string) error {
+
+	// an engine of the same name which is not owned by the schedule is never adopted
+	if engine.Labels["chaosUID"] != string(cs.Instance.UID) {
+		return fmt.Errorf("engine %s/%s already exists and does not belong to the schedule", engine.Namespace, engine.Name)
+	}
+	schedulerReconcile.reqLogger.Info("Adopted the engine created by an earlier reconcile", "ChaosEngine Name", engine.Name, "RunID", runID)
+	schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "AdoptedEngine", "Adopted engine %v created by an earlier reconcile", engine.Name)
+	return nil
+}
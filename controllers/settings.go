@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	configV1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// Settings are the controller-wide defaults of the schedules, they can be updated while the controller is running
+type Settings struct {
+	mu                sync.RWMutex
+	concurrencyPolicy schedulerV1.ConcurrencyPolicy
+	historyLimit      int32
+	location          *time.Location
+	prometheusURL     string
+}
+
+// NewSettings builds the settings from the configuration of the scheduler
+func NewSettings(config *configV1.ChaosSchedulerConfig) (*Settings, error) {
+	settings := &Settings{}
+	if err := settings.Update(config); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Update replaces the settings with the ones of the configuration
+func (s *Settings) Update(config *configV1.ChaosSchedulerConfig) error {
+
+	// time.LoadLocation returns UTC for an empty name, the schedules are evaluated in the local time zone by default
+	location := time.Local
+	if config.Defaults.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(config.Defaults.TimeZone); err != nil {
+			return err
+		}
+	}
+	historyLimit := int32(defaultHistoryLimit)
+	if config.Defaults.HistoryLimit != nil {
+		historyLimit = *config.Defaults.HistoryLimit
+	}
+	concurrencyPolicy := config.Defaults.ConcurrencyPolicy
+	if concurrencyPolicy == "" {
+		concurrencyPolicy = schedulerV1.ForbidConcurrent
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.concurrencyPolicy = concurrencyPolicy
+	s.historyLimit = historyLimit
+	s.location = location
+	s.prometheusURL = config.PrometheusURL
+	return nil
+}
+
+// ConcurrencyPolicy returns the concurrency policy of the schedules which do not set it
+func (s *Settings) ConcurrencyPolicy() schedulerV1.ConcurrencyPolicy {
+	if s == nil {
+		return schedulerV1.ForbidConcurrent
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.concurrencyPolicy
+}
+
+// HistoryLimit returns the history limit of the schedules which do not set it
+func (s *Settings) HistoryLimit() int32 {
+	if s == nil {
+		return defaultHistoryLimit
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.historyLimit
+}
+
+// Location returns the time zone in which the repeat schedules are evaluated
+func (s *Settings) Location() *time.Location {
+	if s == nil {
+		return time.Local
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.location
+}
+
+// PrometheusURL returns the address of the Prometheus server of the sloGuard
+func (s *Settings) PrometheusURL() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.prometheusURL
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	configV1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/config"
+)
+
+func TestSettingsLocation(t *testing.T) {
+	settings, err := NewSettings(&configV1.ChaosSchedulerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Location() != time.Local {
+		t.Fatalf("location = %v, want the local time zone when timeZone is empty", settings.Location())
+	}
+	if err := settings.Update(&configV1.ChaosSchedulerConfig{Defaults: configV1.ScheduleDefaults{TimeZone: "Europe/Paris"}}); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Location().String() != "Europe/Paris" {
+		t.Fatalf("location = %v, want Europe/Paris", settings.Location())
+	}
+}
+
+// TestWatcherReloadsSettings updates the mounted config file, the way the kubelet does once the ConfigMap is updated,
+// and checks that the reloadable settings follow
+func TestWatcherReloadsSettings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	write := func(content string) {
+		// the kubelet swaps the file of the ConfigMap volume through a symlink, the file is replaced at once
+		tmp := filepath.Join(dir, "config.tmp")
+		if err := ioutil.WriteFile(tmp, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`apiVersion: config.litmuschaos.io/v1alpha1
+kind: ChaosSchedulerConfig
+logging:
+  level: info
+defaults:
+  historyLimit: 10
+prometheusURL: http://prometheus:9090
+`)
+
+	current, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, err := NewSettings(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	level := uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+	watcher := &config.Watcher{
+		Path:     path,
+		Current:  current,
+		Interval: 10 * time.Millisecond,
+		OnReload: func(reloaded *configV1.ChaosSchedulerConfig) {
+			if err := settings.Update(reloaded); err != nil {
+				t.Errorf("unable to apply the reloaded config: %v", err)
+			}
+			parsed, _ := config.ParseLevel(reloaded.Logging.Level)
+			level.SetLevel(parsed)
+		},
+	}
+	ctx, cancel := context.WithCancel(context.TODO())
+	done := make(chan error)
+	go func() { done <- watcher.Start(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	}()
+	// the watcher compares the file with the one it read when started
+	time.Sleep(50 * time.Millisecond)
+
+	write(`apiVersion: config.litmuschaos.io/v1alpha1
+kind: ChaosSchedulerConfig
+logging:
+  level: debug
+defaults:
+  historyLimit: 3
+prometheusURL: http://thanos:9090
+`)
+	deadline := time.Now().Add(5 * time.Second)
+	for settings.HistoryLimit() != 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if settings.HistoryLimit() != 3 || settings.PrometheusURL() != "http://thanos:9090" || level.Level() != zapcore.DebugLevel {
+		t.Fatalf("historyLimit %d, prometheusURL %q and level %v, want the reloaded ones",
+			settings.HistoryLimit(), settings.PrometheusURL(), level.Level())
+	}
+
+	// an invalid file is ignored, the current settings are kept
+	write(`apiVersion: config.litmuschaos.io/v1alpha1
+kind: ChaosSchedulerConfig
+defaults:
+  historyLimit: -1
+`)
+	time.Sleep(100 * time.Millisecond)
+	if settings.HistoryLimit() != 3 || level.Level() != zapcore.DebugLevel {
+		t.Fatalf("historyLimit %d and level %v, want the invalid file to be ignored", settings.HistoryLimit(), level.Level())
+	}
+}
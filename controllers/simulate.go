@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// maxProjectedRuns bounds the number of runs projected for a schedule
+const maxProjectedRuns = 100000
+
+// Projection is the outcome of the simulation of a schedule over a window of time
+type Projection struct {
+	// CronString is the cron derived from the repeat schedule or the rule of the rrule schedule, it is empty for the other types
+	CronString string
+	// FireTimes are the scheduled times of the runs within the window
+	FireTimes []time.Time
+	// Truncated is true if the projection stopped at maxProjectedRuns
+	Truncated bool
+}
+
+// ProjectFireTimes returns the times at which the schedule fires between from and until, as per the scheduling
+// functions of the reconciler run against a virtual clock. Every run is assumed to be finished before the next one is
+// due and the run gates are assumed to pass. A schedule without a creation time is considered as created at from
+func ProjectFireTimes(instance *schedulerV1.ChaosSchedule, settings *Settings, from, until time.Time) (*Projection, error) {
+
+	cs := &chaosTypes.SchedulerInfo{Instance: instance.DeepCopy()}
+	if cs.Instance.CreationTimestamp.IsZero() {
+		cs.Instance.CreationTimestamp.Time = from
+	}
+	projection := &Projection{}
+
+	switch cs.Instance.Spec.ScheduleState {
+	case schedulerV1.StateHalted, schedulerV1.StateStopped, schedulerV1.StateCompleted:
+		return projection, nil
+	}
+
+	created := cs.Instance.CreationTimestamp.Time
+	schedule := cs.Instance.Spec.Schedule
+	switch {
+	case schedule.Now:
+		projection.add(created, from, until)
+		return projection, nil
+	case schedule.Once != nil:
+		// a once schedule whose execution time is already passed runs right away
+		fireTime := schedule.Once.ExecutionTime.Time
+		if fireTime.Before(created) {
+			fireTime = created
+		}
+		projection.add(fireTime, from, until)
+		return projection, nil
+	case schedule.Repeat == nil && schedule.RRule == nil:
+		return nil, fmt.Errorf("ScheduleType should be one of ('now', 'once', 'repeat', 'rrule')")
+	}
+
+	schedulerReconcile := &reconcileScheduler{
+		r:         &ChaosScheduleReconciler{Settings: settings},
+		reqLogger: logr.Discard(),
+	}
+	cronString, err := schedulerReconcile.getRecurrence(cs)
+	if err != nil {
+		return nil, err
+	}
+	projection.CronString = cronString
+
+	// the repeat schedule is evaluated from the start of its time range, see schedule
+	now := from
+	var endTime *time.Time
+	if timeRange := getTimeRange(cs); timeRange != nil {
+		if timeRange.StartTime != nil && now.Before(timeRange.StartTime.Time) {
+			now = timeRange.StartTime.Time
+		}
+		if timeRange.EndTime != nil {
+			endTime = &timeRange.EndTime.Time
+		}
+	}
+	if now.Before(created) {
+		now = created
+	}
+
+	for !now.After(until) && (endTime == nil || !now.After(*endTime)) {
+		scheduledTime, err := schedulerReconcile.getRecentUnmetScheduleTime(cs, cronString, now)
+		if err != nil {
+			return nil, err
+		}
+		// the recurrence has no more runs
+		if scheduledTime.IsZero() {
+			break
+		}
+		// the schedule is requeued until the scheduled time
+		if scheduledTime.After(now) {
+			now = scheduledTime
+			continue
+		}
+		if len(projection.FireTimes) == maxProjectedRuns {
+			projection.Truncated = true
+			break
+		}
+		projection.add(scheduledTime, from, until)
+		cs.Instance.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	}
+	return projection, nil
+}
+
+// add records the fire time if it is within the window
+func (p *Projection) add(fireTime, from, until time.Time) {
+	if fireTime.Before(from) || fireTime.After(until) {
+		return
+	}
+	p.FireTimes = append(p.FireTimes, fireTime)
+}
@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+func TestProjectFireTimes(t *testing.T) {
+	everyTwoHours := schedulerV1.ScheduleRepeatProperties{
+		MinChaosInterval: &schedulerV1.MinChaosInterval{Hour: &schedulerV1.Hour{EveryNthHour: 2}},
+	}
+
+	tests := []struct {
+		name     string
+		schedule schedulerV1.Schedule
+		state    schedulerV1.ScheduleState
+		want     []time.Time
+		wantCron string
+		wantErr  bool
+	}{
+		{
+			name:     "now",
+			schedule: schedulerV1.Schedule{Now: true},
+			want:     []time.Time{at(8, 0)},
+		},
+		{
+			name:     "once",
+			schedule: schedulerV1.Schedule{Once: &schedulerV1.ScheduleOnce{ExecutionTime: metav1.Time{Time: at(15, 30)}}},
+			want:     []time.Time{at(15, 30)},
+		},
+		{
+			name:     "once after the window",
+			schedule: schedulerV1.Schedule{Once: &schedulerV1.ScheduleOnce{ExecutionTime: metav1.Time{Time: at(23, 30)}}},
+		},
+		{
+			name: "repeat within the working hours",
+			schedule: schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+				Properties: everyTwoHours,
+				WorkHours:  &schedulerV1.WorkHours{IncludedHours: "9-17"},
+			}},
+			want:     []time.Time{at(9, 0), at(11, 0), at(13, 0), at(15, 0), at(17, 0)},
+			wantCron: "0 9-17/2 * * *",
+		},
+		{
+			name: "repeat until the end of the time range",
+			schedule: schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+				Properties: everyTwoHours,
+				TimeRange:  &schedulerV1.TimeRange{StartTime: metaTime(at(9, 30)), EndTime: metaTime(at(14, 0))},
+			}},
+			want:     []time.Time{at(9, 30), at(10, 0), at(12, 0), at(14, 0)},
+			wantCron: "0 */2 * * *",
+		},
+		{
+			name: "repeat on excluded days",
+			schedule: schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+				Properties: everyTwoHours,
+				WorkDays:   &schedulerV1.WorkDays{IncludedDays: "Sat,Sun"},
+			}},
+			wantCron: "0 */2 * * Sat,Sun",
+		},
+		{
+			name: "rrule until its count",
+			schedule: schedulerV1.Schedule{RRule: &schedulerV1.ScheduleRRule{
+				Rule:          "FREQ=HOURLY;INTERVAL=3;COUNT=5",
+				StartTime:     metaTime(at(5, 0)),
+				ExcludedTimes: []metav1.Time{{Time: at(11, 0)}},
+			}},
+			want:     []time.Time{at(8, 0), at(14, 0), at(17, 0)},
+			wantCron: "FREQ=HOURLY;INTERVAL=3;COUNT=5",
+		},
+		{
+			name:     "halted",
+			schedule: schedulerV1.Schedule{Now: true},
+			state:    schedulerV1.StateHalted,
+		},
+		{
+			name:     "repeat without interval",
+			schedule: schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{}},
+			wantErr:  true,
+		},
+	}
+
+	settings := newTestScheduler(t).r.Settings
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := newTestSchedule("simulated", tt.schedule)
+			schedule.Spec.ScheduleState = tt.state
+
+			projection, err := ProjectFireTimes(schedule, settings, at(8, 0), at(20, 0))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProjectFireTimes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if projection.CronString != tt.wantCron {
+				t.Fatalf("ProjectFireTimes() cron = %q, want %q", projection.CronString, tt.wantCron)
+			}
+			if len(projection.FireTimes) != len(tt.want) {
+				t.Fatalf("ProjectFireTimes() = %v, want %v", projection.FireTimes, tt.want)
+			}
+			for i := range tt.want {
+				if !projection.FireTimes[i].Equal(tt.want[i]) {
+					t.Fatalf("ProjectFireTimes() = %v, want %v", projection.FireTimes, tt.want)
+				}
+			}
+		})
+	}
+}
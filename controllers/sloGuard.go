@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/audit"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+const (
+	// defaultSLOGuardInterval is the interval at which the sloGuard is polled when it is not set
+	defaultSLOGuardInterval = 30 * time.Second
+	// defaultSLOGuardTimeout is the timeout of the query when it is not set
+	defaultSLOGuardTimeout = 10 * time.Second
+)
+
+// prometheusResponse is the response of the instant query api of Prometheus
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// checkSLOGuard evaluates the sloGuard right before the run, the run is skipped if the SLO is breached
+func (schedulerReconcile *reconcileScheduler) checkSLOGuard(cs *chaosTypes.SchedulerInfo) (gateResult, error) {
+
+	if cs.Instance.Spec.SLOGuard == nil {
+		return gateResult{passed: true}, nil
+	}
+
+	status := schedulerReconcile.r.evaluateSLOGuard(schedulerReconcile.getContext(), cs)
+	switch {
+	case status.Message != "":
+		return gateResult{skip: true, policy: "sloGuard", reason: "SLOGuardUnavailable", message: status.Message}, nil
+	case status.Breached:
+		return gateResult{skip: true, policy: "sloGuard", reason: "SLOGuardBreached", message: getBreachMessage(cs.Instance.Spec.SLOGuard, status.LastValue)}, nil
+	}
+	return gateResult{passed: true}, nil
+}
+
+// pollSLOGuard evaluates the sloGuard while the engines are active and stops them once the SLO is breached
+// The schedule is requeued by the end of the interval, unless the given result requeues it earlier
+func (schedulerReconcile *reconcileScheduler) pollSLOGuard(cs *chaosTypes.SchedulerInfo, result reconcile.Result) (reconcile.Result, error) {
+
+	guard := cs.Instance.Spec.SLOGuard
+	if guard == nil || len(cs.Instance.Status.Active) == 0 || cs.Instance.DeletionTimestamp != nil {
+		return result, nil
+	}
+
+	interval := defaultSLOGuardInterval
+	if guard.Interval != nil {
+		interval = guard.Interval.Duration
+	}
+
+	// the updates of the schedule trigger a reconcile as well, the query is only evaluated once per interval
+	if last := cs.Instance.Status.SLOGuard; last != nil && last.LastCheckTime != nil {
+		if wait := interval - schedulerReconcile.r.since(last.LastCheckTime.Time); wait > 0 {
+			return requeueWithin(result, wait), nil
+		}
+	}
+
+	status := schedulerReconcile.r.evaluateSLOGuard(schedulerReconcile.getContext(), cs)
+	switch {
+	case status.Message != "":
+		schedulerReconcile.reqLogger.Info("Unable to evaluate the sloGuard", "Message", status.Message)
+		schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "SLOGuardUnavailable", "Unable to evaluate the sloGuard: %s", status.Message)
+	case status.Breached:
+		message := getBreachMessage(guard, status.LastValue)
+		schedulerReconcile.reqLogger.Info("SLO breached, stopping the active engines", "Message", message)
+		stopped, err := schedulerReconcile.r.stopActiveEngines(schedulerReconcile.getContext(), cs)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if len(stopped) != 0 {
+			schedulerReconcile.r.Recorder.Eventf(cs.Instance, corev1.EventTypeWarning, "SLOGuardBreached", "Stopped engines %s: %s", strings.Join(stopped, ", "), message)
+			schedulerReconcile.r.auditStoppedEngines(cs, audit.Stop, "sloGuard", "SLOGuardBreached", message, stopped)
+		}
+	}
+
+	if err := schedulerReconcile.r.Client.Update(schedulerReconcile.getContext(), cs.Instance); err != nil {
+		return reconcile.Result{}, err
+	}
+	return requeueWithin(result, interval), nil
+}
+
+// evaluateSLOGuard runs the query of the sloGuard and records the outcome in the status of the schedule
+func (r *ChaosScheduleReconciler) evaluateSLOGuard(ctx context.Context, cs *chaosTypes.SchedulerInfo) *schedulerV1.SLOGuardStatus {
+
+	guard := cs.Instance.Spec.SLOGuard
+	status := &schedulerV1.SLOGuardStatus{LastCheckTime: &metav1.Time{Time: r.now()}}
+	cs.Instance.Status.SLOGuard = status
+
+	prometheusURL := guard.PrometheusURL
+	if prometheusURL == "" {
+		prometheusURL = r.Settings.PrometheusURL()
+	}
+	if prometheusURL == "" {
+		status.Message = "no prometheus url defined for the sloGuard"
+		return status
+	}
+
+	timeout := defaultSLOGuardTimeout
+	if guard.Timeout != nil {
+		timeout = guard.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	values, err := queryPrometheus(ctx, prometheusURL, guard.Query)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+
+	for _, value := range values {
+		breached, err := isSLOBreached(value, guard.Comparison, guard.Threshold)
+		if err != nil {
+			status.Message = err.Error()
+			return status
+		}
+		// the first breaching sample is reported, otherwise the first sample
+		if breached || status.LastValue == "" {
+			status.LastValue = strconv.FormatFloat(value, 'g', -1, 64)
+		}
+		if breached {
+			status.Breached = true
+			break
+		}
+	}
+	return status
+}
+
+// stopActiveEngines sets the engineState of the active engines to stop, it returns the names of the stopped engines
+func (r *ChaosScheduleReconciler) stopActiveEngines(ctx context.Context, cs *chaosTypes.SchedulerInfo) ([]string, error) {
+
+	var stopped []string
+	for _, ref := range cs.Instance.Status.Active {
+		engine := &operatorV1.ChaosEngine{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, engine)
+		switch {
+		case k8serrors.IsNotFound(err):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		if engine.Spec.EngineState == operatorV1.EngineStateStop || isEngineDone(engine) {
+			continue
+		}
+		engine.Spec.EngineState = operatorV1.EngineStateStop
+		if err := r.Client.Update(ctx, engine); err != nil {
+			return nil, err
+		}
+		stopped = append(stopped, engine.Name)
+	}
+	return stopped, nil
+}
+
+// queryPrometheus runs the instant query against the Prometheus server and returns the values of the samples
+func queryPrometheus(ctx context.Context, prometheusURL, query string) ([]float64, error) {
+
+	endpoint := strings.TrimSuffix(prometheusURL, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query prometheus, err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("unable to decode the prometheus response with status %d, err: %v", resp.StatusCode, err)
+	}
+	if body.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed with status %d, err: %s", resp.StatusCode, body.Error)
+	}
+
+	var samples [][]interface{}
+	switch body.Data.ResultType {
+	case "scalar":
+		var sample []interface{}
+		if err := json.Unmarshal(body.Data.Result, &sample); err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	case "vector":
+		var vector []struct {
+			Value []interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(body.Data.Result, &vector); err != nil {
+			return nil, err
+		}
+		for _, sample := range vector {
+			samples = append(samples, sample.Value)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported result type %q of the prometheus query, should be one of ('scalar', 'vector')", body.Data.ResultType)
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("prometheus query %q returned no data", query)
+	}
+
+	var values []float64
+	for _, sample := range samples {
+		// a sample is a pair of the timestamp and the value as a string
+		if len(sample) != 2 {
+			return nil, fmt.Errorf("invalid sample %v in the prometheus response", sample)
+		}
+		raw, ok := sample[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid sample value %v in the prometheus response", sample[1])
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sample value %q in the prometheus response, err: %v", raw, err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// isSLOBreached compares the value with the threshold
+func isSLOBreached(value float64, comparison schedulerV1.SLOComparison, threshold string) (bool, error) {
+
+	limit, err := strconv.ParseFloat(threshold, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid sloGuard threshold %q, err: %v", threshold, err)
+	}
+
+	switch comparison {
+	case schedulerV1.GreaterThan:
+		return value > limit, nil
+	case schedulerV1.GreaterThanOrEqual:
+		return value >= limit, nil
+	case schedulerV1.LessThan:
+		return value < limit, nil
+	case schedulerV1.LessThanOrEqual:
+		return value <= limit, nil
+	case schedulerV1.Equal:
+		return value == limit, nil
+	case schedulerV1.NotEqual:
+		return value != limit, nil
+	}
+	return false, fmt.Errorf("invalid sloGuard comparison %q, should be one of ('>', '>=', '<', '<=', '==', '!=')", comparison)
+}
+
+// getBreachMessage describes the breach of the sloGuard
+func getBreachMessage(guard *schedulerV1.SLOGuard, value string) string {
+	return fmt.Sprintf("result %s of query %q %s %s", value, guard.Query, guard.Comparison, guard.Threshold)
+}
+
+// requeueWithin makes sure the schedule is requeued within the given duration
+func requeueWithin(result reconcile.Result, after time.Duration) reconcile.Result {
+	if result.Requeue && result.RequeueAfter == 0 {
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > after {
+		result.RequeueAfter = after
+	}
+	return result
+}
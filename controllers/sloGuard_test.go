@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	configV1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// newFakePrometheus serves the given body on the instant query api of Prometheus
+func newFakePrometheus(t *testing.T, status int, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1/query" {
+			t.Errorf("unexpected path %q", req.URL.Path)
+		}
+		if req.URL.Query().Get("query") == "" {
+			t.Errorf("missing query parameter")
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestQueryPrometheus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    []float64
+		wantErr bool
+	}{
+		{
+			name:   "vector",
+			status: http.StatusOK,
+			body:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"job":"a"},"value":[1600000000,"0.5"]},{"metric":{"job":"b"},"value":[1600000000,"2"]}]}}`,
+			want:   []float64{0.5, 2},
+		},
+		{
+			name:   "scalar",
+			status: http.StatusOK,
+			body:   `{"status":"success","data":{"resultType":"scalar","result":[1600000000,"0.01"]}}`,
+			want:   []float64{0.01},
+		},
+		{
+			name:    "empty vector",
+			status:  http.StatusOK,
+			body:    `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "query error",
+			status:  http.StatusBadRequest,
+			body:    `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			wantErr: true,
+		},
+		{
+			name:    "matrix",
+			status:  http.StatusOK,
+			body:    `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid body",
+			status:  http.StatusInternalServerError,
+			body:    `internal error`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakePrometheus(t, tt.status, tt.body)
+			got, err := queryPrometheus(context.Background(), server.URL+"/", "up")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("queryPrometheus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("queryPrometheus() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("queryPrometheus() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateSLOGuard(t *testing.T) {
+	vector := `{"status":"success","data":{"resultType":"vector","result":[{"value":[1600000000,"0.01"]},{"value":[1600000000,"0.2"]}]}}`
+
+	tests := []struct {
+		name         string
+		comparison   schedulerV1.SLOComparison
+		threshold    string
+		wantBreached bool
+		wantValue    string
+		wantMessage  bool
+	}{
+		{name: "breached by one sample", comparison: schedulerV1.GreaterThan, threshold: "0.05", wantBreached: true, wantValue: "0.2"},
+		{name: "within the threshold", comparison: schedulerV1.GreaterThanOrEqual, threshold: "0.5", wantValue: "0.01"},
+		{name: "less than", comparison: schedulerV1.LessThan, threshold: "0.05", wantBreached: true, wantValue: "0.01"},
+		{name: "invalid threshold", comparison: schedulerV1.GreaterThan, threshold: "high", wantMessage: true},
+		{name: "invalid comparison", comparison: "=~", threshold: "1", wantMessage: true},
+	}
+
+	server := newFakePrometheus(t, http.StatusOK, vector)
+	settings, err := NewSettings(&configV1.ChaosSchedulerConfig{PrometheusURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &ChaosScheduleReconciler{Settings: settings}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := &chaosTypes.SchedulerInfo{Instance: &schedulerV1.ChaosSchedule{}}
+			cs.Instance.Spec.SLOGuard = &schedulerV1.SLOGuard{
+				Query:      "error_rate",
+				Comparison: tt.comparison,
+				Threshold:  tt.threshold,
+			}
+
+			status := r.evaluateSLOGuard(context.TODO(), cs)
+			if cs.Instance.Status.SLOGuard != status || status.LastCheckTime == nil {
+				t.Fatalf("evaluateSLOGuard() did not record the status")
+			}
+			if (status.Message != "") != tt.wantMessage {
+				t.Fatalf("evaluateSLOGuard() message = %q, wantMessage %v", status.Message, tt.wantMessage)
+			}
+			if tt.wantMessage {
+				return
+			}
+			if status.Breached != tt.wantBreached || status.LastValue != tt.wantValue {
+				t.Fatalf("evaluateSLOGuard() = (%v, %s), want (%v, %s)", status.Breached, status.LastValue, tt.wantBreached, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestEvaluateSLOGuardWithoutPrometheus(t *testing.T) {
+	cs := &chaosTypes.SchedulerInfo{Instance: &schedulerV1.ChaosSchedule{}}
+	cs.Instance.Spec.SLOGuard = &schedulerV1.SLOGuard{Query: "up", Comparison: schedulerV1.LessThan, Threshold: "1"}
+
+	if status := (&ChaosScheduleReconciler{}).evaluateSLOGuard(context.TODO(), cs); status.Message == "" {
+		t.Fatalf("evaluateSLOGuard() should fail without a prometheus url")
+	}
+}
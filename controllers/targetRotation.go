@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	appsV1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// applyTargetRotation picks the target of the current run and overrides the appinfo of the engine with it
+func (r *ChaosScheduleReconciler) applyTargetRotation(ctx context.Context, cs *chaosTypes.SchedulerInfo, engine *operatorV1.ChaosEngine) error {
+
+	if cs.Instance.Spec.TargetRotation == nil {
+		return nil
+	}
+
+	target, candidates, err := r.resolveTarget(ctx, cs)
+	if err != nil {
+		return err
+	}
+
+	engine.Spec.Appinfo = operatorV1.ApplicationParams{
+		Appns:    target.Appns,
+		Applabel: target.Applabel,
+		AppKind:  target.AppKind,
+	}
+	if target.Name != "" {
+		engine.Spec.Selectors = &operatorV1.Selector{
+			Workloads: []operatorV1.Workload{
+				{
+					Kind:      operatorV1.WorkloadKind(target.AppKind),
+					Namespace: target.Appns,
+					Names:     target.Name,
+				},
+			},
+		}
+	}
+
+	status := cs.Instance.Status.TargetRotation
+	if status == nil {
+		status = &schedulerV1.TargetRotationStatus{}
+	}
+	status.LastTarget = &target
+	status.History = updateTargetHistory(status.History, candidates, target, r.now())
+	cs.Instance.Status.TargetRotation = status
+	return nil
+}
+
+// resolveTarget returns the target of the upcoming run along with all the candidates
+// The pick only depends on the schedule and the candidates, so that it can be looked up ahead of the run
+func (r *ChaosScheduleReconciler) resolveTarget(ctx context.Context, cs *chaosTypes.SchedulerInfo) (schedulerV1.RotationTarget, []schedulerV1.RotationTarget, error) {
+
+	candidates, err := r.getRotationCandidates(ctx, cs)
+	if err != nil {
+		return schedulerV1.RotationTarget{}, nil, err
+	}
+	if len(candidates) == 0 {
+		return schedulerV1.RotationTarget{}, nil, fmt.Errorf("no target found for the target rotation")
+	}
+
+	status := cs.Instance.Status.TargetRotation
+	if status == nil {
+		status = &schedulerV1.TargetRotationStatus{}
+	}
+	return pickTarget(cs, candidates, status), candidates, nil
+}
+
+// getRotationCandidates returns the candidates listed in the spec along with the ones discovered by the selector
+func (r *ChaosScheduleReconciler) getRotationCandidates(ctx context.Context, cs *chaosTypes.SchedulerInfo) ([]schedulerV1.RotationTarget, error) {
+
+	rotation := cs.Instance.Spec.TargetRotation
+	candidates := append([]schedulerV1.RotationTarget{}, rotation.Candidates...)
+	if rotation.Selector == nil {
+		return candidates, nil
+	}
+
+	selector, err := labels.Parse(rotation.Selector.Applabel)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the target selector label %q, err: %v", rotation.Selector.Applabel, err)
+	}
+
+	namespaces := rotation.Selector.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{cs.Instance.Namespace}
+	}
+
+	var discovered []schedulerV1.RotationTarget
+	for _, ns := range namespaces {
+		names, err := r.listWorkloadNames(ctx, rotation.Selector.AppKind, ns, selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			discovered = append(discovered, schedulerV1.RotationTarget{
+				Appns:    ns,
+				Applabel: rotation.Selector.Applabel,
+				AppKind:  strings.ToLower(rotation.Selector.AppKind),
+				Name:     name,
+			})
+		}
+	}
+
+	// the workloads are sorted to keep the order of the round-robin stable across runs
+	sort.Slice(discovered, func(i, j int) bool {
+		if discovered[i].Appns != discovered[j].Appns {
+			return discovered[i].Appns < discovered[j].Appns
+		}
+		return discovered[i].Name < discovered[j].Name
+	})
+	return append(candidates, discovered...), nil
+}
+
+// listWorkloadNames lists the names of the workloads of the given kind matching the selector
+func (r *ChaosScheduleReconciler) listWorkloadNames(ctx context.Context, kind, namespace string, selector labels.Selector) ([]string, error) {
+
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabelsSelector{Selector: selector},
+	}
+
+	var names []string
+	switch strings.ToLower(kind) {
+	case "deployment":
+		var list appsV1.DeploymentList
+		if err := r.Client.List(ctx, &list, opts...); err != nil {
+			return nil, err
+		}
+		for _, d := range list.Items {
+			names = append(names, d.Name)
+		}
+	case "statefulset":
+		var list appsV1.StatefulSetList
+		if err := r.Client.List(ctx, &list, opts...); err != nil {
+			return nil, err
+		}
+		for _, s := range list.Items {
+			names = append(names, s.Name)
+		}
+	case "daemonset":
+		var list appsV1.DaemonSetList
+		if err := r.Client.List(ctx, &list, opts...); err != nil {
+			return nil, err
+		}
+		for _, d := range list.Items {
+			names = append(names, d.Name)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported target selector kind %q, should be one of ('deployment', 'statefulset', 'daemonset')", kind)
+	}
+	return names, nil
+}
+
+// pickTarget picks the target of the current run as per the rotation strategy
+func pickTarget(cs *chaosTypes.SchedulerInfo, candidates []schedulerV1.RotationTarget, status *schedulerV1.TargetRotationStatus) schedulerV1.RotationTarget {
+
+	runInstances := cs.Instance.Status.Schedule.RunInstances
+	switch cs.Instance.Spec.TargetRotation.Strategy {
+	case schedulerV1.RandomRotation:
+		// the random source is seeded per schedule and run, so the same target is picked until the run is started
+		hash := fnv.New64a()
+		hash.Write([]byte(cs.Instance.UID))
+		source := rand.New(rand.NewSource(int64(hash.Sum64()) + int64(runInstances)))
+		return candidates[source.Intn(len(candidates))]
+	case schedulerV1.LeastRecentlyTargetedRotation:
+		var picked *schedulerV1.RotationTarget
+		var pickedTime *metav1.Time
+		for i := range candidates {
+			lastTime := lastTargetedTime(status.History, candidates[i])
+			// the candidates which were never targeted are preferred over the others
+			if lastTime == nil {
+				return candidates[i]
+			}
+			if picked == nil || lastTime.Before(pickedTime) {
+				picked, pickedTime = &candidates[i], lastTime
+			}
+		}
+		return *picked
+	default:
+		return candidates[runInstances%len(candidates)]
+	}
+}
+
+// lastTargetedTime returns the last time the target has been picked, if any
+func lastTargetedTime(history []schedulerV1.TargetRecord, target schedulerV1.RotationTarget) *metav1.Time {
+	for i := range history {
+		if history[i].Target == target {
+			return &history[i].LastTargetedTime
+		}
+	}
+	return nil
+}
+
+// updateTargetHistory records the picked target and drops the records of the targets which are no longer candidates
+func updateTargetHistory(history []schedulerV1.TargetRecord, candidates []schedulerV1.RotationTarget, target schedulerV1.RotationTarget, now time.Time) []schedulerV1.TargetRecord {
+
+	newHistory := []schedulerV1.TargetRecord{}
+	for _, candidate := range candidates {
+		if candidate == target {
+			newHistory = append(newHistory, schedulerV1.TargetRecord{Target: target, LastTargetedTime: metav1.Time{Time: now}})
+			continue
+		}
+		if lastTime := lastTargetedTime(history, candidate); lastTime != nil {
+			newHistory = append(newHistory, schedulerV1.TargetRecord{Target: candidate, LastTargetedTime: *lastTime})
+		}
+	}
+	return newHistory
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// newRotationSchedule returns a schedule rotating over the given candidates, after the given number of runs
+func newRotationSchedule(uid string, strategy schedulerV1.RotationStrategy, runInstances int, candidates []schedulerV1.RotationTarget) *chaosTypes.SchedulerInfo {
+	schedule := newTestSchedule("schedule", everyMinute())
+	schedule.UID = types.UID(uid)
+	schedule.Spec.TargetRotation = &schedulerV1.TargetRotation{Strategy: strategy, Candidates: candidates}
+	schedule.Status.Schedule.RunInstances = runInstances
+	return &chaosTypes.SchedulerInfo{Instance: schedule}
+}
+
+func TestPickTarget(t *testing.T) {
+	candidates := []schedulerV1.RotationTarget{
+		{Appns: "team-a", Applabel: "app=nginx", AppKind: "deployment"},
+		{Appns: "team-b", Applabel: "app=nginx", AppKind: "deployment"},
+		{Appns: "team-c", Applabel: "app=nginx", AppKind: "deployment"},
+	}
+	targeted := func(minute int, targets ...int) []schedulerV1.TargetRecord {
+		var history []schedulerV1.TargetRecord
+		for i, target := range targets {
+			history = append(history, schedulerV1.TargetRecord{Target: candidates[target], LastTargetedTime: metav1.Time{Time: at(10, minute+i)}})
+		}
+		return history
+	}
+
+	tests := []struct {
+		name         string
+		strategy     schedulerV1.RotationStrategy
+		runInstances int
+		history      []schedulerV1.TargetRecord
+		want         int
+	}{
+		{name: "round-robin first run", strategy: schedulerV1.RoundRobinRotation, runInstances: 0, want: 0},
+		{name: "round-robin next run", strategy: schedulerV1.RoundRobinRotation, runInstances: 2, want: 2},
+		{name: "round-robin wraps around", strategy: schedulerV1.RoundRobinRotation, runInstances: 3, want: 0},
+		{name: "round-robin wraps around again", strategy: schedulerV1.RoundRobinRotation, runInstances: 7, want: 1},
+		{name: "default strategy is round-robin", runInstances: 4, want: 1},
+		{
+			name:     "least-recently-targeted prefers the targets never picked",
+			strategy: schedulerV1.LeastRecentlyTargetedRotation,
+			history:  targeted(0, 0, 2),
+			want:     1,
+		},
+		{
+			name:     "least-recently-targeted picks the oldest target",
+			strategy: schedulerV1.LeastRecentlyTargetedRotation,
+			history:  targeted(0, 2, 0, 1),
+			want:     2,
+		},
+		{
+			name:     "least-recently-targeted ignores the order of the history",
+			strategy: schedulerV1.LeastRecentlyTargetedRotation,
+			history:  append(targeted(5, 0, 2), targeted(0, 1)...),
+			want:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := newRotationSchedule("schedule-uid", tt.strategy, tt.runInstances, candidates)
+			got := pickTarget(cs, candidates, &schedulerV1.TargetRotationStatus{History: tt.history})
+			if got != candidates[tt.want] {
+				t.Fatalf("pickTarget() = %+v, want %+v", got, candidates[tt.want])
+			}
+		})
+	}
+}
+
+// TestPickRandomTarget checks that the random pick is stable for a given run, so that the target checked by the
+// preconditions is the one of the engine, and that it changes across the runs and the schedules
+func TestPickRandomTarget(t *testing.T) {
+	var candidates []schedulerV1.RotationTarget
+	for _, ns := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		candidates = append(candidates, schedulerV1.RotationTarget{Appns: ns, Applabel: "app=nginx", AppKind: "deployment"})
+	}
+	status := &schedulerV1.TargetRotationStatus{}
+	pick := func(uid string, runInstances int) schedulerV1.RotationTarget {
+		return pickTarget(newRotationSchedule(uid, schedulerV1.RandomRotation, runInstances, candidates), candidates, status)
+	}
+
+	picked := map[schedulerV1.RotationTarget]bool{}
+	differs := false
+	for run := 0; run < 20; run++ {
+		target := pick("schedule-uid", run)
+		for i := 0; i < 5; i++ {
+			if again := pick("schedule-uid", run); again != target {
+				t.Fatalf("run %d picked %+v then %+v, want the same target", run, target, again)
+			}
+		}
+		picked[target] = true
+		if pick("other-uid", run) != target {
+			differs = true
+		}
+	}
+	if len(picked) < 2 {
+		t.Fatalf("the same target has been picked for all the runs: %v", picked)
+	}
+	if !differs {
+		t.Fatal("two schedules picked the same targets for all the runs")
+	}
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+const (
+	// tracerName is the instrumentation name of the spans of the reconciler
+	tracerName = "github.com/litmuschaos/chaos-scheduler/controllers"
+	// traceAnnotationPrefix prefixes the trace context of the creation of an engine in its annotations,
+	// e.g. litmuschaos.io/traceparent
+	traceAnnotationPrefix = "litmuschaos.io/"
+)
+
+// traceAnnotations carries the trace context in the annotations of an engine
+type traceAnnotations map[string]string
+
+func (a traceAnnotations) Get(key string) string {
+	return a[traceAnnotationPrefix+key]
+}
+
+func (a traceAnnotations) Set(key, value string) {
+	a[traceAnnotationPrefix+key] = value
+}
+
+func (a traceAnnotations) Keys() []string {
+	var keys []string
+	for key := range a {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// tracer returns the tracer of the reconciler, the spans are discarded when there is no TracerProvider
+func (r *ChaosScheduleReconciler) tracer() trace.Tracer {
+	if r.TracerProvider == nil {
+		return trace.NewNoopTracerProvider().Tracer(tracerName)
+	}
+	return r.TracerProvider.Tracer(tracerName)
+}
+
+// getContext returns the context holding the span of the reconcile
+func (schedulerReconcile *reconcileScheduler) getContext() context.Context {
+	// the schedulers built outside of a reconcile, e.g. by the simulator, have no span
+	if schedulerReconcile.ctx == nil {
+		return context.Background()
+	}
+	return schedulerReconcile.ctx
+}
+
+// startSpan starts a span of the schedule within the span of the reconcile
+func (schedulerReconcile *reconcileScheduler) startSpan(cs *chaosTypes.SchedulerInfo, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return schedulerReconcile.r.tracer().Start(schedulerReconcile.getContext(), name, trace.WithAttributes(append(getScheduleAttributes(cs), attributes...)...))
+}
+
+// endSpan ends the span, with the error as its status if any
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// createEngine creates the engine within the engine create span, whose trace context is kept in the annotations of
+// the engine so that the span of the run can link to it once the engine is finished
+func (schedulerReconcile *reconcileScheduler) createEngine(cs *chaosTypes.SchedulerInfo, engine *operatorV1.ChaosEngine, runID string) error {
+
+	ctx, span := schedulerReconcile.startSpan(cs, "engine create",
+		attribute.String("chaosengine.name", engine.Name),
+		attribute.String("run.id", runID),
+	)
+	if span.SpanContext().IsValid() {
+		// the annotations of the engine are copied from the schedule, they should not be modified in place
+		annotations := traceAnnotations{}
+		for key, value := range engine.Annotations {
+			annotations[key] = value
+		}
+		propagation.TraceContext{}.Inject(ctx, annotations)
+		engine.Annotations = annotations
+	}
+	err := schedulerReconcile.r.Client.Create(ctx, engine)
+	endSpan(span, err)
+	return err
+}
+
+// updateSchedule updates the schedule within the status update span
+func (schedulerReconcile *reconcileScheduler) updateSchedule(cs *chaosTypes.SchedulerInfo) error {
+	ctx, span := schedulerReconcile.startSpan(cs, "status update",
+		attribute.String("chaosschedule.status", string(cs.Instance.Status.Schedule.Status)),
+		attribute.Int("chaosschedule.active", len(cs.Instance.Status.Active)),
+	)
+	err := schedulerReconcile.r.Client.Update(ctx, cs.Instance)
+	endSpan(span, err)
+	return err
+}
+
+// traceEngineRun records the schedule run span of the finished engine, from its creation to now
+// The span is only recorded once the engine is finished, as the spans are exported once they end and the run may
+// outlive the scheduler. It is the root of its own trace, linked to the reconcile which created the engine and to the
+// one which saw it finished
+func (r *ChaosScheduleReconciler) traceEngineRun(ctx context.Context, cs *chaosTypes.SchedulerInfo, engine *operatorV1.ChaosEngine, runID, verdict string) {
+
+	// the trace context of the creation is extracted into an empty context, which does not hold the span of the completion
+	var links []trace.Link
+	if created := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), traceAnnotations(engine.Annotations))); created.IsValid() {
+		links = append(links, trace.Link{SpanContext: created, Attributes: []attribute.KeyValue{attribute.String("reconcile", "creation")}})
+	}
+	if completed := trace.SpanContextFromContext(ctx); completed.IsValid() {
+		links = append(links, trace.Link{SpanContext: completed, Attributes: []attribute.KeyValue{attribute.String("reconcile", "completion")}})
+	}
+
+	attributes := append(getScheduleAttributes(cs),
+		attribute.String("chaosengine.name", engine.Name),
+		attribute.String("run.id", runID),
+		attribute.String("run.verdict", verdict),
+	)
+	_, span := r.tracer().Start(ctx, "schedule run",
+		trace.WithNewRoot(),
+		trace.WithTimestamp(engine.CreationTimestamp.Time),
+		trace.WithLinks(links...),
+		trace.WithAttributes(attributes...),
+	)
+	span.End(trace.WithTimestamp(r.now()))
+}
+
+// getScheduleAttributes returns the attributes identifying the schedule in its spans
+func getScheduleAttributes(cs *chaosTypes.SchedulerInfo) []attribute.KeyValue {
+	if cs == nil || cs.Instance == nil {
+		return nil
+	}
+	return []attribute.KeyValue{
+		attribute.String("chaosschedule.namespace", cs.Instance.Namespace),
+		attribute.String("chaosschedule.name", cs.Instance.Name),
+		attribute.String("chaosschedule.uid", string(cs.Instance.UID)),
+	}
+}
+
+// getScheduledTimeAttribute returns the scheduled time of the cron evaluation as an attribute, the zero time means
+// that the recurrence has no more runs
+func getScheduledTimeAttribute(scheduledTime time.Time) attribute.KeyValue {
+	if scheduledTime.IsZero() {
+		return attribute.String("run.scheduledTime", "")
+	}
+	return attribute.String("run.scheduledTime", scheduledTime.UTC().Format(time.RFC3339))
+}
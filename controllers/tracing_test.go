@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// getSpan returns the recorded span with the given name
+func getSpan(t *testing.T, exporter *tracetest.InMemoryExporter, name string) tracetest.SpanStub {
+	for _, span := range exporter.GetSpans() {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("no %s span in %+v", name, exporter.GetSpans())
+	return tracetest.SpanStub{}
+}
+
+func TestTraceEngineRun(t *testing.T) {
+	schedule := newHookSchedule(nil)
+	schedule.Annotations = map[string]string{"team": "sre"}
+	r := newFakeReconciler(t, schedule)
+	exporter := tracetest.NewInMemoryExporter()
+	r.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	fakeClock := clocktesting.NewFakeClock(at(10, 0))
+	r.Clock = fakeClock
+
+	cs := &chaosTypes.SchedulerInfo{Instance: schedule.DeepCopy()}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, cs.Instance); err != nil {
+		t.Fatal(err)
+	}
+
+	// the reconcile which creates the engine
+	ctx, creation := r.tracer().Start(context.TODO(), "Reconcile")
+	s := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log, ctx: ctx}
+	engine, err := r.getEngineFromTemplate(context.TODO(), cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Name = "engine-a"
+	engine.CreationTimestamp = metav1.Time{Time: at(10, 0)}
+	if err := s.createEngine(cs, engine, getRunID(at(10, 0))); err != nil {
+		t.Fatal(err)
+	}
+	creation.End()
+
+	create := getSpan(t, exporter, "engine create")
+	if create.Parent.SpanID() != creation.SpanContext().SpanID() {
+		t.Fatalf("engine create span is not a child of the reconcile")
+	}
+	if cs.Instance.Annotations["litmuschaos.io/traceparent"] != "" {
+		t.Fatalf("trace context is set on the schedule: %v", cs.Instance.Annotations)
+	}
+
+	stored := &operatorV1.ChaosEngine{}
+	if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "engine-a", Namespace: "default"}, stored); err != nil {
+		t.Fatal(err)
+	}
+	want := "00-" + create.SpanContext.TraceID().String() + "-" + create.SpanContext.SpanID().String() + "-01"
+	if got := stored.Annotations["litmuschaos.io/traceparent"]; got != want || stored.Annotations["team"] != "sre" {
+		t.Fatalf("annotations = %v, want traceparent %s", stored.Annotations, want)
+	}
+
+	// the reconcile which sees the engine finished, after the restart of the scheduler
+	stored.Status.EngineStatus = operatorV1.EngineStatusCompleted
+	stored.Status.Experiments = []operatorV1.ExperimentStatuses{{Verdict: "Pass"}}
+	if err := r.Client.Update(context.TODO(), stored); err != nil {
+		t.Fatal(err)
+	}
+	ref := corev1.ObjectReference{Name: stored.Name, Namespace: stored.Namespace, UID: stored.UID}
+	cs.Instance.Status.Active = []corev1.ObjectReference{ref}
+	cs.Instance.Status.ActiveRuns = []schedulerV1.RunStatus{{RunID: getRunID(at(10, 0)), Engines: []corev1.ObjectReference{ref}}}
+	fakeClock.SetTime(at(10, 20))
+
+	exporter.Reset()
+	ctx, completion := r.tracer().Start(context.TODO(), "Reconcile")
+	if err := r.updateActiveStatus(ctx, cs); err != nil {
+		t.Fatal(err)
+	}
+	completion.End()
+
+	run := getSpan(t, exporter, "schedule run")
+	update := getSpan(t, exporter, "updateActiveStatus")
+	if run.Parent.IsValid() || run.SpanContext.TraceID() == completion.SpanContext().TraceID() {
+		t.Fatalf("schedule run span is not the root of its trace")
+	}
+	if !run.StartTime.Equal(stored.CreationTimestamp.Time) || !run.EndTime.Equal(at(10, 20)) {
+		t.Fatalf("schedule run span lasts from %v to %v, want from the creation of the engine to now", run.StartTime, run.EndTime)
+	}
+	wantLinks := []trace.SpanContext{create.SpanContext, update.SpanContext}
+	if len(run.Links) != len(wantLinks) {
+		t.Fatalf("schedule run span has %d links, want the creation and the completion reconciles", len(run.Links))
+	}
+	for i, link := range run.Links {
+		if link.SpanContext.TraceID() != wantLinks[i].TraceID() || link.SpanContext.SpanID() != wantLinks[i].SpanID() {
+			t.Errorf("link %d = %v, want %v", i, link.SpanContext, wantLinks[i])
+		}
+	}
+	attributes := map[string]string{}
+	for _, kv := range run.Attributes {
+		attributes[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attributes["run.verdict"] != "Pass" || attributes["run.id"] != getRunID(at(10, 0)) || attributes["chaosengine.name"] != "engine-a" {
+		t.Fatalf("attributes = %v", attributes)
+	}
+}
+
+func TestCreateEngineWithoutTracing(t *testing.T) {
+	schedule := newHookSchedule(nil)
+	r := newFakeReconciler(t, schedule)
+	s := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}
+	cs := &chaosTypes.SchedulerInfo{Instance: schedule.DeepCopy()}
+
+	engine, err := r.getEngineFromTemplate(context.TODO(), cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine.Name = "engine-a"
+	if err := s.createEngine(cs, engine, getRunID(at(10, 0))); err != nil {
+		t.Fatal(err)
+	}
+	if _, found := engine.Annotations["litmuschaos.io/traceparent"]; found {
+		t.Fatalf("trace context is set without any tracer provider: %v", engine.Annotations)
+	}
+}
+
+// untracedWritesClient records the writes which are not made within the span of a reconcile
+type untracedWritesClient struct {
+	client.Client
+	untraced []string
+}
+
+func (c *untracedWritesClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		c.untraced = append(c.untraced, obj.GetName())
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func TestTraceScheduleTermination(t *testing.T) {
+	for _, state := range []schedulerV1.ScheduleState{schedulerV1.StateHalted, schedulerV1.StateStopped, schedulerV1.StateCompleted} {
+		t.Run(string(state), func(t *testing.T) {
+			schedule := newTestSchedule("schedule", everyMinute())
+			schedule.UID = "schedule-uid"
+			schedule.Spec.ScheduleState = state
+			schedule.Status.Schedule.Status = schedulerV1.StatusRunning
+			run := newTestRun("100", "e1")
+			schedule.Status.ActiveRuns = []schedulerV1.RunStatus{run}
+			schedule.Status.Active = run.Engines
+
+			r := newFakeReconciler(t, schedule, newTestEngine("e1", ""))
+			r.TracerProvider = sdktrace.NewTracerProvider()
+			r.Clock = clocktesting.NewFakeClock(at(10, 0))
+			writes := &untracedWritesClient{Client: r.Client}
+			r.Client = writes
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "schedule", Namespace: "default"}}
+
+			if _, err := r.Reconcile(context.TODO(), request); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if len(writes.untraced) != 0 {
+				t.Fatalf("%v updated outside of the span of the reconcile", writes.untraced)
+			}
+		})
+	}
+}
@@ -5,19 +5,29 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"go.opentelemetry.io/otel/trace"
+
 	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/audit"
 	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
-	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
 )
 
-func (r *ChaosScheduleReconciler) updateActiveStatus(cs *chaosTypes.SchedulerInfo) error {
+// defaultHistoryLimit is the number of finished runs retained in the status when the historyLimit is not set
+const defaultHistoryLimit = 10
+
+// updateActiveStatus removes the finished engines from the active list, and completes the runs left without any
+// active engine
+func (r *ChaosScheduleReconciler) updateActiveStatus(ctx context.Context, cs *chaosTypes.SchedulerInfo) (err error) {
+
+	ctx, span := r.tracer().Start(ctx, "updateActiveStatus", trace.WithAttributes(getScheduleAttributes(cs)...))
+	defer func() { endSpan(span, err) }()
+
 	optsList := []client.ListOption{
 		client.InNamespace(cs.Instance.Namespace),
 		client.MatchingLabels{
@@ -26,16 +36,19 @@ func (r *ChaosScheduleReconciler) updateActiveStatus(cs *chaosTypes.SchedulerInf
 	}
 
 	var engineList operatorV1.ChaosEngineList
-	if errList := r.Client.List(context.TODO(), &engineList, optsList...); errList != nil {
+	if errList := r.Client.List(ctx, &engineList, optsList...); errList != nil {
 		return errList
 	}
 
 	childrenJobs := make(map[types.UID]bool)
+	verdicts := make(map[string]string)
 	for _, j := range engineList.Items {
 		childrenJobs[j.ObjectMeta.UID] = true
 		found := inActiveList(*cs, j.ObjectMeta.UID)
 
 		if found && IsEngineFinished(&j) {
+			r.traceEngineRun(ctx, cs, &j, getRunOfEngine(cs, j.ObjectMeta.UID), getEngineVerdict(&j))
+			mergeRunVerdict(verdicts, getRunOfEngine(cs, j.ObjectMeta.UID), getEngineVerdict(&j))
 			deleteFromActiveList(cs, j.ObjectMeta.UID)
 			r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "SawCompletedEngine", "Saw completed engine: %s, status: %v", j.Name, operatorV1.EngineStatusCompleted)
 		}
@@ -47,14 +60,134 @@ func (r *ChaosScheduleReconciler) updateActiveStatus(cs *chaosTypes.SchedulerInf
 	for _, j := range cs.Instance.Status.Active {
 		if found := childrenJobs[j.UID]; !found {
 			r.Recorder.Eventf(cs.Instance, corev1.EventTypeNormal, "MissingEngine", "Active engine went missing: %v", j.Name)
+			mergeRunVerdict(verdicts, getRunOfEngine(cs, j.UID), "Missing")
 			deleteFromActiveList(cs, j.UID)
-			cs.Instance.Status.LastScheduleCompletionTime = &metav1.Time{Time: time.Now()}
+			cs.Instance.Status.LastScheduleCompletionTime = &metav1.Time{Time: r.now()}
+		}
+	}
+
+	// the runs without any active engine are finished, except the multi-step run
+	// whose next steps are yet to be started, once their postRun hook is done
+	for _, run := range append([]schedulerV1.RunStatus{}, cs.Instance.Status.ActiveRuns...) {
+		if isRunActive(cs, run) || (isWorkflowRunning(cs) && cs.Instance.Status.Workflow.RunID == run.RunID) {
+			continue
+		}
+		verdict, found := verdicts[run.RunID]
+		if !found {
+			// the verdict is kept in the active run while its postRun hook is running
+			verdict = run.Verdict
+		}
+		if err := r.completeRun(ctx, cs, run.RunID, verdict); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addToActiveList adds the engine to the active list, grouped by the run it belongs to
+// The run is started at the given time if it is not active yet
+func addToActiveList(cs *chaosTypes.SchedulerInfo, runID string, ref corev1.ObjectReference, startTime time.Time) {
+	if !inActiveList(*cs, ref.UID) {
+		cs.Instance.Status.Active = append(cs.Instance.Status.Active, ref)
+	}
+
+	for i := range cs.Instance.Status.ActiveRuns {
+		run := &cs.Instance.Status.ActiveRuns[i]
+		if run.RunID != runID {
+			continue
+		}
+		for _, e := range run.Engines {
+			if e.UID == ref.UID {
+				return
+			}
+		}
+		run.Engines = append(run.Engines, ref)
+		return
+	}
+	cs.Instance.Status.ActiveRuns = append(cs.Instance.Status.ActiveRuns, schedulerV1.RunStatus{
+		RunID:     runID,
+		Engines:   []corev1.ObjectReference{ref},
+		StartTime: &metav1.Time{Time: startTime},
+	})
+}
+
+// getRunOfEngine returns the id of the active run the engine belongs to
+func getRunOfEngine(cs *chaosTypes.SchedulerInfo, uid types.UID) string {
+	for _, run := range cs.Instance.Status.ActiveRuns {
+		for _, e := range run.Engines {
+			if e.UID == uid {
+				return run.RunID
+			}
+		}
+	}
+	return ""
+}
+
+// mergeRunVerdict merges the verdict of an engine into the verdict of its run
+func mergeRunVerdict(verdicts map[string]string, runID, verdict string) {
+	if runID == "" {
+		return
+	}
+	if current, ok := verdicts[runID]; !ok || current == "Pass" {
+		verdicts[runID] = verdict
+	}
+}
+
+// finishRun moves the run from the active runs to the history of the schedule and emits its run.completed event
+func (r *ChaosScheduleReconciler) finishRun(ctx context.Context, cs *chaosTypes.SchedulerInfo, runID, verdict string) error {
+	newActiveRuns := []schedulerV1.RunStatus{}
+	for _, run := range cs.Instance.Status.ActiveRuns {
+		if run.RunID != runID {
+			newActiveRuns = append(newActiveRuns, run)
+			continue
+		}
+		run.EndTime = &metav1.Time{Time: r.now()}
+		run.Verdict = verdict
+		if err := r.emitRunCompleted(ctx, cs, run); err != nil {
+			return err
 		}
+		r.auditRunCompleted(cs, run)
+		cs.Instance.Status.History = append(cs.Instance.Status.History, run)
 	}
+	cs.Instance.Status.ActiveRuns = newActiveRuns
 
+	limit := int(r.Settings.HistoryLimit())
+	if cs.Instance.Spec.HistoryLimit != nil {
+		limit = int(*cs.Instance.Spec.HistoryLimit)
+	}
+	if len(cs.Instance.Status.History) > limit {
+		cs.Instance.Status.History = cs.Instance.Status.History[len(cs.Instance.Status.History)-limit:]
+	}
+	return nil
+}
+
+// finishActiveRuns moves the runs left active to the history with the given verdict, once the schedule no longer
+// follows its engines, so that the runs in flight are still recorded and emit their run.completed event
+func (r *ChaosScheduleReconciler) finishActiveRuns(ctx context.Context, cs *chaosTypes.SchedulerInfo, verdict string) error {
+	if isWorkflowRunning(cs) {
+		cs.Instance.Status.Workflow.Phase = schedulerV1.WorkflowAborted
+		cs.Instance.Status.Workflow.NextStepTime = nil
+	}
+	for _, run := range append([]schedulerV1.RunStatus{}, cs.Instance.Status.ActiveRuns...) {
+		if err := r.finishRun(ctx, cs, run.RunID, verdict); err != nil {
+			return err
+		}
+	}
+	cs.Instance.Status.Active = nil
 	return nil
 }
 
+// isRunActive checks whether any of the engines of the run is still active
+func isRunActive(cs *chaosTypes.SchedulerInfo, run schedulerV1.RunStatus) bool {
+	for _, e := range run.Engines {
+		if inActiveList(*cs, e.UID) {
+			return true
+		}
+	}
+	return false
+}
+
 func deleteFromActiveList(cs *chaosTypes.SchedulerInfo, uid types.UID) {
 	if cs == nil {
 		return
@@ -83,27 +216,21 @@ func IsEngineFinished(j *operatorV1.ChaosEngine) bool {
 }
 
 // UpdateSchedulerStatus updates the scheduler status for the complete
+// A conflicting update is requeued by Reconcile, which reads the schedule again
 func (schedulerReconcile *reconcileScheduler) UpdateSchedulerStatus(cs *chaosTypes.SchedulerInfo, request reconcile.Request) error {
 	cs.Instance.Status.Schedule.Status = schedulerV1.StatusCompleted
-	cs.Instance.Status.Schedule.EndTime = &metav1.Time{Time: time.Now()}
+	cs.Instance.Status.Schedule.EndTime = &metav1.Time{Time: schedulerReconcile.r.now()}
 	cs.Instance.Spec.ScheduleState = schedulerV1.StateCompleted
-	cs.Instance.Status.Active = nil
-	if err := schedulerReconcile.r.Client.Update(context.TODO(), cs.Instance); k8serrors.IsConflict(err) {
-		return retry.
-			Times(uint(5)).
-			Wait(1 * time.Second).
-			Try(func(attempt uint) error {
-				scheduler, err := schedulerReconcile.r.getChaosSchedulerInstance(request)
-				if err != nil {
-					return err
-				}
-				scheduler.Instance.Status.Schedule.Status = schedulerV1.StatusCompleted
-				scheduler.Instance.Spec.ScheduleState = schedulerV1.StateCompleted
-				scheduler.Instance.Status.Schedule.EndTime = &metav1.Time{Time: time.Now()}
-				scheduler.Instance.Status.Active = nil
-				return schedulerReconcile.r.Client.Update(context.TODO(), scheduler.Instance)
-			})
-	}
-	time.Sleep(1 * time.Second)
+	// the engines still running once the schedule is completed, e.g. when its endTime is reached, are no longer followed
+	if err := schedulerReconcile.r.finishActiveRuns(schedulerReconcile.getContext(), cs, "Aborted"); err != nil {
+		return err
+	}
+	if err := schedulerReconcile.r.emitCompleted(schedulerReconcile.getContext(), cs); err != nil {
+		return err
+	}
+	if err := schedulerReconcile.updateSchedule(cs); err != nil {
+		return err
+	}
+	schedulerReconcile.r.audit(cs, audit.Record{Decision: audit.Complete, Policy: "schedule/" + getScheduleType(cs)})
 	return nil
 }
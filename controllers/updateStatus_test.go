@@ -0,0 +1,313 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	appsV1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	configV1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	chaosTypes "github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// newFakeReconciler returns a reconciler backed by a fake client holding the given objects
+func newFakeReconciler(t *testing.T, objects ...client.Object) *ChaosScheduleReconciler {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(schedulerV1.AddToScheme(scheme))
+	utilruntime.Must(operatorV1.AddToScheme(scheme))
+	utilruntime.Must(batchv1.AddToScheme(scheme))
+	utilruntime.Must(coordinationv1.AddToScheme(scheme))
+	utilruntime.Must(appsV1.AddToScheme(scheme))
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(policyV1.AddToScheme(scheme))
+
+	settings, err := NewSettings(&configV1.ChaosSchedulerConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ChaosScheduleReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+		Settings: settings,
+	}
+}
+
+// newTestEngine returns an engine of the schedule with the given uid, finished with the given verdict if any
+func newTestEngine(uid, verdict string) *operatorV1.ChaosEngine {
+	engine := &operatorV1.ChaosEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "engine-" + uid,
+			Namespace: "default",
+			UID:       types.UID(uid),
+			Labels:    map[string]string{"app": "chaos-engine", "chaosUID": "schedule-uid"},
+		},
+		Status: operatorV1.ChaosEngineStatus{EngineStatus: operatorV1.EngineStatusInitialized},
+	}
+	if verdict != "" {
+		engine.Status.EngineStatus = operatorV1.EngineStatusCompleted
+		engine.Status.Experiments = []operatorV1.ExperimentStatuses{{Verdict: verdict}}
+	}
+	return engine
+}
+
+// newTestRun returns an active run with references to the engines of the given uids
+func newTestRun(runID string, uids ...string) schedulerV1.RunStatus {
+	run := schedulerV1.RunStatus{RunID: runID}
+	for _, uid := range uids {
+		run.Engines = append(run.Engines, corev1.ObjectReference{Name: "engine-" + uid, Namespace: "default", UID: types.UID(uid)})
+	}
+	return run
+}
+
+func TestUpdateActiveStatus(t *testing.T) {
+	var historyLimit int32 = 1
+
+	tests := []struct {
+		name           string
+		engines        []client.Object
+		activeRuns     []schedulerV1.RunStatus
+		historyLimit   *int32
+		wantActive     []string
+		wantActiveRuns []string
+		wantHistory    map[string]string
+		wantCompletion bool
+	}{
+		{
+			name:           "running engine stays active",
+			engines:        []client.Object{newTestEngine("e1", "")},
+			activeRuns:     []schedulerV1.RunStatus{newTestRun("100", "e1")},
+			wantActive:     []string{"e1"},
+			wantActiveRuns: []string{"100"},
+			wantHistory:    map[string]string{},
+		},
+		{
+			name:        "completed engine finishes its run",
+			engines:     []client.Object{newTestEngine("e1", "Pass")},
+			activeRuns:  []schedulerV1.RunStatus{newTestRun("100", "e1")},
+			wantHistory: map[string]string{"100": "Pass"},
+		},
+		{
+			name:           "run with a running engine is not finished",
+			engines:        []client.Object{newTestEngine("e1", "Fail"), newTestEngine("e2", "")},
+			activeRuns:     []schedulerV1.RunStatus{newTestRun("100", "e1", "e2")},
+			wantActive:     []string{"e2"},
+			wantActiveRuns: []string{"100"},
+			wantHistory:    map[string]string{},
+		},
+		{
+			name:        "failed engine fails its run",
+			engines:     []client.Object{newTestEngine("e1", "Pass"), newTestEngine("e2", "Fail")},
+			activeRuns:  []schedulerV1.RunStatus{newTestRun("100", "e1", "e2")},
+			wantHistory: map[string]string{"100": "Fail"},
+		},
+		{
+			name:           "missing engine is removed",
+			activeRuns:     []schedulerV1.RunStatus{newTestRun("100", "e1")},
+			wantHistory:    map[string]string{"100": "Missing"},
+			wantCompletion: true,
+		},
+		{
+			name:        "engine which is not active is ignored",
+			engines:     []client.Object{newTestEngine("e1", "Pass")},
+			wantHistory: map[string]string{},
+		},
+		{
+			name:         "history is trimmed to the limit",
+			engines:      []client.Object{newTestEngine("e1", "Pass"), newTestEngine("e2", "Fail")},
+			activeRuns:   []schedulerV1.RunStatus{newTestRun("100", "e1"), newTestRun("200", "e2")},
+			historyLimit: &historyLimit,
+			wantHistory:  map[string]string{"200": "Fail"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeReconciler(t, tt.engines...)
+			cs := &chaosTypes.SchedulerInfo{Instance: &schedulerV1.ChaosSchedule{
+				ObjectMeta: metav1.ObjectMeta{Name: "schedule", Namespace: "default", UID: "schedule-uid"},
+				Spec:       schedulerV1.ChaosScheduleSpec{HistoryLimit: tt.historyLimit},
+			}}
+			for _, run := range tt.activeRuns {
+				cs.Instance.Status.ActiveRuns = append(cs.Instance.Status.ActiveRuns, run)
+				cs.Instance.Status.Active = append(cs.Instance.Status.Active, run.Engines...)
+			}
+
+			if err := r.updateActiveStatus(context.TODO(), cs); err != nil {
+				t.Fatalf("updateActiveStatus() error = %v", err)
+			}
+
+			var active []string
+			for _, ref := range cs.Instance.Status.Active {
+				active = append(active, string(ref.UID))
+			}
+			if !equalStrings(active, tt.wantActive) {
+				t.Fatalf("active engines = %v, want %v", active, tt.wantActive)
+			}
+			var activeRuns []string
+			for _, run := range cs.Instance.Status.ActiveRuns {
+				activeRuns = append(activeRuns, run.RunID)
+			}
+			if !equalStrings(activeRuns, tt.wantActiveRuns) {
+				t.Fatalf("active runs = %v, want %v", activeRuns, tt.wantActiveRuns)
+			}
+			history := map[string]string{}
+			for _, run := range cs.Instance.Status.History {
+				if run.EndTime == nil {
+					t.Fatalf("run %s has no end time", run.RunID)
+				}
+				history[run.RunID] = run.Verdict
+			}
+			if len(history) != len(tt.wantHistory) {
+				t.Fatalf("history = %v, want %v", history, tt.wantHistory)
+			}
+			for runID, verdict := range tt.wantHistory {
+				if history[runID] != verdict {
+					t.Fatalf("history = %v, want %v", history, tt.wantHistory)
+				}
+			}
+			if (cs.Instance.Status.LastScheduleCompletionTime != nil) != tt.wantCompletion {
+				t.Fatalf("lastScheduleCompletionTime = %v, wantCompletion %v", cs.Instance.Status.LastScheduleCompletionTime, tt.wantCompletion)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestFinishActiveRunsOnTermination checks that the runs in flight are recorded in the history once the schedule is
+// completed, stopped or halted
+func TestFinishActiveRunsOnTermination(t *testing.T) {
+	tests := []struct {
+		name        string
+		terminate   func(s *reconcileScheduler, cs *chaosTypes.SchedulerInfo) error
+		wantVerdict string
+		wantStopped bool
+	}{
+		{
+			name: "completed",
+			terminate: func(s *reconcileScheduler, cs *chaosTypes.SchedulerInfo) error {
+				return s.UpdateSchedulerStatus(cs, reconcile.Request{})
+			},
+			wantVerdict: "Aborted",
+		},
+		{
+			name: "stopped",
+			terminate: func(s *reconcileScheduler, cs *chaosTypes.SchedulerInfo) error {
+				_, err := s.reconcileForStop(cs, reconcile.Request{})
+				return err
+			},
+			wantVerdict: "Stopped",
+			wantStopped: true,
+		},
+		{
+			name: "halted",
+			terminate: func(s *reconcileScheduler, cs *chaosTypes.SchedulerInfo) error {
+				_, err := s.reconcileForHalt(cs, reconcile.Request{})
+				return err
+			},
+			wantVerdict: "Aborted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := newTestSchedule("schedule", everyMinute())
+			schedule.UID = "schedule-uid"
+			schedule.Status.History = []schedulerV1.RunStatus{{RunID: "100", Verdict: "Pass"}}
+			for _, run := range []schedulerV1.RunStatus{newTestRun("200", "e1"), newTestRun("300", "e2")} {
+				schedule.Status.ActiveRuns = append(schedule.Status.ActiveRuns, run)
+				schedule.Status.Active = append(schedule.Status.Active, run.Engines...)
+			}
+			r := newFakeReconciler(t, schedule, newTestEngine("e1", ""), newTestEngine("e2", ""))
+			s := &reconcileScheduler{r: r, reqLogger: chaosTypes.Log}
+			cs := &chaosTypes.SchedulerInfo{Instance: &schedulerV1.ChaosSchedule{}}
+			if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, cs.Instance); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.terminate(s, cs); err != nil {
+				t.Fatalf("terminate() error = %v", err)
+			}
+
+			updated := &schedulerV1.ChaosSchedule{}
+			if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "schedule", Namespace: "default"}, updated); err != nil {
+				t.Fatal(err)
+			}
+			if len(updated.Status.Active) != 0 || len(updated.Status.ActiveRuns) != 0 {
+				t.Fatalf("active = %v and activeRuns = %v, want none", updated.Status.Active, updated.Status.ActiveRuns)
+			}
+			var history []string
+			for _, run := range updated.Status.History {
+				history = append(history, run.RunID+"/"+run.Verdict)
+			}
+			want := []string{"100/Pass", "200/" + tt.wantVerdict, "300/" + tt.wantVerdict}
+			if !equalStrings(history, want) {
+				t.Fatalf("history = %v, want %v", history, want)
+			}
+
+			engine := &operatorV1.ChaosEngine{}
+			if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "engine-e1", Namespace: "default"}, engine); err != nil {
+				t.Fatal(err)
+			}
+			if stopped := engine.Spec.EngineState == operatorV1.EngineStateStop; stopped != tt.wantStopped {
+				t.Fatalf("engine stopped = %v, want %v", stopped, tt.wantStopped)
+			}
+		})
+	}
+}
+
+func TestMergeRunVerdict(t *testing.T) {
+	tests := []struct {
+		name     string
+		verdicts []string
+		want     string
+	}{
+		{name: "single pass", verdicts: []string{"Pass"}, want: "Pass"},
+		{name: "all pass", verdicts: []string{"Pass", "Pass"}, want: "Pass"},
+		{name: "fail after pass", verdicts: []string{"Pass", "Fail"}, want: "Fail"},
+		{name: "pass after fail", verdicts: []string{"Fail", "Pass"}, want: "Fail"},
+		{name: "first non pass result wins", verdicts: []string{"Pass", "Stopped", "Fail"}, want: "Stopped"},
+		{name: "missing engine", verdicts: []string{"Missing", "Pass"}, want: "Missing"},
+		{name: "awaited engine", verdicts: []string{"Pass", "Awaited"}, want: "Awaited"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdicts := map[string]string{"other": "Pass"}
+			for _, verdict := range tt.verdicts {
+				mergeRunVerdict(verdicts, "100", verdict)
+			}
+			// the engines which do not belong to any run are ignored
+			mergeRunVerdict(verdicts, "", "Fail")
+
+			if verdicts["100"] != tt.want || verdicts["other"] != "Pass" || len(verdicts) != 2 {
+				t.Fatalf("verdicts = %v, want %s for the run", verdicts, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// schedule-watcher prints the transitions of the ChaosSchedules, e.g. halted schedules or started runs. It is an
+// example of the generated informers and listers, which watch the schedules instead of polling the API
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/client/clientset/versioned"
+	"github.com/litmuschaos/chaos-scheduler/pkg/client/informers/externalversions"
+	listers "github.com/litmuschaos/chaos-scheduler/pkg/client/listers/litmuschaos/v1alpha1"
+)
+
+func main() {
+	var namespace string
+	var resync time.Duration
+	flag.StringVar(&namespace, "namespace", "", "The namespace of the chaosschedules to watch, all of them if empty")
+	flag.DurationVar(&resync, "resync", 10*time.Minute, "The interval at which the informer lists the chaosschedules again")
+	flag.Parse()
+
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	client, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	factory := externalversions.NewSharedInformerFactoryWithOptions(client, resync, externalversions.WithNamespace(namespace))
+	w := newScheduleWatcher(factory, os.Stdout)
+	factory.Start(ctx.Done())
+	if err := w.waitForCacheSync(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := w.printSummary(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	<-ctx.Done()
+}
+
+// scheduleWatcher prints the transitions of the schedules received by the informer
+type scheduleWatcher struct {
+	informer cache.SharedIndexInformer
+	lister   listers.ChaosScheduleLister
+
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// newScheduleWatcher registers the event handlers of the watcher on the informer of the factory, the factory should be
+// started afterwards
+func newScheduleWatcher(factory externalversions.SharedInformerFactory, out io.Writer) *scheduleWatcher {
+	schedules := factory.Litmuschaos().V1alpha1().ChaosSchedules()
+	w := &scheduleWatcher{informer: schedules.Informer(), lister: schedules.Lister(), out: out}
+	w.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if schedule, ok := obj.(*schedulerV1.ChaosSchedule); ok {
+				w.printf(schedule, "added, state %s", describeState(schedule))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			old, ok := oldObj.(*schedulerV1.ChaosSchedule)
+			if !ok {
+				return
+			}
+			schedule, ok := newObj.(*schedulerV1.ChaosSchedule)
+			if !ok {
+				return
+			}
+			for _, transition := range transitions(old, schedule) {
+				w.printf(schedule, "%s", transition)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if schedule, ok := obj.(*schedulerV1.ChaosSchedule); ok {
+				w.printf(schedule, "deleted")
+			}
+		},
+	})
+	return w
+}
+
+// waitForCacheSync waits until the informer has listed the schedules
+func (w *scheduleWatcher) waitForCacheSync(ctx context.Context) error {
+	if !cache.WaitForCacheSync(ctx.Done(), w.informer.HasSynced) {
+		return fmt.Errorf("unable to sync the chaosschedules: %v", ctx.Err())
+	}
+	return nil
+}
+
+// printSummary prints the number of schedules per state, as read from the cache of the informer through the lister
+func (w *scheduleWatcher) printSummary() error {
+	schedules, err := w.lister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	count := map[string]int{}
+	for _, schedule := range schedules {
+		count[describeState(schedule)]++
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = fmt.Fprintf(w.out, "watching %d chaosschedules, %d running, %d halted, %d stopped, %d completed\n",
+		len(schedules), count[string(schedulerV1.StatusRunning)], count[string(schedulerV1.StatusHalted)],
+		count[string(schedulerV1.StatusStopped)], count[string(schedulerV1.StatusCompleted)])
+	return err
+}
+
+func (w *scheduleWatcher) printf(schedule *schedulerV1.ChaosSchedule, format string, args ...interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s/%s: %s\n", schedule.Namespace, schedule.Name, fmt.Sprintf(format, args...))
+}
+
+// describeState returns the status of the schedule, or "pending" until the scheduler has reconciled it
+func describeState(schedule *schedulerV1.ChaosSchedule) string {
+	if schedule.Status.Schedule.Status == "" {
+		return "pending"
+	}
+	return string(schedule.Status.Schedule.Status)
+}
+
+// transitions describes the changes between two versions of a schedule which are relevant to its users
+func transitions(old, schedule *schedulerV1.ChaosSchedule) []string {
+	var changes []string
+	if old.Spec.ScheduleState != schedule.Spec.ScheduleState && schedule.Spec.ScheduleState != "" {
+		changes = append(changes, fmt.Sprintf("scheduleState set to %s", schedule.Spec.ScheduleState))
+	}
+	if describeState(old) != describeState(schedule) {
+		changes = append(changes, fmt.Sprintf("state %s -> %s", describeState(old), describeState(schedule)))
+	}
+	if run := schedule.Status.Schedule.RunInstances; run > old.Status.Schedule.RunInstances {
+		changes = append(changes, fmt.Sprintf("run %d started", run))
+	}
+	completion := schedule.Status.LastScheduleCompletionTime
+	if completion != nil && !completion.Equal(old.Status.LastScheduleCompletionTime) {
+		changes = append(changes, fmt.Sprintf("run completed at %s", completion.UTC().Format(time.RFC3339)))
+	}
+	return changes
+}
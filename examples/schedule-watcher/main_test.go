@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clienttesting "k8s.io/client-go/testing"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/client/clientset/versioned/fake"
+	"github.com/litmuschaos/chaos-scheduler/pkg/client/informers/externalversions"
+)
+
+// safeBuffer is a buffer which can be read by the test while the informer writes to it
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newSchedule(namespace, name string, status schedulerV1.ChaosStatus) *schedulerV1.ChaosSchedule {
+	return &schedulerV1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       schedulerV1.ChaosScheduleSpec{ScheduleState: schedulerV1.StateActive},
+		Status:     schedulerV1.ChaosScheduleStatus{Schedule: schedulerV1.ScheduleStatus{Status: status}},
+	}
+}
+
+// startWatcher starts a watcher on the fake clientset and waits until the informer watches the schedules, since the
+// fake clientset drops the events sent before the watch is established
+func startWatcher(ctx context.Context, t *testing.T, client *fake.Clientset, namespace string, out *safeBuffer) *scheduleWatcher {
+	watching := make(chan struct{})
+	client.PrependWatchReactor("chaosschedules", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		w, err := client.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return false, nil, err
+		}
+		close(watching)
+		return true, w, nil
+	})
+
+	factory := externalversions.NewSharedInformerFactoryWithOptions(client, 0, externalversions.WithNamespace(namespace))
+	w := newScheduleWatcher(factory, out)
+	factory.Start(ctx.Done())
+	if err := w.waitForCacheSync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-watching:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("the informer did not watch the chaosschedules")
+	}
+	return w
+}
+
+// waitForOutput waits until the watcher has printed all the lines
+func waitForOutput(t *testing.T, out *safeBuffer, lines ...string) {
+	err := wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		for _, line := range lines {
+			if !strings.Contains(out.String(), line+"\n") {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("the watcher did not print %q, got:\n%s", lines, out.String())
+	}
+}
+
+func TestScheduleWatcher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := fake.NewSimpleClientset(
+		newSchedule("default", "nginx", schedulerV1.StatusRunning),
+		newSchedule("default", "redis", schedulerV1.StatusCompleted),
+	)
+	out := &safeBuffer{}
+	w := startWatcher(ctx, t, client, "", out)
+	waitForOutput(t, out, "default/nginx: added, state running", "default/redis: added, state completed")
+
+	if err := w.printSummary(); err != nil {
+		t.Fatal(err)
+	}
+	waitForOutput(t, out, "watching 2 chaosschedules, 1 running, 0 halted, 0 stopped, 1 completed")
+
+	schedules := client.LitmuschaosV1alpha1().ChaosSchedules("default")
+	nginx, err := schedules.Get(ctx, "nginx", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	completion := metav1.NewTime(time.Date(2021, time.October, 6, 10, 5, 0, 0, time.UTC))
+	nginx.Status.Schedule.RunInstances = 1
+	nginx.Status.LastScheduleCompletionTime = &completion
+	if nginx, err = schedules.Update(ctx, nginx, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForOutput(t, out, "default/nginx: run 1 started", "default/nginx: run completed at 2021-10-06T10:05:00Z")
+
+	nginx.Spec.ScheduleState = schedulerV1.StateHalted
+	nginx.Status.Schedule.Status = schedulerV1.StatusHalted
+	if nginx, err = schedules.Update(ctx, nginx, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForOutput(t, out, "default/nginx: scheduleState set to halt", "default/nginx: state running -> halted")
+
+	if _, err := schedules.Create(ctx, &schedulerV1.ChaosSchedule{ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "default"}}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := schedules.Delete(ctx, "redis", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForOutput(t, out, "default/kafka: added, state pending", "default/redis: deleted")
+
+	// no transition is printed for the changes which are not relevant to the users
+	before := out.String()
+	nginx.Labels = map[string]string{"team": "sre"}
+	if _, err = schedules.Update(ctx, nginx, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	err = wait.PollImmediate(10*time.Millisecond, wait.ForeverTestTimeout, func() (bool, error) {
+		cached, err := w.lister.ChaosSchedules("default").Get("nginx")
+		return err == nil && cached.Labels["team"] == "sre", err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != before {
+		t.Fatalf("unexpected transition printed:\n%s", strings.TrimPrefix(out.String(), before))
+	}
+}
+
+func TestScheduleWatcherNamespace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := fake.NewSimpleClientset(
+		newSchedule("default", "nginx", schedulerV1.StatusRunning),
+		newSchedule("litmus", "nginx", schedulerV1.StatusHalted),
+	)
+	out := &safeBuffer{}
+	w := startWatcher(ctx, t, client, "litmus", out)
+
+	cached, err := w.lister.ChaosSchedules("litmus").Get("nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.Status.Schedule.Status != schedulerV1.StatusHalted {
+		t.Fatalf("cached status = %s, want %s", cached.Status.Schedule.Status, schedulerV1.StatusHalted)
+	}
+	_, err = w.lister.ChaosSchedules("default").Get("nginx")
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("the schedules of the other namespaces should not be cached, got %v", err)
+	}
+	if qualified := err.(apierrors.APIStatus).Status().Details.Group; qualified != schedulerV1.GroupVersion.Group {
+		t.Fatalf("the not found error is qualified with %q", qualified)
+	}
+
+	if _, err := client.LitmuschaosV1alpha1().ChaosSchedules("default").Create(ctx, newSchedule("default", "kafka", ""), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.LitmuschaosV1alpha1().ChaosSchedules("litmus").Create(ctx, newSchedule("litmus", "kafka", ""), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitForOutput(t, out, "litmus/kafka: added, state pending")
+	if strings.Contains(out.String(), "default/") {
+		t.Fatalf("the watcher printed the schedules of the other namespaces:\n%s", out.String())
+	}
+}
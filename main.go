@@ -17,26 +17,43 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	"os"
 	"runtime"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	uberzap "go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	schemeruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	operatorScheme "github.com/litmuschaos/chaos-operator/pkg/client/clientset/versioned/scheme"
+	configv1alpha1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
 	litmuschaosiov1alpha1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	litmuschaosiov1beta1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1beta1"
 	"github.com/litmuschaos/chaos-scheduler/controllers"
+	"github.com/litmuschaos/chaos-scheduler/pkg/audit"
+	"github.com/litmuschaos/chaos-scheduler/pkg/config"
+	"github.com/litmuschaos/chaos-scheduler/pkg/notify"
+	"github.com/litmuschaos/chaos-scheduler/pkg/tracing"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -48,68 +65,289 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(litmuschaosiov1alpha1.AddToScheme(scheme))
+	utilruntime.Must(litmuschaosiov1beta1.AddToScheme(scheme))
 	utilruntime.Must(operatorScheme.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 func main() {
+	var configFile string
 	var metricsAddr string
 	var enableLeaderElection bool
+	var leaseDuration time.Duration
+	var renewDeadline time.Duration
+	var retryPeriod time.Duration
 	var probeAddr string
+	var prometheusURL string
+	var killSwitchName string
+	var killSwitchNamespace string
+	var mutexLeaseNamespace string
+	var calendarAddr string
+	var enableConversionWebhook bool
+	var cloudEventsSinkURL string
+	var auditLogPath string
+	var otlpEndpoint string
+	flag.StringVar(&configFile, "config", "",
+		"The controller will load its configuration from this file. "+
+			"The flags which are set take precedence over the file.")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
+	flag.BoolVar(&enableLeaderElection, "leader-elect", true,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-	opts := zap.Options{
-		Development: true,
-	}
+	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second, "The duration the other replicas wait for before taking over the lease of a leader which stopped renewing it.")
+	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second, "The duration the leader retries to renew its lease for before stepping down.")
+	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second, "The interval at which the replicas try to acquire or renew the lease.")
+	flag.StringVar(&prometheusURL, "prometheus-url", "", "The address of the Prometheus server used by the sloGuard of the schedules which do not define one.")
+	flag.StringVar(&killSwitchName, "kill-switch-configmap", "chaos-kill-switch", "The name of the ConfigMap which pauses all the schedules while its globalPause is set.")
+	flag.StringVar(&killSwitchNamespace, "kill-switch-namespace", "", "The namespace of the kill switch ConfigMap, defaults to the watch namespace or the namespace of the scheduler.")
+	flag.StringVar(&mutexLeaseNamespace, "mutex-lease-namespace", "", "The namespace of the Leases of the cluster mutex groups, defaults to the namespace of the scheduler.")
+	flag.StringVar(&calendarAddr, "calendar-bind-address", "127.0.0.1:8082", "The address the calendar endpoint binds to, \"0\" disables it.")
+	flag.BoolVar(&enableConversionWebhook, "enable-conversion-webhook", false,
+		"Serve the conversion of the ChaosSchedules between the v1alpha1 and v1beta1 versions. "+
+			"The serving certificate is read from the certDir of the webhook.")
+	flag.StringVar(&auditLogPath, "audit-log-path", "", "The json lines file the decisions of the scheduler are appended to, \"-\" writes them to the standard output.")
+	flag.StringVar(&cloudEventsSinkURL, "cloudevents-sink-url", "", "The address the CloudEvents of the runs and of the schedules are posted to, empty disables them.")
+	flag.StringVar(&otlpEndpoint, "otlp-endpoint", "", "The address of the OTLP/HTTP receiver the spans of the reconciles and of the runs are exported to, empty disables them.")
+	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	schedulerConfig := config.Default()
+	var err error
+	if configFile != "" {
+		if schedulerConfig, err = config.Load(configFile); err != nil {
+			ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+			setupLog.Error(err, "unable to load the config file")
+			os.Exit(1)
+		}
+	}
+
+	// the flags which are set explicitly override the config file, including the reloaded one
+	applyFlags := func(c *configv1alpha1.ChaosSchedulerConfig) {
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "metrics-bind-address":
+				c.Metrics.BindAddress = metricsAddr
+			case "health-probe-bind-address":
+				c.Health.HealthProbeBindAddress = probeAddr
+			case "leader-elect":
+				c.LeaderElection.LeaderElect = &enableLeaderElection
+			case "leader-elect-lease-duration":
+				c.LeaderElection.LeaseDuration = metav1.Duration{Duration: leaseDuration}
+			case "leader-elect-renew-deadline":
+				c.LeaderElection.RenewDeadline = metav1.Duration{Duration: renewDeadline}
+			case "leader-elect-retry-period":
+				c.LeaderElection.RetryPeriod = metav1.Duration{Duration: retryPeriod}
+			case "prometheus-url":
+				c.PrometheusURL = prometheusURL
+			case "kill-switch-configmap":
+				c.KillSwitch.Name = killSwitchName
+			case "kill-switch-namespace":
+				c.KillSwitch.Namespace = killSwitchNamespace
+			case "mutex-lease-namespace":
+				c.MutexLeaseNamespace = mutexLeaseNamespace
+			case "calendar-bind-address":
+				c.Calendar.BindAddress = calendarAddr
+			case "enable-conversion-webhook":
+				c.ConversionWebhook = enableConversionWebhook
+			case "cloudevents-sink-url":
+				c.CloudEvents.SinkURL = cloudEventsSinkURL
+			case "audit-log-path":
+				c.AuditLogPath = auditLogPath
+			case "otlp-endpoint":
+				c.Tracing.OTLPEndpoint = otlpEndpoint
+			case "zap-devel":
+				c.Logging.Development = opts.Development
+			}
+		})
+	}
+	applyFlags(schedulerConfig)
+	if err := config.Validate(schedulerConfig); err != nil {
+		ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+		setupLog.Error(err, "invalid flags")
+		os.Exit(1)
+	}
+
+	logLevel := setupLogger(&opts, configFile != "", schedulerConfig.Logging)
 
 	printVersion()
 
-	namespace, err := k8sutil.GetWatchNamespace()
-	if err != nil {
-		setupLog.Error(err, "Failed to get watch namespace")
-		os.Exit(1)
+	namespaces := schedulerConfig.WatchNamespaces
+	if len(namespaces) == 0 {
+		namespace, err := k8sutil.GetWatchNamespace()
+		if err != nil {
+			setupLog.Error(err, "Failed to get watch namespace")
+			os.Exit(1)
+		}
+		if namespace != "" {
+			namespaces = strings.Split(namespace, ",")
+		}
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		MetricsBindAddress:     metricsAddr,
-		Port:                   9443,
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "770f4bfd.",
-		Namespace:              namespace,
+	// the reloaded files are compared with the loaded one, before the namespaces are defaulted below
+	loadedConfig := schedulerConfig.DeepCopy()
+	if schedulerConfig.KillSwitch.Namespace == "" {
+		schedulerConfig.KillSwitch.Namespace = getKillSwitchNamespace(namespaces)
+	}
+	if schedulerConfig.CloudEvents.OutboxNamespace == "" {
+		schedulerConfig.CloudEvents.OutboxNamespace = schedulerConfig.KillSwitch.Namespace
+	}
+	// the Leases of the cluster mutex groups are shared by all the watch namespaces, they are kept next to the scheduler
+	if schedulerConfig.MutexLeaseNamespace == "" {
+		schedulerConfig.MutexLeaseNamespace = getSchedulerNamespace()
+	}
+
+	options, err := ctrl.Options{
+		Scheme: scheme,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
 		// speeds up voluntary leader transitions as the new leader don't have to wait
 		// LeaseDuration time first.
 		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
-	})
+		// Only the spans are flushed once the manager stops, no engine is created then,
+		// so the lease is released right away on a rolling update.
+		LeaderElectionReleaseOnCancel: true,
+	}.AndFrom(schedulerConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to apply the config")
+		os.Exit(1)
+	}
+	// the lease is kept next to the scheduler, which also lets it run out of the cluster
+	if options.LeaderElectionNamespace == "" {
+		options.LeaderElectionNamespace = getSchedulerNamespace()
+	}
+
+	// only the kill switch is watched among the ConfigMaps, in every watch namespace
+	selectors := cache.SelectorsByObject{
+		&corev1.ConfigMap{}: {Field: fields.OneTermEqualSelector("metadata.name", schedulerConfig.KillSwitch.Name)},
+	}
+	switch len(namespaces) {
+	case 0, 1:
+		if len(namespaces) == 1 {
+			options.Namespace = namespaces[0]
+		}
+		options.NewCache = cache.BuilderWithOptions(cache.Options{SelectorsByObject: selectors})
+	default:
+		// the multi-namespaced cache passes its options to the cache of each namespace
+		newCache := cache.MultiNamespacedCacheBuilder(namespaces)
+		options.NewCache = func(config *rest.Config, opts cache.Options) (cache.Cache, error) {
+			opts.SelectorsByObject = selectors
+			return newCache(config, opts)
+		}
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	settings, err := controllers.NewSettings(schedulerConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to apply the config")
+		os.Exit(1)
+	}
+
+	// the Secrets of the notification sinks are read directly, the cache would watch all of them
+	dispatcher := notify.NewDispatcher(mgr.GetAPIReader())
+	if err := mgr.Add(dispatcher); err != nil {
+		setupLog.Error(err, "unable to set up the notification dispatcher")
+		os.Exit(1)
+	}
+
+	// the events are stored in the outbox ConfigMap until the sink accepts them, the outbox is read directly
+	// as the cache only holds the kill switch among the ConfigMaps
+	var events controllers.EventEmitter
+	if schedulerConfig.CloudEvents.SinkURL != "" {
+		outbox := notify.NewOutbox(mgr.GetClient(), mgr.GetAPIReader(), types.NamespacedName{
+			Name:      schedulerConfig.CloudEvents.OutboxName,
+			Namespace: schedulerConfig.CloudEvents.OutboxNamespace,
+		}, schedulerConfig.CloudEvents.SinkURL)
+		if err := mgr.Add(outbox); err != nil {
+			setupLog.Error(err, "unable to set up the CloudEvents outbox")
+			os.Exit(1)
+		}
+		events = outbox
+	}
+
+	var auditLog *audit.Logger
+	if schedulerConfig.AuditLogPath != "" {
+		if auditLog, err = audit.Open(schedulerConfig.AuditLogPath); err != nil {
+			setupLog.Error(err, "unable to open the audit log")
+			os.Exit(1)
+		}
+	}
+
+	// the spans are discarded by the no-op provider when there is no endpoint
+	tracerProvider, shutdownTracing, err := tracing.NewTracerProvider(schedulerConfig.Tracing.OTLPEndpoint)
+	if err != nil {
+		setupLog.Error(err, "unable to set up the tracing")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.ChaosScheduleReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("chaos-scheduler"),
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  notify.NewRecorder(mgr.GetEventRecorderFor("chaos-scheduler"), dispatcher),
+		Settings:  settings,
+		KillSwitch: types.NamespacedName{
+			Name:      schedulerConfig.KillSwitch.Name,
+			Namespace: schedulerConfig.KillSwitch.Namespace,
+		},
+		MutexLeaseNamespace:     schedulerConfig.MutexLeaseNamespace,
+		MaxConcurrentReconciles: schedulerConfig.MaxConcurrentReconciles,
+		RateLimiter:             config.NewRateLimiter(schedulerConfig.RateLimiter),
+		Clock:                   clock.RealClock{},
+		Events:                  events,
+		Audit:                   auditLog,
+		TracerProvider:          tracerProvider,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ChaosSchedule")
 		os.Exit(1)
 	}
+
+	if schedulerConfig.ConversionWebhook {
+		if err = ctrl.NewWebhookManagedBy(mgr).For(&litmuschaosiov1beta1.ChaosSchedule{}).Complete(); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ChaosSchedule")
+			os.Exit(1)
+		}
+	}
+
+	if schedulerConfig.Calendar.BindAddress != "0" {
+		calendarServer := &controllers.CalendarServer{
+			BindAddress: schedulerConfig.Calendar.BindAddress,
+			Handler: controllers.NewCalendarHandler(mgr.GetCache(), settings, schedulerConfig.Calendar.Days,
+				schedulerConfig.Calendar.EventDuration.Duration, clock.RealClock{}),
+		}
+		if err := mgr.Add(calendarServer); err != nil {
+			setupLog.Error(err, "unable to set up the calendar server")
+			os.Exit(1)
+		}
+	}
+
+	if configFile != "" {
+		watcher := &config.Watcher{
+			Path:    configFile,
+			Current: loadedConfig,
+			OnReload: func(reloaded *configv1alpha1.ChaosSchedulerConfig) {
+				applyFlags(reloaded)
+				if err := settings.Update(reloaded); err != nil {
+					setupLog.Error(err, "unable to apply the reloaded config")
+				}
+				if logLevel != nil {
+					level, _ := config.ParseLevel(reloaded.Logging.Level)
+					logLevel.SetLevel(level)
+				}
+			},
+		}
+		if err := mgr.Add(watcher); err != nil {
+			setupLog.Error(err, "unable to set up the config watcher")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -126,9 +364,59 @@ func main() {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+	// the spans of the last reconciles are flushed to the endpoint before exiting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		setupLog.Error(err, "unable to flush the spans")
+	}
 }
 
 func printVersion() {
 	setupLog.Info(fmt.Sprintf("Go Version: %s", runtime.Version()))
 	setupLog.Info(fmt.Sprintf("Go OS/Arch: %s/%s", runtime.GOOS, runtime.GOARCH))
 }
+
+// setupLogger sets the logger as per the zap flags and the logging config
+// The level is returned when it follows the config file, so that it can be reloaded
+func setupLogger(opts *zap.Options, fromFile bool, logging configv1alpha1.LoggingConfig) *uberzap.AtomicLevel {
+
+	// the --zap-devel flag is applied to the logging config along with the other flags
+	opts.Development = logging.Development
+	zapOpts := []zap.Opts{zap.UseFlagOptions(opts)}
+	switch logging.Format {
+	case "json":
+		zapOpts = append(zapOpts, zap.JSONEncoder())
+	case "console":
+		zapOpts = append(zapOpts, zap.ConsoleEncoder())
+	}
+
+	// the level set through the zap flags is not overridden
+	var logLevel *uberzap.AtomicLevel
+	if fromFile && opts.Level == nil {
+		level, _ := config.ParseLevel(logging.Level)
+		atomicLevel := uberzap.NewAtomicLevelAt(level)
+		logLevel = &atomicLevel
+		zapOpts = append(zapOpts, zap.Level(atomicLevel))
+	}
+
+	ctrl.SetLogger(zap.New(zapOpts...))
+	return logLevel
+}
+
+// getKillSwitchNamespace returns the namespace of the kill switch when it is not set
+// The ConfigMap has to be in a watch namespace, if any, to be visible to the cache of the manager
+func getKillSwitchNamespace(watchNamespaces []string) string {
+	if len(watchNamespaces) != 0 {
+		return watchNamespaces[0]
+	}
+	return getSchedulerNamespace()
+}
+
+// getSchedulerNamespace returns the namespace the scheduler runs in, litmus when it runs out of the cluster
+func getSchedulerNamespace() string {
+	if operatorNamespace, err := k8sutil.GetOperatorNamespace(); err == nil {
+		return operatorNamespace
+	}
+	return "litmus"
+}
@@ -0,0 +1,117 @@
+// Package audit writes the decisions of the scheduler as json lines, so that it can be reconstructed why the chaos
+// did or did not happen. The fields of the records are only ever added, the version is bumped otherwise
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// SchemaVersion is the version of the schema of the records
+const SchemaVersion = 1
+
+var log = ctrl.Log.WithName("audit")
+
+// Decision is the decision taken by the scheduler
+type Decision string
+
+const (
+	// Create is the creation of the engines of a run
+	Create Decision = "create"
+	// Skip drops a run which did not pass its gates
+	Skip Decision = "skip"
+	// Defer postpones a run until its gates pass
+	Defer Decision = "defer"
+	// Abort finishes a run without creating its engines
+	Abort Decision = "abort"
+	// Complete finishes a run, or the schedule when the record has no run id
+	Complete Decision = "complete"
+	// Halt halts the schedule
+	Halt Decision = "halt"
+	// Stop stops the active engines, as per the stopped scheduleState, the sloGuard, the kill switch or the Replace
+	// concurrencyPolicy
+	Stop Decision = "stop"
+)
+
+// Record is a line of the audit log
+type Record struct {
+	Version  int       `json:"version"`
+	Time     time.Time `json:"time"`
+	Decision Decision  `json:"decision"`
+	Schedule Schedule  `json:"schedule"`
+	RunID    string    `json:"runID,omitempty"`
+	// Engines are the engines created, replaced or finished by the decision
+	Engines       []Engine   `json:"engines,omitempty"`
+	ScheduledTime *time.Time `json:"scheduledTime,omitempty"`
+	// Policy is the part of the spec which led to the decision, e.g. "mutexGroup/skip" or "concurrencyPolicy/Forbid"
+	Policy  string `json:"policy,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	Verdict string `json:"verdict,omitempty"`
+	// Inputs are the values considered by the decision
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// Schedule identifies the schedule the decision is about
+type Schedule struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	UID        types.UID `json:"uid"`
+	Generation int64     `json:"generation"`
+}
+
+// Engine identifies an engine of the run
+type Engine struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid,omitempty"`
+}
+
+// Logger appends the records to a writer, one json object per line
+type Logger struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+// New returns a logger writing the records to the writer
+func New(w io.Writer) *Logger {
+	return &Logger{encoder: json.NewEncoder(w)}
+}
+
+// Open returns a logger appending the records to the file at the path, "-" is the standard output
+func Open(path string) (*Logger, error) {
+	if path == "-" {
+		return New(os.Stdout), nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the audit log %s, err: %v", path, err)
+	}
+	return New(file), nil
+}
+
+// Log writes the record, a nil logger discards it
+// The records which cannot be written are reported in the logs of the scheduler, the decision is not held back
+func (l *Logger) Log(record Record) {
+	if l == nil {
+		return
+	}
+	record.Version = SchemaVersion
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.encoder.Encode(record); err != nil {
+		log.Error(err, "Unable to write the audit record", "Decision", record.Decision,
+			"Schedule", record.Schedule.Namespace+"/"+record.Schedule.Name)
+	}
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf)
+
+	scheduledTime := time.Date(2021, time.October, 6, 10, 0, 0, 0, time.UTC)
+	logger.Log(Record{
+		Decision:      Skip,
+		Schedule:      Schedule{Namespace: "litmus", Name: "schedule-nginx", UID: "uid", Generation: 2},
+		RunID:         "1633514400",
+		ScheduledTime: &scheduledTime,
+		Policy:        "mutexGroup/skip",
+		Reason:        "MutexGroupBusy",
+	})
+	logger.Log(Record{Decision: Complete, Schedule: Schedule{Namespace: "litmus", Name: "schedule-nginx"}})
+
+	var nilLogger *Logger
+	nilLogger.Log(Record{Decision: Create})
+
+	var records []map[string]interface{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		record := map[string]interface{}{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("wrote %d records, want 2", len(records))
+	}
+
+	first := records[0]
+	for field, want := range map[string]interface{}{
+		"version":       float64(SchemaVersion),
+		"decision":      "skip",
+		"runID":         "1633514400",
+		"scheduledTime": "2021-10-06T10:00:00Z",
+		"policy":        "mutexGroup/skip",
+		"reason":        "MutexGroupBusy",
+	} {
+		if first[field] != want {
+			t.Errorf("%s = %v, want %v", field, first[field], want)
+		}
+	}
+	if schedule := first["schedule"].(map[string]interface{}); schedule["name"] != "schedule-nginx" || schedule["generation"] != float64(2) {
+		t.Errorf("schedule = %v", schedule)
+	}
+	if _, found := records[1]["runID"]; found {
+		t.Errorf("completion of the schedule has a runID: %v", records[1])
+	}
+	if records[1]["time"] == "0001-01-01T00:00:00Z" {
+		t.Errorf("time is not set: %v", records[1])
+	}
+}
+
+func TestOpenAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for i := 0; i < 2; i++ {
+		logger, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		logger.Log(Record{Decision: Create})
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(content, []byte("\n")); lines != 2 {
+		t.Fatalf("audit log has %d lines, want 2:\n%s", lines, content)
+	}
+}
@@ -0,0 +1,112 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// icsTimeFormat is the UTC date-time format of RFC 5545
+const icsTimeFormat = "20060102T150405Z"
+
+// maxLineLength is the maximum length of a content line in octets, the longer lines are folded
+const maxLineLength = 75
+
+// Event is a run of a schedule
+type Event struct {
+	// UID identifies the event across the updates of the calendar
+	UID string
+	// Start is the scheduled time of the run
+	Start time.Time
+	// Duration is the expected duration of the run, no DURATION is written when it is zero
+	Duration time.Duration
+	// Summary is the title of the event
+	Summary string
+	// Description gives the details of the run
+	Description string
+}
+
+// Write writes the events as an iCalendar (RFC 5545) with the given name
+// The events are stamped with the given time, as per the DTSTAMP property
+func Write(w io.Writer, name string, stamp time.Time, events []Event) error {
+
+	bw := bufio.NewWriter(w)
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:-//litmuschaos//chaos-scheduler//EN")
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	writeLine(bw, "X-WR-CALNAME:"+escapeText(name))
+	for _, event := range events {
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+escapeText(event.UID))
+		writeLine(bw, "DTSTAMP:"+stamp.UTC().Format(icsTimeFormat))
+		writeLine(bw, "DTSTART:"+event.Start.UTC().Format(icsTimeFormat))
+		if event.Duration > 0 {
+			writeLine(bw, "DURATION:"+formatDuration(event.Duration))
+		}
+		writeLine(bw, "SUMMARY:"+escapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+escapeText(event.Description))
+		}
+		writeLine(bw, "END:VEVENT")
+	}
+	writeLine(bw, "END:VCALENDAR")
+	return bw.Flush()
+}
+
+// EventUID derives a stable uid of the run of a schedule from its scheduled time
+func EventUID(namespace, name string, start time.Time) string {
+	return fmt.Sprintf("%s-%s-%d@chaos-scheduler.litmuschaos.io", namespace, name, start.Unix())
+}
+
+// formatDuration formats the duration as a dur-value of RFC 5545, e.g. "P1DT2H30M", rounded down to the second
+func formatDuration(d time.Duration) string {
+
+	seconds := int64(d / time.Second)
+	value := "P"
+	if days := seconds / 86400; days != 0 {
+		value += fmt.Sprintf("%dD", days)
+	}
+	if seconds%86400 == 0 && seconds != 0 {
+		return value
+	}
+	value += "T"
+	if hours := seconds % 86400 / 3600; hours != 0 {
+		value += fmt.Sprintf("%dH", hours)
+	}
+	if minutes := seconds % 3600 / 60; minutes != 0 {
+		value += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds%60 != 0 || seconds == 0 {
+		value += fmt.Sprintf("%dS", seconds%60)
+	}
+	return value
+}
+
+// writeLine writes the content line terminated by CRLF, folding it once it exceeds the maximum length
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineLength
+	for len(line) > limit {
+		// the line is not folded within a multi-byte character
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// the folded lines start with a space, which counts towards their length
+		limit = maxLineLength - 1
+	}
+	w.WriteString(line + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// escapeText escapes the characters which have a meaning in the TEXT values
+func escapeText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,70 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	start := time.Date(2021, time.October, 6, 10, 0, 0, 0, time.UTC)
+	events := []Event{{
+		UID:         EventUID("litmus", "schedule-nginx", start),
+		Start:       start,
+		Duration:    90 * time.Second,
+		Summary:     "ChaosSchedule litmus/schedule-nginx",
+		Description: "experiments: pod-delete, pod-cpu-hog; " + strings.Repeat("a", 100),
+	}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "litmus", start, events); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:litmus-schedule-nginx-1633514400@chaos-scheduler.litmuschaos.io\r\n",
+		"DTSTART:20211006T100000Z\r\nDURATION:PT1M30S\r\n",
+		`DESCRIPTION:experiments: pod-delete\, pod-cpu-hog\; aaa`,
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("calendar does not contain %q:\n%s", want, out)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > maxLineLength {
+			t.Fatalf("line of %d octets is not folded: %q", len(line), line)
+		}
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	if !strings.Contains(unfolded, strings.Repeat("a", 100)+"\r\n") {
+		t.Fatalf("folded line is not restored by unfolding:\n%s", unfolded)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{duration: 0, want: "PT0S"},
+		{duration: 30 * time.Second, want: "PT30S"},
+		{duration: 15 * time.Minute, want: "PT15M"},
+		{duration: 2*time.Hour + 30*time.Minute, want: "PT2H30M"},
+		{duration: time.Hour + 1500*time.Millisecond, want: "PT1H1S"},
+		{duration: 48 * time.Hour, want: "P2D"},
+		{duration: 26*time.Hour + 5*time.Second, want: "P1DT2H5S"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatDuration(tt.duration); got != tt.want {
+				t.Fatalf("formatDuration(%v) = %q, want %q", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
@@ -36,9 +36,9 @@ type FakeChaosSchedules struct {
 	ns   string
 }
 
-var chaosschedulesResource = schema.GroupVersionResource{Group: "litmuschaos", Version: "v1alpha1", Resource: "chaosschedules"}
+var chaosschedulesResource = schema.GroupVersionResource{Group: "litmuschaos.io", Version: "v1alpha1", Resource: "chaosschedules"}
 
-var chaosschedulesKind = schema.GroupVersionKind{Group: "litmuschaos", Version: "v1alpha1", Kind: "ChaosSchedule"}
+var chaosschedulesKind = schema.GroupVersionKind{Group: "litmuschaos.io", Version: "v1alpha1", Kind: "ChaosSchedule"}
 
 // Get takes name of the chaosSchedule, and returns the corresponding chaosSchedule object, and an error if there is any.
 func (c *FakeChaosSchedules) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ChaosSchedule, err error) {
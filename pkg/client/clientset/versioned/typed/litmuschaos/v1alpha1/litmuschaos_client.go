@@ -29,7 +29,7 @@ type LitmuschaosV1alpha1Interface interface {
 	ChaosSchedulesGetter
 }
 
-// LitmuschaosV1alpha1Client is used to interact with features provided by the litmuschaos group.
+// LitmuschaosV1alpha1Client is used to interact with features provided by the litmuschaos.io group.
 type LitmuschaosV1alpha1Client struct {
 	restClient rest.Interface
 }
@@ -67,7 +67,7 @@ func New(c rest.Interface) *LitmuschaosV1alpha1Client {
 }
 
 func setConfigDefaults(config *rest.Config) error {
-	gv := v1alpha1.GroupVersion
+	gv := v1alpha1.SchemeGroupVersion
 	config.GroupVersion = &gv
 	config.APIPath = "/apis"
 	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/workqueue"
+	componentconfig "k8s.io/component-base/config/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	configV1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// defaultReloadInterval is the interval at which the configuration file is checked for changes
+const defaultReloadInterval = 10 * time.Second
+
+var (
+	log    = ctrl.Log.WithName("config")
+	scheme = runtime.NewScheme()
+)
+
+func init() {
+	utilruntime.Must(configV1.AddToScheme(scheme))
+}
+
+// Default returns the configuration used when no configuration file is given
+func Default() *configV1.ChaosSchedulerConfig {
+	config := &configV1.ChaosSchedulerConfig{}
+	setDefaults(config)
+	return config
+}
+
+// Load reads and validates the configuration file
+func Load(path string) (*configV1.ChaosSchedulerConfig, error) {
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the config file %s, err: %v", path, err)
+	}
+
+	config := &configV1.ChaosSchedulerConfig{}
+	codecs := serializer.NewCodecFactory(scheme)
+	if err := runtime.DecodeInto(codecs.UniversalDecoder(), content, config); err != nil {
+		return nil, fmt.Errorf("unable to decode the config file %s, err: %v", path, err)
+	}
+	setDefaults(config)
+	if err := Validate(config); err != nil {
+		return nil, fmt.Errorf("invalid config file %s, err: %v", path, err)
+	}
+	return config, nil
+}
+
+// setDefaults fills the settings of the manager which were previously set through the flags
+func setDefaults(config *configV1.ChaosSchedulerConfig) {
+
+	if config.Metrics.BindAddress == "" {
+		config.Metrics.BindAddress = ":8080"
+	}
+	if config.Health.HealthProbeBindAddress == "" {
+		config.Health.HealthProbeBindAddress = ":8081"
+	}
+	if config.Webhook.Port == nil {
+		port := 9443
+		config.Webhook.Port = &port
+	}
+	// the manager dereferences the leader election settings
+	if config.LeaderElection == nil {
+		config.LeaderElection = &componentconfig.LeaderElectionConfiguration{}
+	}
+	// a single replica creates the engines, the others take over once its lease expires
+	if config.LeaderElection.LeaderElect == nil {
+		leaderElect := true
+		config.LeaderElection.LeaderElect = &leaderElect
+	}
+	if config.LeaderElection.ResourceName == "" {
+		config.LeaderElection.ResourceName = "chaos-scheduler.litmuschaos.io"
+	}
+	if config.LeaderElection.LeaseDuration.Duration == 0 {
+		config.LeaderElection.LeaseDuration.Duration = 15 * time.Second
+	}
+	if config.LeaderElection.RenewDeadline.Duration == 0 {
+		config.LeaderElection.RenewDeadline.Duration = 10 * time.Second
+	}
+	if config.LeaderElection.RetryPeriod.Duration == 0 {
+		config.LeaderElection.RetryPeriod.Duration = 2 * time.Second
+	}
+	if config.MaxConcurrentReconciles == 0 {
+		config.MaxConcurrentReconciles = 1
+	}
+	if config.KillSwitch.Name == "" {
+		config.KillSwitch.Name = "chaos-kill-switch"
+	}
+	if config.Calendar.BindAddress == "" {
+		config.Calendar.BindAddress = "127.0.0.1:8082"
+	}
+	if config.Calendar.Days == 0 {
+		config.Calendar.Days = 30
+	}
+	if config.Calendar.EventDuration.Duration == 0 {
+		config.Calendar.EventDuration.Duration = 15 * time.Minute
+	}
+	if config.CloudEvents.OutboxName == "" {
+		config.CloudEvents.OutboxName = "chaos-scheduler-outbox"
+	}
+}
+
+// Validate checks the fields which would otherwise only fail once they are used
+func Validate(config *configV1.ChaosSchedulerConfig) error {
+
+	switch config.Defaults.ConcurrencyPolicy {
+	case "", schedulerV1.AllowConcurrent, schedulerV1.ForbidConcurrent, schedulerV1.ReplaceConcurrent:
+	default:
+		return fmt.Errorf("invalid default concurrencyPolicy %q, should be one of ('Allow', 'Forbid', 'Replace')", config.Defaults.ConcurrencyPolicy)
+	}
+	if limit := config.Defaults.HistoryLimit; limit != nil && *limit < 0 {
+		return fmt.Errorf("invalid default historyLimit %d, should not be negative", *limit)
+	}
+	if _, err := time.LoadLocation(config.Defaults.TimeZone); err != nil {
+		return fmt.Errorf("invalid default timeZone %q, err: %v", config.Defaults.TimeZone, err)
+	}
+	switch config.Logging.Format {
+	case "", "json", "console":
+	default:
+		return fmt.Errorf("invalid logging format %q, should be one of ('json', 'console')", config.Logging.Format)
+	}
+	if _, err := ParseLevel(config.Logging.Level); err != nil {
+		return err
+	}
+	if config.RateLimiter.QPS < 0 || config.RateLimiter.Burst < 0 {
+		return fmt.Errorf("invalid rateLimiter qps %d and burst %d, should be positive", config.RateLimiter.QPS, config.RateLimiter.Burst)
+	}
+	if base, max := config.RateLimiter.BaseDelay, config.RateLimiter.MaxDelay; (base != nil && base.Duration < 0) ||
+		(max != nil && max.Duration < 0) || (base != nil && max != nil && base.Duration > max.Duration) {
+		return fmt.Errorf("invalid rateLimiter baseDelay %v and maxDelay %v, should be positive with the baseDelay below the maxDelay", base, max)
+	}
+	if config.Calendar.Days < 0 {
+		return fmt.Errorf("invalid calendar days %d, should be positive", config.Calendar.Days)
+	}
+	if config.Calendar.EventDuration.Duration < 0 {
+		return fmt.Errorf("invalid calendar eventDuration %v, should be positive", config.Calendar.EventDuration.Duration)
+	}
+	if sinkURL := config.CloudEvents.SinkURL; sinkURL != "" {
+		if u, err := url.Parse(sinkURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid cloudEvents sinkURL %q, should be an absolute http or https url", sinkURL)
+		}
+	}
+	if endpoint := config.Tracing.OTLPEndpoint; endpoint != "" {
+		if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid tracing otlpEndpoint %q, should be an absolute http or https url", endpoint)
+		}
+	}
+	if election := config.LeaderElection; election.RetryPeriod.Duration <= 0 ||
+		election.RenewDeadline.Duration <= election.RetryPeriod.Duration || election.LeaseDuration.Duration <= election.RenewDeadline.Duration {
+		return fmt.Errorf("invalid leaderElection leaseDuration %v, renewDeadline %v and retryPeriod %v, should be positive and decreasing",
+			election.LeaseDuration.Duration, election.RenewDeadline.Duration, election.RetryPeriod.Duration)
+	}
+	if config.MaxConcurrentReconciles < 0 {
+		return fmt.Errorf("invalid maxConcurrentReconciles %d, should be positive", config.MaxConcurrentReconciles)
+	}
+	return nil
+}
+
+// NewRateLimiter builds the rate limiter of the controller, the defaults are the ones of controller-runtime
+func NewRateLimiter(config configV1.RateLimiterConfig) workqueue.RateLimiter {
+
+	baseDelay, maxDelay := 5*time.Millisecond, 1000*time.Second
+	if config.BaseDelay != nil {
+		baseDelay = config.BaseDelay.Duration
+	}
+	if config.MaxDelay != nil {
+		maxDelay = config.MaxDelay.Duration
+	}
+	qps, burst := 10, 100
+	if config.QPS != 0 {
+		qps = config.QPS
+	}
+	if config.Burst != 0 {
+		burst = config.Burst
+	}
+
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
+
+// ParseLevel parses the logging level, the verbosity levels are the negated zap levels
+func ParseLevel(level string) (zapcore.Level, error) {
+
+	if level == "" {
+		return zapcore.InfoLevel, nil
+	}
+	if verbosity, err := strconv.Atoi(level); err == nil {
+		if verbosity < 0 {
+			return 0, fmt.Errorf("invalid logging level %q, the verbosity should be positive", level)
+		}
+		return zapcore.Level(-verbosity), nil
+	}
+
+	var zapLevel zapcore.Level
+	if err := zapLevel.UnmarshalText([]byte(strings.ToLower(level))); err != nil {
+		return 0, fmt.Errorf("invalid logging level %q, should be one of ('debug', 'info', 'error') or a positive verbosity level", level)
+	}
+	return zapLevel, nil
+}
+
+// Watcher reloads the configuration file whenever it changes
+// Only the fields which are safe to be changed are handed over to OnReload, the others need a restart
+type Watcher struct {
+	// Path of the configuration file
+	Path string
+	// Current is the configuration which has been loaded at the start
+	Current *configV1.ChaosSchedulerConfig
+	// OnReload is called with the new configuration, before it is compared with the current one
+	OnReload func(*configV1.ChaosSchedulerConfig)
+	// Interval at which the file is checked, defaults to 10s
+	Interval time.Duration
+}
+
+// Start checks the file periodically until the context is cancelled
+// The file is compared by content, the ConfigMap volumes update it through a symlink swap
+func (w *Watcher) Start(ctx context.Context) error {
+
+	interval := w.Interval
+	if interval == 0 {
+		interval = defaultReloadInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last, _ := ioutil.ReadFile(w.Path)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		content, err := ioutil.ReadFile(w.Path)
+		if err != nil || string(content) == string(last) {
+			continue
+		}
+		last = content
+
+		config, err := Load(w.Path)
+		if err != nil {
+			log.Error(err, "Unable to reload the config file, keeping the current one")
+			continue
+		}
+		w.OnReload(config)
+		if restartRequired(w.Current, config) {
+			log.Info("Only the defaults, the logging level and the prometheusURL are reloaded, restart the scheduler to apply the other changes")
+		}
+		w.Current = config
+		log.Info("Config file reloaded", "Path", w.Path)
+	}
+}
+
+// NeedLeaderElection makes the watcher run on all the replicas, see manager.LeaderElectionRunnable
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// restartRequired checks whether any of the fields which are not reloaded has changed
+func restartRequired(current, config *configV1.ChaosSchedulerConfig) bool {
+	return !reflect.DeepEqual(current.ControllerManagerConfigurationSpec, config.ControllerManagerConfigurationSpec) ||
+		!reflect.DeepEqual(current.WatchNamespaces, config.WatchNamespaces) ||
+		current.MaxConcurrentReconciles != config.MaxConcurrentReconciles ||
+		!reflect.DeepEqual(current.RateLimiter, config.RateLimiter) ||
+		current.Logging.Format != config.Logging.Format ||
+		current.Logging.Development != config.Logging.Development ||
+		current.KillSwitch != config.KillSwitch ||
+		current.MutexLeaseNamespace != config.MutexLeaseNamespace ||
+		current.Calendar != config.Calendar ||
+		current.ConversionWebhook != config.ConversionWebhook
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	configV1 "github.com/litmuschaos/chaos-scheduler/api/config/v1alpha1"
+)
+
+// writeConfig writes the configuration file in a temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const configHeader = `apiVersion: config.litmuschaos.io/v1alpha1
+kind: ChaosSchedulerConfig
+`
+
+func TestDefault(t *testing.T) {
+	config := Default()
+
+	if config.Metrics.BindAddress != ":8080" || config.Health.HealthProbeBindAddress != ":8081" || *config.Webhook.Port != 9443 {
+		t.Errorf("manager defaults = %+v", config.ControllerManagerConfigurationSpec)
+	}
+	election := config.LeaderElection
+	if election.LeaderElect == nil || !*election.LeaderElect || election.ResourceName != "chaos-scheduler.litmuschaos.io" {
+		t.Errorf("leaderElection = %+v, want it enabled with the chaos-scheduler lease", election)
+	}
+	if election.LeaseDuration.Duration != 15*time.Second || election.RenewDeadline.Duration != 10*time.Second || election.RetryPeriod.Duration != 2*time.Second {
+		t.Errorf("leaderElection timings = %v, %v, %v", election.LeaseDuration, election.RenewDeadline, election.RetryPeriod)
+	}
+	if config.MaxConcurrentReconciles != 1 || config.KillSwitch.Name != "chaos-kill-switch" ||
+		config.Calendar.BindAddress != "127.0.0.1:8082" || config.Calendar.Days != 30 || config.Calendar.EventDuration.Duration != 15*time.Minute ||
+		config.CloudEvents.OutboxName != "chaos-scheduler-outbox" {
+		t.Errorf("scheduler defaults = %+v", config)
+	}
+	if err := Validate(config); err != nil {
+		t.Errorf("the default config is invalid: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+		check   func(t *testing.T, c *configV1.ChaosSchedulerConfig)
+	}{
+		{
+			name: "full config",
+			content: configHeader + `
+leaderElection:
+  leaderElect: false
+maxConcurrentReconciles: 4
+rateLimiter:
+  baseDelay: 10ms
+  maxDelay: 5m
+  qps: 20
+  burst: 200
+logging:
+  format: json
+  level: "2"
+defaults:
+  concurrencyPolicy: Allow
+  historyLimit: 3
+  timeZone: Europe/Paris
+prometheusURL: http://prometheus:9090
+`,
+			check: func(t *testing.T, c *configV1.ChaosSchedulerConfig) {
+				if *c.LeaderElection.LeaderElect || c.LeaderElection.ResourceName != "chaos-scheduler.litmuschaos.io" {
+					t.Errorf("leaderElection = %+v, want it disabled with the default lease", c.LeaderElection)
+				}
+				if c.MaxConcurrentReconciles != 4 || c.RateLimiter.BaseDelay.Duration != 10*time.Millisecond || c.RateLimiter.QPS != 20 {
+					t.Errorf("controller settings = %d, %+v", c.MaxConcurrentReconciles, c.RateLimiter)
+				}
+				if c.Logging.Format != "json" || c.Logging.Level != "2" {
+					t.Errorf("logging = %+v", c.Logging)
+				}
+				if c.Defaults.ConcurrencyPolicy != "Allow" || *c.Defaults.HistoryLimit != 3 || c.Defaults.TimeZone != "Europe/Paris" {
+					t.Errorf("defaults = %+v", c.Defaults)
+				}
+				if c.PrometheusURL != "http://prometheus:9090" || c.KillSwitch.Name != "chaos-kill-switch" {
+					t.Errorf("prometheusURL %q and killSwitch %+v", c.PrometheusURL, c.KillSwitch)
+				}
+			},
+		},
+		{
+			name:    "empty config is defaulted",
+			content: configHeader,
+			check: func(t *testing.T, c *configV1.ChaosSchedulerConfig) {
+				if c.MaxConcurrentReconciles != 1 || c.Metrics.BindAddress != ":8080" || !*c.LeaderElection.LeaderElect {
+					t.Errorf("config = %+v, want the defaults", c)
+				}
+			},
+		},
+		{
+			name:    "negative qps",
+			content: configHeader + "rateLimiter:\n  qps: -1\n",
+			wantErr: "invalid rateLimiter qps",
+		},
+		{
+			name:    "base delay above the max delay",
+			content: configHeader + "rateLimiter:\n  baseDelay: 10s\n  maxDelay: 1s\n",
+			wantErr: "invalid rateLimiter baseDelay",
+		},
+		{
+			name:    "invalid level",
+			content: configHeader + "logging:\n  level: verbose\n",
+			wantErr: "invalid logging level",
+		},
+		{
+			name:    "negative verbosity",
+			content: configHeader + "logging:\n  level: \"-1\"\n",
+			wantErr: "the verbosity should be positive",
+		},
+		{
+			name:    "invalid format",
+			content: configHeader + "logging:\n  format: text\n",
+			wantErr: "invalid logging format",
+		},
+		{
+			name:    "invalid zone",
+			content: configHeader + "defaults:\n  timeZone: Mars/Olympus\n",
+			wantErr: "invalid default timeZone",
+		},
+		{
+			name:    "invalid concurrency policy",
+			content: configHeader + "defaults:\n  concurrencyPolicy: Queue\n",
+			wantErr: "invalid default concurrencyPolicy",
+		},
+		{
+			name:    "negative history limit",
+			content: configHeader + "defaults:\n  historyLimit: -1\n",
+			wantErr: "invalid default historyLimit",
+		},
+		{
+			name:    "relative otlp endpoint",
+			content: configHeader + "tracing:\n  otlpEndpoint: otel-collector:4318\n",
+			wantErr: "invalid tracing otlpEndpoint",
+		},
+		{
+			name:    "renew deadline above the lease duration",
+			content: configHeader + "leaderElection:\n  leaseDuration: 5s\n  renewDeadline: 10s\n",
+			wantErr: "invalid leaderElection",
+		},
+		{
+			name:    "unknown kind",
+			content: "apiVersion: config.litmuschaos.io/v1alpha1\nkind: Unknown\n",
+			wantErr: "unable to decode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := Load(writeConfig(t, tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			tt.check(t, config)
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("loaded a missing file")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		level   string
+		want    zapcore.Level
+		wantErr bool
+	}{
+		{level: "", want: zapcore.InfoLevel},
+		{level: "debug", want: zapcore.DebugLevel},
+		{level: "ERROR", want: zapcore.ErrorLevel},
+		{level: "3", want: zapcore.Level(-3)},
+		{level: "-3", wantErr: true},
+		{level: "verbose", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.level)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v, error %v", tt.level, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CloudEvent is a CloudEvents 1.0 event whose data is encoded as json
+// It is stored as json in the outbox until it is delivered
+type CloudEvent struct {
+	ID      string    `json:"id"`
+	Source  string    `json:"source"`
+	Type    string    `json:"type"`
+	Subject string    `json:"subject,omitempty"`
+	Time    time.Time `json:"time"`
+	// Extensions are the extension attributes of the event, their names should be lower case alphanumeric
+	Extensions map[string]string `json:"extensions,omitempty"`
+	Data       interface{}       `json:"data,omitempty"`
+}
+
+// NewCloudEventRequest returns the request posting the event in the binary content mode of the HTTP protocol binding,
+// the attributes are sent as ce- headers and the data as the body
+func NewCloudEventRequest(ctx context.Context, url string, event CloudEvent) (*http.Request, error) {
+
+	body, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-id", event.ID)
+	req.Header.Set("ce-source", event.Source)
+	req.Header.Set("ce-type", event.Type)
+	if event.Subject != "" {
+		req.Header.Set("ce-subject", event.Subject)
+	}
+	if !event.Time.IsZero() {
+		req.Header.Set("ce-time", event.Time.UTC().Format(time.RFC3339Nano))
+	}
+	for name, value := range event.Extensions {
+		req.Header.Set("ce-"+name, value)
+	}
+	return req, nil
+}
+
+// ScheduleSource returns the source attribute of the CloudEvents about the chaosschedule
+func ScheduleSource(namespace, name string) string {
+	return fmt.Sprintf("/apis/litmuschaos.io/v1alpha1/namespaces/%s/chaosschedules/%s", namespace, name)
+}
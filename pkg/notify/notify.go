@@ -0,0 +1,255 @@
+// Package notify sends the events of the chaosschedules to the notification sinks they subscribe to, the Kubernetes
+// Events recorded by the scheduler expire after an hour
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+const (
+	// deliveryTimeout bounds a single delivery attempt
+	deliveryTimeout = 10 * time.Second
+	// maxDeliveryAttempts is the number of attempts after which a notification is dropped
+	maxDeliveryAttempts = 8
+	// workers is the number of notifications delivered at the same time
+	workers = 4
+
+	// SignatureHeader carries the HMAC-SHA256 of the body of the webhook sinks, as "sha256=<hex digest>"
+	SignatureHeader = "X-Chaos-Signature-256"
+	// eventType is the type of the CloudEvents sent to the cloudevents sinks
+	eventType = "io.litmuschaos.schedule.event"
+)
+
+var log = ctrl.Log.WithName("notify")
+
+// Notification is an event of a chaosschedule, as sent to the sinks
+type Notification struct {
+	// ID identifies the notification, it is the same across the delivery attempts
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+	// Type of the event, either Normal or Warning
+	Type     string            `json:"type"`
+	Reason   string            `json:"reason"`
+	Message  string            `json:"message"`
+	Schedule ScheduleReference `json:"schedule"`
+}
+
+// ScheduleReference identifies the chaosschedule the event is about
+type ScheduleReference struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+}
+
+// delivery is a notification queued for a sink
+type delivery struct {
+	sink         schedulerV1.NotificationSink
+	notification Notification
+}
+
+// Dispatcher delivers the notifications to the sinks in the background
+// The failed deliveries are retried with an exponential backoff, the notification is dropped after maxDeliveryAttempts
+type Dispatcher struct {
+	reader client.Reader
+	queue  workqueue.RateLimitingInterface
+}
+
+// NewDispatcher returns a dispatcher reading the Secrets of the sinks with the given reader
+// The reader should not be the cache of the manager, which would watch all the Secrets
+func NewDispatcher(reader client.Reader) *Dispatcher {
+	return newDispatcher(reader, workqueue.NewItemExponentialFailureRateLimiter(time.Second, 5*time.Minute))
+}
+
+func newDispatcher(reader client.Reader, rateLimiter workqueue.RateLimiter) *Dispatcher {
+	return &Dispatcher{
+		reader: reader,
+		queue:  workqueue.NewNamedRateLimitingQueue(rateLimiter, "notifications"),
+	}
+}
+
+// Enqueue queues the notification for delivery to the sink
+func (d *Dispatcher) Enqueue(sink schedulerV1.NotificationSink, notification Notification) {
+	d.queue.Add(&delivery{sink: sink, notification: notification})
+}
+
+// Start delivers the queued notifications until the context is cancelled
+func (d *Dispatcher) Start(ctx context.Context) error {
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d.processNext(ctx) {
+			}
+		}()
+	}
+	log.Info("Delivering the notifications")
+
+	<-ctx.Done()
+	d.queue.ShutDown()
+	wg.Wait()
+	return nil
+}
+
+// processNext delivers the next notification of the queue, it returns false once the queue is shut down
+func (d *Dispatcher) processNext(ctx context.Context) bool {
+
+	item, shutdown := d.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer d.queue.Done(item)
+
+	next := item.(*delivery)
+	err := d.deliver(ctx, next)
+	switch {
+	case err == nil:
+		d.queue.Forget(item)
+	case d.queue.NumRequeues(item)+1 < maxDeliveryAttempts:
+		log.Info("Unable to deliver the notification, retrying", "Sink", next.sink.Name, "Reason", next.notification.Reason,
+			"Schedule", next.notification.Schedule.Namespace+"/"+next.notification.Schedule.Name, "Error", err.Error())
+		d.queue.AddRateLimited(item)
+	default:
+		log.Error(err, "Unable to deliver the notification, dropping it", "Sink", next.sink.Name, "Reason", next.notification.Reason,
+			"Schedule", next.notification.Schedule.Namespace+"/"+next.notification.Schedule.Name, "Attempts", maxDeliveryAttempts)
+		d.queue.Forget(item)
+	}
+	return true
+}
+
+// deliver sends the notification to the sink, any response other than 2xx is a failure
+func (d *Dispatcher) deliver(ctx context.Context, item *delivery) error {
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	namespace := item.notification.Schedule.Namespace
+	url := item.sink.URL
+	if ref := item.sink.URLSecretRef; ref != nil {
+		value, err := d.readSecret(ctx, namespace, ref)
+		if err != nil {
+			return err
+		}
+		url = strings.TrimSpace(string(value))
+	}
+
+	var req *http.Request
+	var err error
+	switch item.sink.Type {
+	case schedulerV1.SlackSink:
+		req, err = newJSONRequest(ctx, url, map[string]string{"text": FormatMessage(item.notification)})
+	case schedulerV1.CloudEventsSink:
+		n := item.notification
+		req, err = NewCloudEventRequest(ctx, url, CloudEvent{
+			ID:         n.ID,
+			Source:     ScheduleSource(n.Schedule.Namespace, n.Schedule.Name),
+			Type:       eventType,
+			Subject:    n.Reason,
+			Time:       n.Time,
+			Extensions: map[string]string{"eventtype": strings.ToLower(n.Type)},
+			Data:       n,
+		})
+	default:
+		req, err = d.newWebhookRequest(ctx, url, namespace, item)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newWebhookRequest returns the request posting the notification as json, signed when the sink refers to an HMAC key
+func (d *Dispatcher) newWebhookRequest(ctx context.Context, url, namespace string, item *delivery) (*http.Request, error) {
+
+	body, err := json.Marshal(item.notification)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Chaos-Event", item.notification.Reason)
+	req.Header.Set("X-Chaos-Delivery", item.notification.ID)
+
+	if ref := item.sink.HMACSecretRef; ref != nil {
+		key, err := d.readSecret(ctx, namespace, ref)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(SignatureHeader, Sign(key, body))
+	}
+	return req, nil
+}
+
+// readSecret returns the value of the key of the Secret
+func (d *Dispatcher) readSecret(ctx context.Context, namespace string, ref *corev1.SecretKeySelector) ([]byte, error) {
+
+	secret := &corev1.Secret{}
+	if err := d.reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return nil, fmt.Errorf("unable to read secret %s/%s, err: %v", namespace, ref.Name, err)
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in secret %s/%s", ref.Key, namespace, ref.Name)
+	}
+	return value, nil
+}
+
+// Sign returns the signature of the body as sent in the SignatureHeader
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// FormatMessage returns the notification as a single line of text, as sent to the Slack sinks
+func FormatMessage(n Notification) string {
+	return fmt.Sprintf("[%s] chaosschedule %s/%s: %s: %s", n.Type, n.Schedule.Namespace, n.Schedule.Name, n.Reason, n.Message)
+}
+
+// newJSONRequest returns the request posting the value as json
+func newJSONRequest(ctx context.Context, url string, value interface{}) (*http.Request, error) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
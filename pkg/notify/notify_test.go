@@ -0,0 +1,202 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+var testNotification = Notification{
+	ID:       "0b9f0a52-7c5c-4f0c-9d43-3f6a2c1e8d11",
+	Time:     time.Date(2021, time.October, 6, 10, 0, 0, 0, time.UTC),
+	Type:     corev1.EventTypeWarning,
+	Reason:   "MissEngine",
+	Message:  "Missed scheduled time to start chaosengine",
+	Schedule: ScheduleReference{Namespace: "litmus", Name: "schedule-nginx", UID: "uid"},
+}
+
+func newTestDispatcher(objects ...*corev1.Secret) *Dispatcher {
+	builder := fake.NewClientBuilder()
+	for _, object := range objects {
+		builder = builder.WithObjects(object)
+	}
+	return newDispatcher(builder.Build(), workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, 10*time.Millisecond))
+}
+
+func TestDeliverWebhook(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "audit-webhook", Namespace: "litmus"},
+		Data:       map[string][]byte{"key": []byte("s3cr3t")},
+	}
+
+	var got Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if signature := r.Header.Get(SignatureHeader); signature != Sign([]byte("s3cr3t"), body) {
+			t.Errorf("signature = %q, want the HMAC of the body", signature)
+		}
+		if event := r.Header.Get("X-Chaos-Event"); event != "MissEngine" {
+			t.Errorf("X-Chaos-Event = %q, want MissEngine", event)
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("unable to decode the body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher(secret)
+	sink := schedulerV1.NotificationSink{
+		Name:          "audit",
+		Type:          schedulerV1.WebhookSink,
+		URL:           server.URL,
+		HMACSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "audit-webhook"}, Key: "key"},
+	}
+	if err := d.deliver(context.Background(), &delivery{sink: sink, notification: testNotification}); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != testNotification.ID || got.Schedule != testNotification.Schedule || !got.Time.Equal(testNotification.Time) {
+		t.Fatalf("delivered %+v, want %+v", got, testNotification)
+	}
+}
+
+func TestDeliverSlack(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "slack-webhook", Namespace: "litmus"},
+		Data:       map[string][]byte{"url": []byte(server.URL + "\n")},
+	}
+	d := newTestDispatcher(secret)
+	sink := schedulerV1.NotificationSink{
+		Name:         "oncall",
+		Type:         schedulerV1.SlackSink,
+		URLSecretRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "slack-webhook"}, Key: "url"},
+	}
+	if err := d.deliver(context.Background(), &delivery{sink: sink, notification: testNotification}); err != nil {
+		t.Fatal(err)
+	}
+	want := "[Warning] chaosschedule litmus/schedule-nginx: MissEngine: Missed scheduled time to start chaosengine"
+	if got["text"] != want {
+		t.Fatalf("text = %q, want %q", got["text"], want)
+	}
+}
+
+func TestDeliverCloudEvents(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher()
+	sink := schedulerV1.NotificationSink{Name: "broker", Type: schedulerV1.CloudEventsSink, URL: server.URL}
+	if err := d.deliver(context.Background(), &delivery{sink: sink, notification: testNotification}); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"ce-specversion": "1.0",
+		"ce-id":          testNotification.ID,
+		"ce-source":      "/apis/litmuschaos.io/v1alpha1/namespaces/litmus/chaosschedules/schedule-nginx",
+		"ce-type":        eventType,
+		"ce-subject":     "MissEngine",
+		"ce-time":        "2021-10-06T10:00:00Z",
+		"ce-eventtype":   "warning",
+		"Content-Type":   "application/json",
+	} {
+		if got := header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	var attempts int32
+	delivered := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		close(delivered)
+	}))
+	defer server.Close()
+
+	d := newTestDispatcher()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- d.Start(ctx) }()
+
+	d.Enqueue(schedulerV1.NotificationSink{Name: "audit", Type: schedulerV1.WebhookSink, URL: server.URL}, testNotification)
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("notification not delivered after %d attempts", atomic.LoadInt32(&attempts))
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRecorderSubscriptions(t *testing.T) {
+	events := record.NewFakeRecorder(10)
+	d := newTestDispatcher()
+	recorder := NewRecorder(events, d)
+
+	schedule := &schedulerV1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "schedule-nginx", Namespace: "litmus"},
+		Spec: schedulerV1.ChaosScheduleSpec{
+			Notifications: []schedulerV1.NotificationSink{
+				{Name: "audit", Type: schedulerV1.WebhookSink, URL: "https://audit.example.com"},
+				{Name: "oncall", Type: schedulerV1.SlackSink, URL: "https://hooks.slack.com/services/T0", Events: []string{"MissEngine"}},
+			},
+		},
+	}
+
+	recorder.Eventf(schedule, corev1.EventTypeNormal, "SuccessfulCreate", "Created engine %s", "schedule-nginx-1633514400")
+	recorder.Eventf(schedule, corev1.EventTypeWarning, "MissEngine", "Missed scheduled time to start chaosengine")
+	recorder.Eventf(&corev1.Pod{}, corev1.EventTypeWarning, "MissEngine", "not a chaosschedule")
+
+	if got := len(events.Events); got != 3 {
+		t.Fatalf("recorded %d events, want 3", got)
+	}
+
+	var sinks []string
+	for d.queue.Len() > 0 {
+		item, _ := d.queue.Get()
+		next := item.(*delivery)
+		sinks = append(sinks, next.sink.Name+"/"+next.notification.Reason)
+		d.queue.Done(item)
+	}
+	want := []string{"audit/SuccessfulCreate", "audit/MissEngine", "oncall/MissEngine"}
+	if len(sinks) != len(want) {
+		t.Fatalf("queued %v, want %v", sinks, want)
+	}
+	for i := range want {
+		if sinks[i] != want[i] {
+			t.Fatalf("queued %v, want %v", sinks, want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxOutboxEvents bounds the size of the outbox ConfigMap, the oldest events are dropped beyond it
+	maxOutboxEvents = 500
+	// outboxFlushInterval is the interval at which the outbox is flushed when it is not woken up by a new event
+	outboxFlushInterval = 30 * time.Second
+	// maxOutboxBackoff is the longest wait between two flushes while the sink is failing
+	maxOutboxBackoff = 5 * time.Minute
+)
+
+// Outbox delivers the CloudEvents to the sink at least once
+// The events are stored in a ConfigMap before they are delivered, and only removed from it once the sink accepted them,
+// so that the events emitted right before a restart of the scheduler are delivered by the next leader. The events
+// are identified by deterministic ids, emitting the same event twice stores it once
+type Outbox struct {
+	// Client writes the outbox ConfigMap
+	Client client.Client
+	// Reader reads the outbox ConfigMap, it should not be the cache of the manager which only holds the kill switch
+	Reader client.Reader
+	// Key is the name and the namespace of the outbox ConfigMap
+	Key types.NamespacedName
+	// SinkURL is the address the events are posted to
+	SinkURL string
+
+	wake chan struct{}
+}
+
+// NewOutbox returns the outbox of the events posted to the sink
+func NewOutbox(c client.Client, reader client.Reader, key types.NamespacedName, sinkURL string) *Outbox {
+	return &Outbox{Client: c, Reader: reader, Key: key, SinkURL: sinkURL, wake: make(chan struct{}, 1)}
+}
+
+// Emit stores the event in the outbox, it is delivered in the background
+func (o *Outbox) Emit(ctx context.Context, event CloudEvent) error {
+
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		outbox := &corev1.ConfigMap{}
+		err := o.Reader.Get(ctx, o.Key, outbox)
+		if k8serrors.IsNotFound(err) {
+			outbox = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: o.Key.Name, Namespace: o.Key.Namespace},
+				Data:       map[string]string{event.ID: string(value)},
+			}
+			return o.Client.Create(ctx, outbox)
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, found := outbox.Data[event.ID]; found {
+			return nil
+		}
+		if outbox.Data == nil {
+			outbox.Data = map[string]string{}
+		}
+		for _, dropped := range oldestEvents(outbox.Data, len(outbox.Data)+1-maxOutboxEvents) {
+			log.Info("Outbox is full, dropping the oldest event", "ID", dropped.ID, "Type", dropped.Type)
+			delete(outbox.Data, dropped.ID)
+		}
+		outbox.Data[event.ID] = string(value)
+		return o.Client.Update(ctx, outbox)
+	})
+	// the concurrent creations of the outbox are retried as conflicts
+	if k8serrors.IsAlreadyExists(err) {
+		return o.Emit(ctx, event)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to store the event %s in the outbox, err: %v", event.ID, err)
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start flushes the outbox until the context is cancelled, the flushes are backed off while the sink is failing
+func (o *Outbox) Start(ctx context.Context) error {
+
+	log.Info("Delivering the CloudEvents", "Sink", o.SinkURL, "Outbox", o.Key.String())
+	// the events left by the previous leader are flushed right away
+	wait, retryWait := time.Duration(0), time.Duration(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		case <-o.wake:
+		}
+
+		if err := o.flush(ctx); err != nil {
+			retryWait = backoff(retryWait)
+			wait = retryWait
+			log.Info("Unable to flush the outbox, retrying", "Error", err.Error(), "RetryAfter", wait.String())
+			continue
+		}
+		wait, retryWait = outboxFlushInterval, 0
+	}
+}
+
+// flush delivers the stored events in order and removes the delivered ones from the outbox
+// The delivery stops at the first failure, so that the events are not reordered
+func (o *Outbox) flush(ctx context.Context) error {
+
+	outbox := &corev1.ConfigMap{}
+	if err := o.Reader.Get(ctx, o.Key, outbox); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	var delivered []string
+	var errDeliver error
+	for _, event := range oldestEvents(outbox.Data, len(outbox.Data)) {
+		// the entries which cannot be decoded are removed without being sent
+		if event.Type == "" {
+			delivered = append(delivered, event.ID)
+			continue
+		}
+		if errDeliver = o.deliver(ctx, event); errDeliver != nil {
+			break
+		}
+		delivered = append(delivered, event.ID)
+	}
+	if len(delivered) == 0 {
+		return errDeliver
+	}
+
+	errUpdate := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := o.Reader.Get(ctx, o.Key, outbox); err != nil {
+			return err
+		}
+		for _, id := range delivered {
+			delete(outbox.Data, id)
+		}
+		return o.Client.Update(ctx, outbox)
+	})
+	if errUpdate != nil {
+		// the delivered events are sent again by the next flush
+		return fmt.Errorf("unable to remove the delivered events from the outbox, err: %v", errUpdate)
+	}
+	return errDeliver
+}
+
+// deliver posts the event to the sink, any response other than 2xx is a failure
+func (o *Outbox) deliver(ctx context.Context, event CloudEvent) error {
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	req, err := NewCloudEventRequest(ctx, o.SinkURL, event)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink returned status %d for event %s", resp.StatusCode, event.ID)
+	}
+	return nil
+}
+
+// oldestEvents decodes the events of the outbox and returns the n oldest ones, the invalid entries come first with
+// an empty type so that they are dropped
+func oldestEvents(data map[string]string, n int) []CloudEvent {
+	if n <= 0 {
+		return nil
+	}
+
+	events := make([]CloudEvent, 0, len(data))
+	for id, value := range data {
+		event := CloudEvent{}
+		if err := json.Unmarshal([]byte(value), &event); err != nil {
+			log.Error(err, "Invalid event in the outbox", "ID", id)
+			event = CloudEvent{}
+		}
+		event.ID = id
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].Time.Equal(events[j].Time) {
+			return events[i].Time.Before(events[j].Time)
+		}
+		return events[i].ID < events[j].ID
+	})
+	if n < len(events) {
+		events = events[:n]
+	}
+	return events
+}
+
+// backoff doubles the wait between two failed flushes, from a second up to maxOutboxBackoff
+func backoff(wait time.Duration) time.Duration {
+	switch {
+	case wait < time.Second:
+		return time.Second
+	case 2*wait > maxOutboxBackoff:
+		return maxOutboxBackoff
+	}
+	return 2 * wait
+}
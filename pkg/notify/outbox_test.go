@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var outboxKey = types.NamespacedName{Name: "chaos-scheduler-outbox", Namespace: "litmus"}
+
+func newTestOutbox(sinkURL string) *Outbox {
+	c := fake.NewClientBuilder().Build()
+	return NewOutbox(c, c, outboxKey, sinkURL)
+}
+
+func newTestEvent(i int) CloudEvent {
+	return CloudEvent{
+		ID:     fmt.Sprintf("uid.%d.run.created", i),
+		Source: ScheduleSource("litmus", "schedule-nginx"),
+		Type:   "io.litmuschaos.schedule.run.created",
+		Time:   time.Date(2021, time.October, 6, 10, i, 0, 0, time.UTC),
+		Data:   map[string]string{"runID": fmt.Sprint(i)},
+	}
+}
+
+func getOutbox(t *testing.T, o *Outbox) map[string]string {
+	outbox := &corev1.ConfigMap{}
+	if err := o.Reader.Get(context.TODO(), o.Key, outbox); err != nil {
+		t.Fatal(err)
+	}
+	return outbox.Data
+}
+
+func TestOutboxEmit(t *testing.T) {
+	o := newTestOutbox("http://sink")
+
+	for _, i := range []int{1, 2, 1} {
+		if err := o.Emit(context.TODO(), newTestEvent(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if data := getOutbox(t, o); len(data) != 2 {
+		t.Fatalf("outbox holds %d events, want the 2 distinct ones", len(data))
+	}
+
+	for i := 3; i <= maxOutboxEvents+1; i++ {
+		if err := o.Emit(context.TODO(), newTestEvent(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := getOutbox(t, o)
+	if len(data) != maxOutboxEvents {
+		t.Fatalf("outbox holds %d events, want %d", len(data), maxOutboxEvents)
+	}
+	if _, found := data["uid.1.run.created"]; found {
+		t.Fatal("oldest event is kept in the full outbox")
+	}
+}
+
+func TestOutboxFlush(t *testing.T) {
+	var received []string
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("ce-id")
+		if failing && id == "uid.2.run.created" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		received = append(received, id)
+	}))
+	defer server.Close()
+
+	o := newTestOutbox(server.URL)
+	for _, i := range []int{3, 1, 2} {
+		if err := o.Emit(context.TODO(), newTestEvent(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the delivery stops at the failing event, the later ones are kept in order
+	if err := o.flush(context.TODO()); err == nil {
+		t.Fatal("flush() succeeded, want the error of the sink")
+	}
+	if len(received) != 1 || received[0] != "uid.1.run.created" {
+		t.Fatalf("received %v, want only the oldest event", received)
+	}
+	if data := getOutbox(t, o); len(data) != 2 {
+		t.Fatalf("outbox holds %d events, want the 2 undelivered ones", len(data))
+	}
+
+	failing = false
+	if err := o.flush(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"uid.1.run.created", "uid.2.run.created", "uid.3.run.created"}
+	if fmt.Sprint(received) != fmt.Sprint(want) {
+		t.Fatalf("received %v, want %v", received, want)
+	}
+	if data := getOutbox(t, o); len(data) != 0 {
+		t.Fatalf("outbox holds %v, want no event", data)
+	}
+}
+
+func TestOutboxSurvivesRestart(t *testing.T) {
+	delivered := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- r.Header.Get("ce-id")
+	}))
+	defer server.Close()
+
+	// the event is stored by a scheduler which stops before delivering it
+	c := fake.NewClientBuilder().Build()
+	if err := NewOutbox(c, c, outboxKey, server.URL).Emit(context.TODO(), newTestEvent(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	next := NewOutbox(c, c, outboxKey, server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = next.Start(ctx) }()
+
+	select {
+	case id := <-delivered:
+		if id != "uid.1.run.created" {
+			t.Fatalf("delivered %q, want the stored event", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stored event not delivered by the next scheduler")
+	}
+}
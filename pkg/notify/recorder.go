@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// Recorder records the events with the wrapped recorder, and queues the events of the chaosschedules for the sinks
+// they subscribe to
+type Recorder struct {
+	record.EventRecorder
+	Dispatcher *Dispatcher
+}
+
+// NewRecorder returns a recorder notifying the sinks of the chaosschedules through the dispatcher
+func NewRecorder(recorder record.EventRecorder, dispatcher *Dispatcher) *Recorder {
+	return &Recorder{EventRecorder: recorder, Dispatcher: dispatcher}
+}
+
+// Event records the event and notifies the sinks subscribing to it
+func (r *Recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, message)
+	r.notify(object, eventtype, reason, message)
+}
+
+// Eventf is just like Event, but with Sprintf for the message field
+func (r *Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf is just like Eventf, but with annotations attached to the recorded event
+func (r *Recorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	message := fmt.Sprintf(messageFmt, args...)
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	r.notify(object, eventtype, reason, message)
+}
+
+// notify queues the event for the sinks of the chaosschedule subscribing to it, the events of the other objects
+// are only recorded
+func (r *Recorder) notify(object runtime.Object, eventtype, reason, message string) {
+
+	schedule, ok := object.(*schedulerV1.ChaosSchedule)
+	if !ok || len(schedule.Spec.Notifications) == 0 {
+		return
+	}
+
+	notification := Notification{
+		ID:      string(uuid.NewUUID()),
+		Time:    time.Now(),
+		Type:    eventtype,
+		Reason:  reason,
+		Message: message,
+		Schedule: ScheduleReference{
+			Namespace: schedule.Namespace,
+			Name:      schedule.Name,
+			UID:       schedule.UID,
+		},
+	}
+	for _, sink := range schedule.Spec.Notifications {
+		if Subscribes(sink, reason) {
+			r.Dispatcher.Enqueue(*sink.DeepCopy(), notification)
+		}
+	}
+}
+
+// Subscribes checks whether the sink subscribes to the events with the given reason
+func Subscribes(sink schedulerV1.NotificationSink, reason string) bool {
+	if len(sink.Events) == 0 {
+		return true
+	}
+	for _, event := range sink.Events {
+		if event == reason {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sdk builds and manages ChaosSchedules from Go programs, on top of the clientset of pkg/client
+package sdk
+
+import (
+	"sort"
+	"time"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/validation"
+)
+
+// ScheduleBuilder assembles a ChaosSchedule, e.g.
+//
+//	schedule, err := sdk.NewSchedule("schedule-nginx", "default").
+//		EveryMinutes(30).
+//		OnDays("Mon-Fri").
+//		DuringHours("9-17").
+//		Target("default", "app=nginx", "deployment").
+//		Experiment("pod-delete", map[string]string{"TOTAL_CHAOS_DURATION": "30"}).
+//		ServiceAccount("pod-delete-sa").
+//		Build()
+//
+// The type of schedule is set by the last call among Now, Once, EveryMinutes, EveryHours and RRule
+type ScheduleBuilder struct {
+	schedule *schedulerV1.ChaosSchedule
+
+	// the repeat and rrule schedules share the time range, it is set on the schedule by Build
+	timeRange *schedulerV1.TimeRange
+	workHours *schedulerV1.WorkHours
+	workDays  *schedulerV1.WorkDays
+	random    bool
+}
+
+// NewSchedule starts the build of a ChaosSchedule with the given name and namespace
+func NewSchedule(name, namespace string) *ScheduleBuilder {
+	return &ScheduleBuilder{
+		schedule: &schedulerV1.ChaosSchedule{
+			TypeMeta:   metav1.TypeMeta{APIVersion: schedulerV1.GroupVersion.String(), Kind: "ChaosSchedule"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		},
+	}
+}
+
+// Labels adds the labels to the ChaosSchedule, which are copied to its engines
+func (b *ScheduleBuilder) Labels(labels map[string]string) *ScheduleBuilder {
+	if b.schedule.Labels == nil {
+		b.schedule.Labels = map[string]string{}
+	}
+	for key, value := range labels {
+		b.schedule.Labels[key] = value
+	}
+	return b
+}
+
+// Now runs the chaos once, as soon as the ChaosSchedule is created
+func (b *ScheduleBuilder) Now() *ScheduleBuilder {
+	b.schedule.Spec.Schedule = schedulerV1.Schedule{Now: true}
+	return b
+}
+
+// Once runs the chaos once, at the given time
+func (b *ScheduleBuilder) Once(executionTime time.Time) *ScheduleBuilder {
+	b.schedule.Spec.Schedule = schedulerV1.Schedule{Once: &schedulerV1.ScheduleOnce{ExecutionTime: metav1.NewTime(executionTime)}}
+	return b
+}
+
+// EveryMinutes repeats the chaos every n minutes
+func (b *ScheduleBuilder) EveryMinutes(n int) *ScheduleBuilder {
+	return b.repeat(&schedulerV1.MinChaosInterval{Minute: &schedulerV1.Minute{EveryNthMinute: n}})
+}
+
+// EveryHours repeats the chaos every n hours, at the given minute of the hour
+func (b *ScheduleBuilder) EveryHours(n, minuteOfTheHour int) *ScheduleBuilder {
+	return b.repeat(&schedulerV1.MinChaosInterval{Hour: &schedulerV1.Hour{EveryNthHour: n, MinuteOfTheHour: minuteOfTheHour}})
+}
+
+func (b *ScheduleBuilder) repeat(interval *schedulerV1.MinChaosInterval) *ScheduleBuilder {
+	b.schedule.Spec.Schedule = schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+		Properties: schedulerV1.ScheduleRepeatProperties{MinChaosInterval: interval},
+	}}
+	return b
+}
+
+// RRule runs the chaos as per the iCalendar recurrence rule, e.g. "FREQ=WEEKLY;BYDAY=TU,TH;BYHOUR=10"
+func (b *ScheduleBuilder) RRule(rule string, excludedTimes ...time.Time) *ScheduleBuilder {
+	rrule := &schedulerV1.ScheduleRRule{Rule: rule}
+	for _, excluded := range excludedTimes {
+		rrule.ExcludedTimes = append(rrule.ExcludedTimes, metav1.NewTime(excluded))
+	}
+	b.schedule.Spec.Schedule = schedulerV1.Schedule{RRule: rrule}
+	return b
+}
+
+// Between limits the runs of the repeat and rrule schedules to the time range, a zero time leaves its end open
+func (b *ScheduleBuilder) Between(start, end time.Time) *ScheduleBuilder {
+	b.timeRange = &schedulerV1.TimeRange{}
+	if !start.IsZero() {
+		b.timeRange.StartTime = &metav1.Time{Time: start}
+	}
+	if !end.IsZero() {
+		b.timeRange.EndTime = &metav1.Time{Time: end}
+	}
+	return b
+}
+
+// DuringHours limits the runs of the repeat schedule to the hours, given like "9-12,14"
+func (b *ScheduleBuilder) DuringHours(hours string) *ScheduleBuilder {
+	b.workHours = &schedulerV1.WorkHours{IncludedHours: hours}
+	return b
+}
+
+// OnDays limits the runs of the repeat schedule to the days of the week, given like "Mon-Wed,Fri" or "1-3,5"
+func (b *ScheduleBuilder) OnDays(days string) *ScheduleBuilder {
+	b.workDays = &schedulerV1.WorkDays{IncludedDays: days}
+	return b
+}
+
+// Random starts the runs of the repeat schedule at a random time
+func (b *ScheduleBuilder) Random() *ScheduleBuilder {
+	b.random = true
+	return b
+}
+
+// Target sets the application into which the chaos is injected
+func (b *ScheduleBuilder) Target(namespace, label, kind string) *ScheduleBuilder {
+	b.schedule.Spec.EngineTemplateSpec.Appinfo = operatorV1.ApplicationParams{Appns: namespace, Applabel: label, AppKind: kind}
+	return b
+}
+
+// ServiceAccount sets the service account of the chaos runner pods
+func (b *ScheduleBuilder) ServiceAccount(name string) *ScheduleBuilder {
+	b.schedule.Spec.EngineTemplateSpec.ChaosServiceAccount = name
+	return b
+}
+
+// Experiment adds the experiment to the engine, with the env overridden by the engine
+func (b *ScheduleBuilder) Experiment(name string, env map[string]string) *ScheduleBuilder {
+	experiment := operatorV1.ExperimentList{Name: name}
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		experiment.Spec.Components.ENV = append(experiment.Spec.Components.ENV, corev1.EnvVar{Name: key, Value: env[key]})
+	}
+	b.schedule.Spec.EngineTemplateSpec.Experiments = append(b.schedule.Spec.EngineTemplateSpec.Experiments, experiment)
+	return b
+}
+
+// EngineTemplate replaces the whole spec of the engine, for the fields which have no method of their own
+func (b *ScheduleBuilder) EngineTemplate(spec operatorV1.ChaosEngineSpec) *ScheduleBuilder {
+	b.schedule.Spec.EngineTemplateSpec = *spec.DeepCopy()
+	return b
+}
+
+// ConcurrencyPolicy sets whether a run may start while the engines of the previous one are still active
+func (b *ScheduleBuilder) ConcurrencyPolicy(policy schedulerV1.ConcurrencyPolicy) *ScheduleBuilder {
+	b.schedule.Spec.ConcurrencyPolicy = policy
+	return b
+}
+
+// HistoryLimit sets the number of finished runs retained in the status
+func (b *ScheduleBuilder) HistoryLimit(limit int32) *ScheduleBuilder {
+	b.schedule.Spec.HistoryLimit = &limit
+	return b
+}
+
+// MutexGroup prevents the ChaosSchedule from running at the same time as the other schedules of the group
+func (b *ScheduleBuilder) MutexGroup(name string, policy schedulerV1.MutexPolicy) *ScheduleBuilder {
+	b.schedule.Spec.MutexGroup = &schedulerV1.MutexGroup{Name: name, Policy: policy}
+	return b
+}
+
+// Build returns the ChaosSchedule, or the errors found by the validation of the controller
+func (b *ScheduleBuilder) Build() (*schedulerV1.ChaosSchedule, error) {
+	schedule := b.schedule.DeepCopy()
+	if repeat := schedule.Spec.Schedule.Repeat; repeat != nil {
+		repeat.TimeRange = b.timeRange.DeepCopy()
+		repeat.WorkHours = b.workHours.DeepCopy()
+		repeat.WorkDays = b.workDays.DeepCopy()
+		repeat.Properties.Random = b.random
+	}
+	if rrule := schedule.Spec.Schedule.RRule; rrule != nil {
+		rrule.TimeRange = b.timeRange.DeepCopy()
+	}
+	if err := Validate(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Validate checks the ChaosSchedule the way the controller does before scheduling it
+func Validate(schedule *schedulerV1.ChaosSchedule) error {
+	return validation.ValidateChaosSchedule(schedule).ToAggregate()
+}
@@ -0,0 +1,170 @@
+package sdk
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/diff"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+var (
+	start = time.Date(2021, time.October, 6, 9, 0, 0, 0, time.UTC)
+	end   = start.AddDate(0, 1, 0)
+)
+
+// newBuilder returns a builder with the target and the experiment set
+func newBuilder() *ScheduleBuilder {
+	return NewSchedule("schedule-nginx", "default").
+		Target("default", "app=nginx", "deployment").
+		Experiment("pod-delete", nil)
+}
+
+func TestScheduleBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *ScheduleBuilder
+		want    schedulerV1.Schedule
+	}{
+		{
+			name:    "now",
+			builder: newBuilder().Now(),
+			want:    schedulerV1.Schedule{Now: true},
+		},
+		{
+			name:    "once",
+			builder: newBuilder().Once(start),
+			want:    schedulerV1.Schedule{Once: &schedulerV1.ScheduleOnce{ExecutionTime: metav1.NewTime(start)}},
+		},
+		{
+			name:    "repeat every few minutes within the work hours and days",
+			builder: newBuilder().OnDays("Mon-Fri").DuringHours("9-17").Between(start, time.Time{}).Random().EveryMinutes(30),
+			want: schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+				TimeRange: &schedulerV1.TimeRange{StartTime: &metav1.Time{Time: start}},
+				Properties: schedulerV1.ScheduleRepeatProperties{
+					MinChaosInterval: &schedulerV1.MinChaosInterval{Minute: &schedulerV1.Minute{EveryNthMinute: 30}},
+					Random:           true,
+				},
+				WorkHours: &schedulerV1.WorkHours{IncludedHours: "9-17"},
+				WorkDays:  &schedulerV1.WorkDays{IncludedDays: "Mon-Fri"},
+			}},
+		},
+		{
+			name:    "repeat every few hours",
+			builder: newBuilder().EveryHours(2, 15),
+			want: schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+				Properties: schedulerV1.ScheduleRepeatProperties{
+					MinChaosInterval: &schedulerV1.MinChaosInterval{Hour: &schedulerV1.Hour{EveryNthHour: 2, MinuteOfTheHour: 15}},
+				},
+			}},
+		},
+		{
+			name:    "rrule",
+			builder: newBuilder().Between(start, end).RRule("FREQ=WEEKLY;BYDAY=TU,TH;BYHOUR=10", end.AddDate(0, 0, -1)),
+			want: schedulerV1.Schedule{RRule: &schedulerV1.ScheduleRRule{
+				Rule:          "FREQ=WEEKLY;BYDAY=TU,TH;BYHOUR=10",
+				ExcludedTimes: []metav1.Time{metav1.NewTime(end.AddDate(0, 0, -1))},
+				TimeRange:     &schedulerV1.TimeRange{StartTime: &metav1.Time{Time: start}, EndTime: &metav1.Time{Time: end}},
+			}},
+		},
+		{
+			name:    "the last type of schedule is kept",
+			builder: newBuilder().EveryMinutes(10).Now(),
+			want:    schedulerV1.Schedule{Now: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := tt.builder.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(schedule.Spec.Schedule, tt.want) {
+				t.Fatalf("Build() schedule:\n%s", diff.ObjectReflectDiff(tt.want, schedule.Spec.Schedule))
+			}
+		})
+	}
+}
+
+func TestScheduleBuilderEngine(t *testing.T) {
+	schedule, err := NewSchedule("schedule-nginx", "default").
+		Labels(map[string]string{"team": "sre"}).
+		EveryMinutes(10).
+		Target("default", "app=nginx", "deployment").
+		ServiceAccount("pod-delete-sa").
+		Experiment("pod-delete", map[string]string{"TOTAL_CHAOS_DURATION": "30", "CHAOS_INTERVAL": "10"}).
+		ConcurrencyPolicy(schedulerV1.ForbidConcurrent).
+		HistoryLimit(5).
+		MutexGroup("payments", schedulerV1.SkipRun).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := operatorV1.ChaosEngineSpec{
+		Appinfo:             operatorV1.ApplicationParams{Appns: "default", Applabel: "app=nginx", AppKind: "deployment"},
+		ChaosServiceAccount: "pod-delete-sa",
+		Experiments: []operatorV1.ExperimentList{{
+			Name: "pod-delete",
+			Spec: operatorV1.ExperimentAttributes{Components: operatorV1.ExperimentComponents{ENV: []corev1.EnvVar{
+				{Name: "CHAOS_INTERVAL", Value: "10"},
+				{Name: "TOTAL_CHAOS_DURATION", Value: "30"},
+			}}},
+		}},
+	}
+	if !apiequality.Semantic.DeepEqual(schedule.Spec.EngineTemplateSpec, want) {
+		t.Fatalf("Build() engine:\n%s", diff.ObjectReflectDiff(want, schedule.Spec.EngineTemplateSpec))
+	}
+	if schedule.Labels["team"] != "sre" || *schedule.Spec.HistoryLimit != 5 || schedule.Spec.MutexGroup.Name != "payments" ||
+		schedule.Spec.ConcurrencyPolicy != schedulerV1.ForbidConcurrent {
+		t.Fatalf("Build() = %+v", schedule)
+	}
+	if schedule.APIVersion != "litmuschaos.io/v1alpha1" || schedule.Kind != "ChaosSchedule" {
+		t.Fatalf("Build() type = %s %s", schedule.APIVersion, schedule.Kind)
+	}
+}
+
+func TestScheduleBuilderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *ScheduleBuilder
+		wantErr string
+	}{
+		{
+			name:    "no schedule",
+			builder: newBuilder(),
+			wantErr: "spec.schedule: Required value",
+		},
+		{
+			name:    "no experiment",
+			builder: NewSchedule("schedule-nginx", "default").Now(),
+			wantErr: "spec.engineTemplateSpec.experiments: Required value",
+		},
+		{
+			name:    "invalid work days",
+			builder: newBuilder().EveryHours(1, 0).OnDays("Mon-Someday"),
+			wantErr: "spec.schedule.repeat.workDays.includedDays",
+		},
+		{
+			name:    "time range ending before its start",
+			builder: newBuilder().RRule("FREQ=DAILY").Between(end, start),
+			wantErr: "spec.schedule.rrule.timeRange.endTime",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Build() error = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
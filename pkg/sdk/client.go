@@ -0,0 +1,217 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/client-go/util/retry"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/client/clientset/versioned"
+	typedV1 "github.com/litmuschaos/chaos-scheduler/pkg/client/clientset/versioned/typed/litmuschaos/v1alpha1"
+)
+
+// TriggeredByLabel is set on the ChaosSchedules created by TriggerNow, to the name of the ChaosSchedule they are run from
+const TriggeredByLabel = "litmuschaos.io/triggered-by"
+
+// Client manages the ChaosSchedules, the state changes are applied by the chaos-scheduler running in the cluster
+type Client struct {
+	clientset versioned.Interface
+}
+
+// New returns a Client using the given clientset
+func New(clientset versioned.Interface) *Client {
+	return &Client{clientset: clientset}
+}
+
+// NewForConfig returns a Client for the cluster of the config
+func NewForConfig(config *rest.Config) (*Client, error) {
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return New(clientset), nil
+}
+
+func (c *Client) schedules(namespace string) typedV1.ChaosScheduleInterface {
+	return c.clientset.LitmuschaosV1alpha1().ChaosSchedules(namespace)
+}
+
+// Create validates and creates the ChaosSchedule
+func (c *Client) Create(ctx context.Context, schedule *schedulerV1.ChaosSchedule) (*schedulerV1.ChaosSchedule, error) {
+	if err := Validate(schedule); err != nil {
+		return nil, err
+	}
+	return c.schedules(schedule.Namespace).Create(ctx, schedule, metav1.CreateOptions{})
+}
+
+// Get returns the ChaosSchedule
+func (c *Client) Get(ctx context.Context, namespace, name string) (*schedulerV1.ChaosSchedule, error) {
+	return c.schedules(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// Halt stops the ChaosSchedule from starting new runs, the engines which are already running are left to finish
+func (c *Client) Halt(ctx context.Context, namespace, name string) (*schedulerV1.ChaosSchedule, error) {
+	return c.setScheduleState(ctx, namespace, name, schedulerV1.StateHalted)
+}
+
+// Resume sets a halted or stopped ChaosSchedule active again
+func (c *Client) Resume(ctx context.Context, namespace, name string) (*schedulerV1.ChaosSchedule, error) {
+	return c.setScheduleState(ctx, namespace, name, schedulerV1.StateActive)
+}
+
+// Stop stops the ChaosSchedule from starting new runs and stops the engines which are already running
+func (c *Client) Stop(ctx context.Context, namespace, name string) (*schedulerV1.ChaosSchedule, error) {
+	return c.setScheduleState(ctx, namespace, name, schedulerV1.StateStopped)
+}
+
+// setScheduleState updates the scheduleState of the ChaosSchedule, retrying on the conflicts with the updates of the
+// chaos-scheduler
+func (c *Client) setScheduleState(ctx context.Context, namespace, name string, state schedulerV1.ScheduleState) (*schedulerV1.ChaosSchedule, error) {
+	var updated *schedulerV1.ChaosSchedule
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		schedule, err := c.Get(ctx, namespace, name)
+		if err != nil {
+			return err
+		}
+		if schedule.Status.Schedule.Status == schedulerV1.StatusCompleted {
+			return fmt.Errorf("the chaosschedule %s/%s is already completed", namespace, name)
+		}
+		if schedule.Spec.ScheduleState == state {
+			updated = schedule
+			return nil
+		}
+		schedule.Spec.ScheduleState = state
+		updated, err = c.schedules(namespace).Update(ctx, schedule, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// TriggerNow starts an extra run of the ChaosSchedule right away, outside of its schedule. The run is a ChaosSchedule
+// of type now with the same spec, owned by the ChaosSchedule and labelled with TriggeredByLabel. The returned
+// ChaosSchedule can be passed to WaitForCompletion
+// The run keeps the mutexGroup of the ChaosSchedule but not its targetRotation, whose state lives in the status of the
+// ChaosSchedule: the run targets the appinfo of the engine and leaves the rotation of the ChaosSchedule untouched
+func (c *Client) TriggerNow(ctx context.Context, namespace, name string) (*schedulerV1.ChaosSchedule, error) {
+	schedule, err := c.Get(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{TriggeredByLabel: schedule.Name}
+	for key, value := range schedule.Labels {
+		labels[key] = value
+	}
+	run := &schedulerV1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-manual-%d", schedule.Name, time.Now().Unix()),
+			Namespace:   schedule.Namespace,
+			Labels:      labels,
+			Annotations: schedule.Annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(schedule, schedulerV1.GroupVersion.WithKind("ChaosSchedule")),
+			},
+		},
+		Spec: *schedule.Spec.DeepCopy(),
+	}
+	run.Spec.Schedule = schedulerV1.Schedule{Now: true}
+	run.Spec.TargetRotation = nil
+	run.Spec.ScheduleState = schedulerV1.StateActive
+	return c.Create(ctx, run)
+}
+
+// WaitForNextRun waits until the ChaosSchedule starts a new run and returns it. It fails if the ChaosSchedule is
+// completed or stopped before, or once the context is done
+func (c *Client) WaitForNextRun(ctx context.Context, namespace, name string) (*schedulerV1.ChaosSchedule, error) {
+	schedule, err := c.Get(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	lastRun := schedule.Status.LastScheduleTime
+
+	return c.waitFor(ctx, namespace, name, func(schedule *schedulerV1.ChaosSchedule) (bool, error) {
+		if run := schedule.Status.LastScheduleTime; run != nil && (lastRun == nil || run.After(lastRun.Time)) {
+			return true, nil
+		}
+		switch schedule.Status.Schedule.Status {
+		case schedulerV1.StatusCompleted, schedulerV1.StatusStopped:
+			return false, fmt.Errorf("the chaosschedule %s/%s is %s", namespace, name, schedule.Status.Schedule.Status)
+		}
+		return false, nil
+	})
+}
+
+// WaitForCompletion waits until the ChaosSchedule is completed and returns it. It fails if the ChaosSchedule is stopped
+// before, or once the context is done
+func (c *Client) WaitForCompletion(ctx context.Context, namespace, name string) (*schedulerV1.ChaosSchedule, error) {
+	return c.waitFor(ctx, namespace, name, func(schedule *schedulerV1.ChaosSchedule) (bool, error) {
+		switch schedule.Status.Schedule.Status {
+		case schedulerV1.StatusCompleted:
+			return true, nil
+		case schedulerV1.StatusStopped:
+			return false, fmt.Errorf("the chaosschedule %s/%s is stopped", namespace, name)
+		}
+		return false, nil
+	})
+}
+
+// waitFor watches the ChaosSchedule until the condition is met
+func (c *Client) waitFor(ctx context.Context, namespace, name string, condition func(*schedulerV1.ChaosSchedule) (bool, error)) (*schedulerV1.ChaosSchedule, error) {
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return c.schedules(namespace).List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return c.schedules(namespace).Watch(ctx, options)
+		},
+	}
+
+	event, err := watchtools.UntilWithSync(ctx, listWatch, &schedulerV1.ChaosSchedule{}, nil, func(event watch.Event) (bool, error) {
+		schedule, ok := event.Object.(*schedulerV1.ChaosSchedule)
+		if !ok || schedule.Name != name {
+			return false, nil
+		}
+		if event.Type == watch.Deleted {
+			return false, fmt.Errorf("the chaosschedule %s/%s has been deleted", namespace, name)
+		}
+		return condition(schedule)
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return event.Object.(*schedulerV1.ChaosSchedule), nil
+}
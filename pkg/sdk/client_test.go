@@ -0,0 +1,298 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	clienttesting "k8s.io/client-go/testing"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/client/clientset/versioned/fake"
+)
+
+// newFakeClient returns a Client on a fake clientset holding the schedules. A value is sent on the returned channel
+// whenever a watch is established, since the fake clientset drops the events sent before
+func newFakeClient(t *testing.T, schedules ...runtime.Object) (*Client, *fake.Clientset, <-chan struct{}) {
+	clientset := fake.NewSimpleClientset(schedules...)
+	watching := make(chan struct{}, 10)
+	clientset.PrependWatchReactor("chaosschedules", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		w, err := clientset.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return false, nil, err
+		}
+		watching <- struct{}{}
+		return true, w, nil
+	})
+	return New(clientset), clientset, watching
+}
+
+// newRunningSchedule returns a repeat schedule which has started a run
+func newRunningSchedule() *schedulerV1.ChaosSchedule {
+	schedule, err := newBuilder().Labels(map[string]string{"team": "sre"}).EveryMinutes(10).Build()
+	if err != nil {
+		panic(err)
+	}
+	schedule.UID = "1"
+	schedule.Spec.ScheduleState = schedulerV1.StateActive
+	schedule.Status.Schedule.Status = schedulerV1.StatusRunning
+	schedule.Status.Schedule.RunInstances = 1
+	schedule.Status.LastScheduleTime = &metav1.Time{Time: start}
+	return schedule
+}
+
+// waitUntilWatching waits until the client watches the schedule
+func waitUntilWatching(t *testing.T, watching <-chan struct{}) {
+	select {
+	case <-watching:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("the client did not watch the chaosschedule")
+	}
+}
+
+// updateStatus updates the status of the schedule, as the chaos-scheduler would
+func updateStatus(t *testing.T, clientset *fake.Clientset, mutate func(*schedulerV1.ChaosSchedule)) {
+	schedules := clientset.LitmuschaosV1alpha1().ChaosSchedules("default")
+	schedule, err := schedules.Get(context.TODO(), "schedule-nginx", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutate(schedule)
+	if _, err := schedules.Update(context.TODO(), schedule, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type waitResult struct {
+	schedule *schedulerV1.ChaosSchedule
+	err      error
+}
+
+// startWaiting calls the wait function in the background and waits until it watches the schedule
+func startWaiting(t *testing.T, watching <-chan struct{}, waitFunc func() (*schedulerV1.ChaosSchedule, error)) <-chan waitResult {
+	result := make(chan waitResult, 1)
+	go func() {
+		schedule, err := waitFunc()
+		result <- waitResult{schedule: schedule, err: err}
+	}()
+	waitUntilWatching(t, watching)
+	return result
+}
+
+func getResult(t *testing.T, result <-chan waitResult) waitResult {
+	select {
+	case r := <-result:
+		return r
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("the wait did not return")
+	}
+	return waitResult{}
+}
+
+func TestScheduleState(t *testing.T) {
+	ctx := context.Background()
+	client, clientset, _ := newFakeClient(t, newRunningSchedule())
+
+	// the first update conflicts with an update of the chaos-scheduler
+	conflicts := 1
+	clientset.PrependReactor("update", "chaosschedules", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if conflicts == 0 {
+			return false, nil, nil
+		}
+		conflicts--
+		return true, nil, k8serrors.NewConflict(schema.GroupResource{Group: "litmuschaos.io", Resource: "chaosschedules"}, "schedule-nginx", errors.New("the object has been modified"))
+	})
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context, namespace, name string) (*schedulerV1.ChaosSchedule, error)
+		want schedulerV1.ScheduleState
+	}{
+		{name: "halt", call: client.Halt, want: schedulerV1.StateHalted},
+		{name: "halt again", call: client.Halt, want: schedulerV1.StateHalted},
+		{name: "resume", call: client.Resume, want: schedulerV1.StateActive},
+		{name: "stop", call: client.Stop, want: schedulerV1.StateStopped},
+	}
+	for _, tt := range tests {
+		schedule, err := tt.call(ctx, "default", "schedule-nginx")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		stored, err := client.Get(ctx, "default", "schedule-nginx")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if schedule.Spec.ScheduleState != tt.want || stored.Spec.ScheduleState != tt.want {
+			t.Fatalf("%s: scheduleState = %s, stored %s, want %s", tt.name, schedule.Spec.ScheduleState, stored.Spec.ScheduleState, tt.want)
+		}
+	}
+
+	updateStatus(t, clientset, func(schedule *schedulerV1.ChaosSchedule) {
+		schedule.Status.Schedule.Status = schedulerV1.StatusCompleted
+	})
+	if _, err := client.Resume(ctx, "default", "schedule-nginx"); err == nil || !strings.Contains(err.Error(), "already completed") {
+		t.Fatalf("Resume() of a completed schedule error = %v", err)
+	}
+	if _, err := client.Halt(ctx, "default", "schedule-kafka"); !k8serrors.IsNotFound(err) {
+		t.Fatalf("Halt() of a missing schedule error = %v", err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	client, _, _ := newFakeClient(t)
+
+	invalid := newRunningSchedule()
+	invalid.Spec.Schedule.Now = true
+	if _, err := client.Create(context.TODO(), invalid); err == nil {
+		t.Fatal("Create() of an invalid schedule should fail")
+	}
+	if _, err := client.Create(context.TODO(), newRunningSchedule()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTriggerNow(t *testing.T) {
+	schedule := newRunningSchedule()
+	schedule.Spec.MutexGroup = &schedulerV1.MutexGroup{Name: "storage"}
+	schedule.Spec.TargetRotation = &schedulerV1.TargetRotation{
+		Strategy:   schedulerV1.RoundRobinRotation,
+		Candidates: []schedulerV1.RotationTarget{{Appns: "default", Applabel: "app=nginx", AppKind: "deployment"}},
+	}
+	client, _, _ := newFakeClient(t, schedule)
+
+	run, err := client.TriggerNow(context.TODO(), "default", "schedule-nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := client.Get(context.TODO(), "default", run.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored.Name, "schedule-nginx-manual-") {
+		t.Fatalf("name = %s", stored.Name)
+	}
+	if !stored.Spec.Schedule.Now || stored.Spec.Schedule.Repeat != nil || stored.Spec.ScheduleState != schedulerV1.StateActive {
+		t.Fatalf("schedule = %+v, state %s", stored.Spec.Schedule, stored.Spec.ScheduleState)
+	}
+	if stored.Labels[TriggeredByLabel] != "schedule-nginx" || stored.Labels["team"] != "sre" {
+		t.Fatalf("labels = %v", stored.Labels)
+	}
+	if owner := metav1.GetControllerOf(stored); owner == nil || owner.Name != "schedule-nginx" || owner.Kind != "ChaosSchedule" {
+		t.Fatalf("owner = %+v", owner)
+	}
+	if stored.Spec.EngineTemplateSpec.Experiments[0].Name != "pod-delete" || stored.Status.Schedule.RunInstances != 0 {
+		t.Fatalf("the engine or the status is not copied as expected: %+v", stored)
+	}
+	// the rotation of the schedule is not restarted by the run
+	if stored.Spec.TargetRotation != nil || stored.Spec.MutexGroup == nil || stored.Spec.MutexGroup.Name != "storage" {
+		t.Fatalf("targetRotation = %+v, mutexGroup = %+v", stored.Spec.TargetRotation, stored.Spec.MutexGroup)
+	}
+}
+
+func TestWaitForNextRun(t *testing.T) {
+	ctx := context.Background()
+	client, clientset, watching := newFakeClient(t, newRunningSchedule())
+
+	result := startWaiting(t, watching, func() (*schedulerV1.ChaosSchedule, error) {
+		return client.WaitForNextRun(ctx, "default", "schedule-nginx")
+	})
+	// the update of the active engines is not a new run
+	updateStatus(t, clientset, func(schedule *schedulerV1.ChaosSchedule) {
+		schedule.Status.Active = nil
+	})
+	updateStatus(t, clientset, func(schedule *schedulerV1.ChaosSchedule) {
+		schedule.Status.Schedule.RunInstances = 2
+		schedule.Status.LastScheduleTime = &metav1.Time{Time: start.Add(10 * time.Minute)}
+	})
+	r := getResult(t, result)
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if r.schedule.Status.Schedule.RunInstances != 2 {
+		t.Fatalf("WaitForNextRun() returned the run %d", r.schedule.Status.Schedule.RunInstances)
+	}
+
+	result = startWaiting(t, watching, func() (*schedulerV1.ChaosSchedule, error) {
+		return client.WaitForNextRun(ctx, "default", "schedule-nginx")
+	})
+	updateStatus(t, clientset, func(schedule *schedulerV1.ChaosSchedule) {
+		schedule.Status.Schedule.Status = schedulerV1.StatusCompleted
+	})
+	if r := getResult(t, result); r.err == nil || !strings.Contains(r.err.Error(), "is completed") {
+		t.Fatalf("WaitForNextRun() of a completed schedule error = %v", r.err)
+	}
+}
+
+func TestWaitForCompletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  func(*testing.T, *fake.Clientset)
+		wantErr string
+	}{
+		{
+			name: "completed",
+			update: func(t *testing.T, clientset *fake.Clientset) {
+				updateStatus(t, clientset, func(schedule *schedulerV1.ChaosSchedule) {
+					schedule.Status.Schedule.Status = schedulerV1.StatusCompleted
+				})
+			},
+		},
+		{
+			name: "stopped",
+			update: func(t *testing.T, clientset *fake.Clientset) {
+				updateStatus(t, clientset, func(schedule *schedulerV1.ChaosSchedule) {
+					schedule.Status.Schedule.Status = schedulerV1.StatusStopped
+				})
+			},
+			wantErr: "is stopped",
+		},
+		{
+			name: "deleted",
+			update: func(t *testing.T, clientset *fake.Clientset) {
+				err := clientset.LitmuschaosV1alpha1().ChaosSchedules("default").Delete(context.TODO(), "schedule-nginx", metav1.DeleteOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: "has been deleted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, clientset, watching := newFakeClient(t, newRunningSchedule())
+			result := startWaiting(t, watching, func() (*schedulerV1.ChaosSchedule, error) {
+				return client.WaitForCompletion(context.Background(), "default", "schedule-nginx")
+			})
+			tt.update(t, clientset)
+
+			r := getResult(t, result)
+			switch {
+			case tt.wantErr == "" && r.err != nil:
+				t.Fatalf("WaitForCompletion() error = %v", r.err)
+			case tt.wantErr == "" && r.schedule.Status.Schedule.Status != schedulerV1.StatusCompleted:
+				t.Fatalf("WaitForCompletion() returned the status %s", r.schedule.Status.Schedule.Status)
+			case tt.wantErr != "" && (r.err == nil || !strings.Contains(r.err.Error(), tt.wantErr)):
+				t.Fatalf("WaitForCompletion() error = %v, want an error containing %q", r.err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForCompletionContext(t *testing.T) {
+	client, _, _ := newFakeClient(t, newRunningSchedule())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.WaitForCompletion(ctx, "default", "schedule-nginx"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForCompletion() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
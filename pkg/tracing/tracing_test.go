@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		paths = append(paths, req.URL.Path)
+	}))
+	defer server.Close()
+
+	provider, shutdown, err := NewTracerProvider(server.URL + "/collector/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := provider.Tracer("test").Start(context.TODO(), "Reconcile")
+	if !span.SpanContext().IsValid() || !span.IsRecording() {
+		t.Fatal("span is not recorded with an endpoint")
+	}
+	span.End()
+
+	// the remaining spans are flushed on shutdown
+	if err := shutdown(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "/collector/v1/traces" {
+		t.Fatalf("spans posted to %v, want a single request to /collector/v1/traces", paths)
+	}
+}
+
+func TestNoopTracerProvider(t *testing.T) {
+	provider, shutdown, err := NewTracerProvider("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := provider.Tracer("test").Start(context.TODO(), "Reconcile")
+	if span.SpanContext().IsValid() || span.IsRecording() {
+		t.Fatal("span is recorded without any endpoint")
+	}
+	if err := shutdown(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2019 LitmusChaos Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation checks the ChaosSchedules beyond the schema of the CRD. It is shared by the controller, which
+// refuses to schedule an invalid chaosschedule, and by the SDK, which refuses to create one
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/teambition/rrule-go"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-scheduler/pkg/types"
+)
+
+// ValidateChaosSchedule returns the errors of the spec of the chaosschedule
+func ValidateChaosSchedule(cs *schedulerV1.ChaosSchedule) field.ErrorList {
+	spec := &cs.Spec
+	specPath := field.NewPath("spec")
+
+	allErrs := validateSchedule(&spec.Schedule, specPath.Child("schedule"))
+
+	switch spec.ScheduleState {
+	case "", schedulerV1.StateActive, schedulerV1.StateHalted, schedulerV1.StateStopped, schedulerV1.StateCompleted:
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("scheduleState"), spec.ScheduleState,
+			[]string{string(schedulerV1.StateActive), string(schedulerV1.StateHalted), string(schedulerV1.StateStopped), string(schedulerV1.StateCompleted)}))
+	}
+
+	switch spec.ConcurrencyPolicy {
+	case "", schedulerV1.AllowConcurrent, schedulerV1.ForbidConcurrent, schedulerV1.ReplaceConcurrent:
+	default:
+		allErrs = append(allErrs, field.NotSupported(specPath.Child("concurrencyPolicy"), spec.ConcurrencyPolicy,
+			[]string{string(schedulerV1.AllowConcurrent), string(schedulerV1.ForbidConcurrent), string(schedulerV1.ReplaceConcurrent)}))
+	}
+
+	if spec.HistoryLimit != nil && *spec.HistoryLimit < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("historyLimit"), *spec.HistoryLimit, "should not be negative"))
+	}
+
+	if len(spec.EngineTemplates) == 0 && len(spec.EngineTemplateSpec.Experiments) == 0 {
+		allErrs = append(allErrs, field.Required(specPath.Child("engineTemplateSpec", "experiments"), "at least one experiment should be listed"))
+	}
+
+	if group := spec.MutexGroup; group != nil {
+		if group.Name == "" {
+			allErrs = append(allErrs, field.Required(specPath.Child("mutexGroup", "name"), ""))
+		}
+		switch group.Policy {
+		case "", schedulerV1.DeferRun, schedulerV1.SkipRun:
+		default:
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("mutexGroup", "policy"), group.Policy,
+				[]string{string(schedulerV1.DeferRun), string(schedulerV1.SkipRun)}))
+		}
+	}
+
+	if hooks := spec.Hooks; hooks != nil {
+		if hooks.PreRun != nil {
+			allErrs = append(allErrs, validateHook(hooks.PreRun, specPath.Child("hooks", "preRun"),
+				schedulerV1.ContinueOnHookFailure, schedulerV1.SkipOnHookFailure, schedulerV1.AbortOnHookFailure)...)
+		}
+		if hooks.PostRun != nil {
+			allErrs = append(allErrs, validateHook(hooks.PostRun, specPath.Child("hooks", "postRun"),
+				schedulerV1.ContinueOnHookFailure, schedulerV1.AbortOnHookFailure)...)
+		}
+	}
+
+	names := map[string]bool{}
+	for i, sink := range spec.Notifications {
+		path := specPath.Child("notifications").Index(i)
+		switch {
+		case sink.Name == "":
+			allErrs = append(allErrs, field.Required(path.Child("name"), ""))
+		case names[sink.Name]:
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), sink.Name))
+		}
+		names[sink.Name] = true
+		allErrs = append(allErrs, validateNotificationSink(&sink, path)...)
+	}
+	return allErrs
+}
+
+// validateNotificationSink checks that the sink has a supported type and exactly one of the url and the urlSecretRef
+func validateNotificationSink(sink *schedulerV1.NotificationSink, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	switch sink.Type {
+	case schedulerV1.WebhookSink, schedulerV1.SlackSink, schedulerV1.CloudEventsSink:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("type"), sink.Type,
+			[]string{string(schedulerV1.WebhookSink), string(schedulerV1.SlackSink), string(schedulerV1.CloudEventsSink)}))
+	}
+
+	switch {
+	case sink.URL == "" && sink.URLSecretRef == nil:
+		allErrs = append(allErrs, field.Required(path, "one of 'url' or 'urlSecretRef' should be given"))
+	case sink.URL != "" && sink.URLSecretRef != nil:
+		allErrs = append(allErrs, field.Forbidden(path, "only one of 'url' or 'urlSecretRef' should be given"))
+	case sink.URL != "":
+		if u, err := url.Parse(sink.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("url"), sink.URL, "should be an absolute http or https url"))
+		}
+	}
+
+	if sink.HMACSecretRef != nil && sink.Type != schedulerV1.WebhookSink {
+		allErrs = append(allErrs, field.Forbidden(path.Child("hmacSecretRef"), "only supported by the webhook sinks"))
+	}
+	return allErrs
+}
+
+// validateHook checks that exactly one of the job and the webhook is given, and that the failure policy is one of
+// the supported ones
+func validateHook(hook *schedulerV1.RunHook, path *field.Path, policies ...schedulerV1.HookFailurePolicy) field.ErrorList {
+	var allErrs field.ErrorList
+
+	switch {
+	case hook.Job == nil && hook.Webhook == nil:
+		allErrs = append(allErrs, field.Required(path, "one of 'job' or 'webhook' should be given"))
+	case hook.Job != nil && hook.Webhook != nil:
+		allErrs = append(allErrs, field.Forbidden(path, "only one of 'job' or 'webhook' should be given"))
+	case hook.Job != nil && len(hook.Job.Spec.Template.Spec.Containers) == 0:
+		allErrs = append(allErrs, field.Required(path.Child("job", "spec", "template", "spec", "containers"), ""))
+	case hook.Webhook != nil:
+		if u, err := url.Parse(hook.Webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("webhook", "url"), hook.Webhook.URL, "should be an absolute http or https url"))
+		}
+	}
+
+	if hook.Timeout != nil && hook.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("timeout"), hook.Timeout.Duration.String(), "should be positive"))
+	}
+
+	supported := []string{}
+	for _, policy := range policies {
+		if hook.FailurePolicy == policy {
+			return allErrs
+		}
+		supported = append(supported, string(policy))
+	}
+	if hook.FailurePolicy != "" {
+		allErrs = append(allErrs, field.NotSupported(path.Child("failurePolicy"), hook.FailurePolicy, supported))
+	}
+	return allErrs
+}
+
+// validateSchedule checks that exactly one type of schedule is given, along with its properties
+func validateSchedule(schedule *schedulerV1.Schedule, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	var given []string
+	if schedule.Now {
+		given = append(given, "now")
+	}
+	if schedule.Once != nil {
+		given = append(given, "once")
+		if schedule.Once.ExecutionTime.IsZero() {
+			allErrs = append(allErrs, field.Required(path.Child("once", "executionTime"), ""))
+		}
+	}
+	if schedule.Repeat != nil {
+		given = append(given, "repeat")
+		allErrs = append(allErrs, validateRepeat(schedule.Repeat, path.Child("repeat"))...)
+	}
+	if schedule.RRule != nil {
+		given = append(given, "rrule")
+		allErrs = append(allErrs, validateRRule(schedule.RRule, path.Child("rrule"))...)
+	}
+
+	switch len(given) {
+	case 0:
+		allErrs = append(allErrs, field.Required(path, "one of 'now', 'once', 'repeat' or 'rrule' should be given"))
+	case 1:
+	default:
+		allErrs = append(allErrs, field.Forbidden(path, fmt.Sprintf("only one of 'now', 'once', 'repeat' or 'rrule' should be given, found %s", strings.Join(given, ", "))))
+	}
+	return allErrs
+}
+
+func validateRepeat(repeat *schedulerV1.ScheduleRepeat, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	intervalPath := path.Child("properties", "minChaosInterval")
+	interval := repeat.Properties.MinChaosInterval
+	switch {
+	case interval == nil || (interval.Hour == nil && interval.Minute == nil):
+		allErrs = append(allErrs, field.Required(intervalPath, "one of 'hour' or 'minute' should be given"))
+	case interval.Hour != nil && interval.Minute != nil:
+		allErrs = append(allErrs, field.Forbidden(intervalPath, "only one of 'hour' or 'minute' should be given"))
+	case interval.Minute != nil:
+		if interval.Minute.EveryNthMinute < 1 {
+			allErrs = append(allErrs, field.Invalid(intervalPath.Child("minute", "everyNthMinute"), interval.Minute.EveryNthMinute, "should be at least 1"))
+		}
+	default:
+		if interval.Hour.EveryNthHour < 1 {
+			allErrs = append(allErrs, field.Invalid(intervalPath.Child("hour", "everyNthHour"), interval.Hour.EveryNthHour, "should be at least 1"))
+		}
+		if interval.Hour.MinuteOfTheHour < 0 || interval.Hour.MinuteOfTheHour > 59 {
+			allErrs = append(allErrs, field.Invalid(intervalPath.Child("hour", "minuteOfTheHour"), interval.Hour.MinuteOfTheHour, "should be between 0 and 59"))
+		}
+	}
+
+	if repeat.WorkHours != nil {
+		if err := validateList(repeat.WorkHours.IncludedHours, 23, ParseRange); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("workHours", "includedHours"), repeat.WorkHours.IncludedHours, err.Error()))
+		}
+	}
+	if repeat.WorkDays != nil {
+		if err := validateList(repeat.WorkDays.IncludedDays, 6, ParseWeekdays); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("workDays", "includedDays"), repeat.WorkDays.IncludedDays, err.Error()))
+		}
+	}
+	return append(allErrs, validateTimeRange(repeat.TimeRange, path.Child("timeRange"))...)
+}
+
+func validateRRule(rule *schedulerV1.ScheduleRRule, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	option, err := rrule.StrToROption(rule.Rule)
+	if err == nil {
+		_, err = rrule.NewRRule(*option)
+	}
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("rule"), rule.Rule, err.Error()))
+	}
+	return append(allErrs, validateTimeRange(rule.TimeRange, path.Child("timeRange"))...)
+}
+
+func validateTimeRange(timeRange *schedulerV1.TimeRange, path *field.Path) field.ErrorList {
+	if timeRange == nil || timeRange.StartTime == nil || timeRange.EndTime == nil {
+		return nil
+	}
+	if !timeRange.EndTime.After(timeRange.StartTime.Time) {
+		return field.ErrorList{field.Invalid(path.Child("endTime"), timeRange.EndTime, "should be after the startTime")}
+	}
+	return nil
+}
+
+// validateList checks the comma separated list of values or ranges, all of them should be between 0 and the max
+func validateList(list string, max int, parse func(string) (int, int, error)) error {
+	for _, item := range strings.Split(list, ",") {
+		start, end, err := parse(item)
+		if err != nil {
+			return err
+		}
+		if start < 0 || end > max || start > end {
+			return fmt.Errorf("%q should be a value or a range between 0 and %d", strings.TrimSpace(item), max)
+		}
+	}
+	return nil
+}
+
+// ParseWeekdays parses a single day or range of days, given as names such as "Mon-Wed" or numbers such as "1-3"
+// where 0 represents Sunday and 6 represents Saturday
+func ParseWeekdays(data string) (int, int, error) {
+	if start, end, err := ParseRange(data); err == nil {
+		return start, end, nil
+	}
+	fieldRange := strings.Split(data, "-")
+	if len(fieldRange) > 2 {
+		return 0, 0, fmt.Errorf("provided the correct input range, range: %v", data)
+	}
+	start, err := parseWeekday(fieldRange[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseWeekday(fieldRange[len(fieldRange)-1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+func parseWeekday(data string) (int, error) {
+	day, ok := types.WeekDays[strings.ToLower(strings.TrimSpace(data))]
+	if !ok {
+		return 0, fmt.Errorf("unknown day of the week: %q", strings.TrimSpace(data))
+	}
+	return day, nil
+}
+
+// ParseRange parses a single value or range of values given in int format, such as "9" or "9-17"
+func ParseRange(data string) (int, int, error) {
+	var start, end string
+	if strings.Contains(data, "-") {
+		fieldRange := strings.Split(data, "-")
+		if len(fieldRange) != 2 {
+			return 0, 0, fmt.Errorf("provided the correct input range, range: %v", data)
+		}
+		start = strings.TrimSpace(fieldRange[0])
+		end = strings.TrimSpace(fieldRange[1])
+	} else {
+		start = strings.TrimSpace(data)
+		end = start
+	}
+	startInt, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	endInt, err := strconv.Atoi(end)
+	if err != nil {
+		return 0, 0, err
+	}
+	return startInt, endInt, nil
+}
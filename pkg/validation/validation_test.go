@@ -0,0 +1,313 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	operatorV1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedulerV1 "github.com/litmuschaos/chaos-scheduler/api/litmuschaos/v1alpha1"
+)
+
+// newSchedule returns a valid schedule repeated every 10 minutes
+func newSchedule() *schedulerV1.ChaosSchedule {
+	return &schedulerV1.ChaosSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "default"},
+		Spec: schedulerV1.ChaosScheduleSpec{
+			Schedule: schedulerV1.Schedule{Repeat: &schedulerV1.ScheduleRepeat{
+				Properties: schedulerV1.ScheduleRepeatProperties{
+					MinChaosInterval: &schedulerV1.MinChaosInterval{Minute: &schedulerV1.Minute{EveryNthMinute: 10}},
+				},
+			}},
+			EngineTemplateSpec: operatorV1.ChaosEngineSpec{
+				Experiments: []operatorV1.ExperimentList{{Name: "pod-delete"}},
+			},
+		},
+	}
+}
+
+func TestValidateChaosSchedule(t *testing.T) {
+	start := metav1.NewTime(time.Date(2021, time.October, 6, 9, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(time.Hour))
+	limit := int32(-1)
+
+	tests := []struct {
+		name    string
+		mutate  func(*schedulerV1.ChaosSchedule)
+		wantErr string
+	}{
+		{
+			name:   "valid repeat",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {},
+		},
+		{
+			name: "valid work hours and days",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule.Repeat.WorkHours = &schedulerV1.WorkHours{IncludedHours: "0-8, 17-23"}
+				cs.Spec.Schedule.Repeat.WorkDays = &schedulerV1.WorkDays{IncludedDays: "Mon-Wed,5,sat"}
+				cs.Spec.Schedule.Repeat.TimeRange = &schedulerV1.TimeRange{StartTime: &start, EndTime: &end}
+			},
+		},
+		{
+			name: "valid rrule with engineTemplates",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule = schedulerV1.Schedule{RRule: &schedulerV1.ScheduleRRule{Rule: "FREQ=WEEKLY;BYDAY=TU,TH"}}
+				cs.Spec.EngineTemplateSpec = operatorV1.ChaosEngineSpec{}
+				cs.Spec.EngineTemplates = []schedulerV1.EngineTemplate{{Name: "pod-delete"}}
+			},
+		},
+		{
+			name:    "no schedule",
+			mutate:  func(cs *schedulerV1.ChaosSchedule) { cs.Spec.Schedule = schedulerV1.Schedule{} },
+			wantErr: "spec.schedule: Required value",
+		},
+		{
+			name:    "several schedules",
+			mutate:  func(cs *schedulerV1.ChaosSchedule) { cs.Spec.Schedule.Now = true },
+			wantErr: "found now, repeat",
+		},
+		{
+			name: "once without execution time",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule = schedulerV1.Schedule{Once: &schedulerV1.ScheduleOnce{}}
+			},
+			wantErr: "spec.schedule.once.executionTime: Required value",
+		},
+		{
+			name:    "no interval",
+			mutate:  func(cs *schedulerV1.ChaosSchedule) { cs.Spec.Schedule.Repeat.Properties.MinChaosInterval = nil },
+			wantErr: "spec.schedule.repeat.properties.minChaosInterval: Required value",
+		},
+		{
+			name: "hour and minute intervals",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule.Repeat.Properties.MinChaosInterval.Hour = &schedulerV1.Hour{EveryNthHour: 1}
+			},
+			wantErr: "only one of 'hour' or 'minute'",
+		},
+		{
+			name: "zero minutes",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule.Repeat.Properties.MinChaosInterval.Minute.EveryNthMinute = 0
+			},
+			wantErr: "everyNthMinute: Invalid value: 0",
+		},
+		{
+			name: "minute of the hour out of range",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule.Repeat.Properties.MinChaosInterval = &schedulerV1.MinChaosInterval{Hour: &schedulerV1.Hour{EveryNthHour: 2, MinuteOfTheHour: 60}}
+			},
+			wantErr: "minuteOfTheHour: Invalid value: 60",
+		},
+		{
+			name: "hour out of range",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule.Repeat.WorkHours = &schedulerV1.WorkHours{IncludedHours: "9-24"}
+			},
+			wantErr: `"9-24" should be a value or a range between 0 and 23`,
+		},
+		{
+			name: "reversed range of hours",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule.Repeat.WorkHours = &schedulerV1.WorkHours{IncludedHours: "17-9"}
+			},
+			wantErr: "spec.schedule.repeat.workHours.includedHours",
+		},
+		{
+			name: "unknown day",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule.Repeat.WorkDays = &schedulerV1.WorkDays{IncludedDays: "Mon,Funday"}
+			},
+			wantErr: `unknown day of the week: "Funday"`,
+		},
+		{
+			name: "time range ending before its start",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule.Repeat.TimeRange = &schedulerV1.TimeRange{StartTime: &end, EndTime: &start}
+			},
+			wantErr: "spec.schedule.repeat.timeRange.endTime",
+		},
+		{
+			name: "invalid rrule",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Schedule = schedulerV1.Schedule{RRule: &schedulerV1.ScheduleRRule{Rule: "FREQ=SOMETIMES"}}
+			},
+			wantErr: "spec.schedule.rrule.rule",
+		},
+		{
+			name:    "unknown scheduleState",
+			mutate:  func(cs *schedulerV1.ChaosSchedule) { cs.Spec.ScheduleState = "paused" },
+			wantErr: "spec.scheduleState: Unsupported value",
+		},
+		{
+			name:    "unknown concurrencyPolicy",
+			mutate:  func(cs *schedulerV1.ChaosSchedule) { cs.Spec.ConcurrencyPolicy = "allow" },
+			wantErr: "spec.concurrencyPolicy: Unsupported value",
+		},
+		{
+			name:    "negative historyLimit",
+			mutate:  func(cs *schedulerV1.ChaosSchedule) { cs.Spec.HistoryLimit = &limit },
+			wantErr: "spec.historyLimit",
+		},
+		{
+			name:    "no experiment",
+			mutate:  func(cs *schedulerV1.ChaosSchedule) { cs.Spec.EngineTemplateSpec.Experiments = nil },
+			wantErr: "spec.engineTemplateSpec.experiments: Required value",
+		},
+		{
+			name: "mutex group without name",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.MutexGroup = &schedulerV1.MutexGroup{Policy: schedulerV1.SkipRun}
+			},
+			wantErr: "spec.mutexGroup.name: Required value",
+		},
+		{
+			name: "valid hooks",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Hooks = &schedulerV1.RunHooks{
+					PreRun: &schedulerV1.RunHook{
+						Job:           &batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "snapshot"}}}}}},
+						FailurePolicy: schedulerV1.SkipOnHookFailure,
+					},
+					PostRun: &schedulerV1.RunHook{Webhook: &schedulerV1.WebhookHook{URL: "https://hooks.example.com/chaos"}},
+				}
+			},
+		},
+		{
+			name: "hook without job or webhook",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Hooks = &schedulerV1.RunHooks{PreRun: &schedulerV1.RunHook{}}
+			},
+			wantErr: "spec.hooks.preRun: Required value",
+		},
+		{
+			name: "hook with relative webhook url",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Hooks = &schedulerV1.RunHooks{PreRun: &schedulerV1.RunHook{Webhook: &schedulerV1.WebhookHook{URL: "/chaos"}}}
+			},
+			wantErr: "spec.hooks.preRun.webhook.url",
+		},
+		{
+			name: "postRun hook skipping the run",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Hooks = &schedulerV1.RunHooks{PostRun: &schedulerV1.RunHook{
+					Webhook:       &schedulerV1.WebhookHook{URL: "https://hooks.example.com/chaos"},
+					FailurePolicy: schedulerV1.SkipOnHookFailure,
+				}}
+			},
+			wantErr: "spec.hooks.postRun.failurePolicy: Unsupported value",
+		},
+		{
+			name: "valid notifications",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Notifications = []schedulerV1.NotificationSink{
+					{Name: "audit", Type: schedulerV1.WebhookSink, URL: "https://audit.example.com", HMACSecretRef: &corev1.SecretKeySelector{Key: "key"}},
+					{Name: "oncall", Type: schedulerV1.SlackSink, URLSecretRef: &corev1.SecretKeySelector{Key: "url"}, Events: []string{"MissEngine"}},
+				}
+			},
+		},
+		{
+			name: "duplicate notification sink",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Notifications = []schedulerV1.NotificationSink{
+					{Name: "audit", Type: schedulerV1.WebhookSink, URL: "https://audit.example.com"},
+					{Name: "audit", Type: schedulerV1.CloudEventsSink, URL: "https://broker.example.com"},
+				}
+			},
+			wantErr: "spec.notifications[1].name: Duplicate value",
+		},
+		{
+			name: "notification sink without url",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Notifications = []schedulerV1.NotificationSink{{Name: "oncall", Type: schedulerV1.SlackSink}}
+			},
+			wantErr: "spec.notifications[0]: Required value",
+		},
+		{
+			name: "signed slack sink",
+			mutate: func(cs *schedulerV1.ChaosSchedule) {
+				cs.Spec.Notifications = []schedulerV1.NotificationSink{
+					{Name: "oncall", Type: schedulerV1.SlackSink, URL: "https://hooks.slack.com/services/T0", HMACSecretRef: &corev1.SecretKeySelector{Key: "key"}},
+				}
+			},
+			wantErr: "spec.notifications[0].hmacSecretRef: Forbidden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := newSchedule()
+			tt.mutate(cs)
+			err := ValidateChaosSchedule(cs).ToAggregate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("ValidateChaosSchedule() = %v, want no error", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("ValidateChaosSchedule() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseWeekdays(t *testing.T) {
+	tests := []struct {
+		data      string
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{data: "Mon-Fri", wantStart: 1, wantEnd: 5},
+		{data: "mon - wed", wantStart: 1, wantEnd: 3},
+		{data: "2-4", wantStart: 2, wantEnd: 4},
+		{data: "Sat", wantStart: 6, wantEnd: 6},
+		{data: "SUN", wantStart: 0, wantEnd: 0},
+		{data: "3", wantStart: 3, wantEnd: 3},
+		{data: "x", wantErr: true},
+		{data: "1-x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			start, end, err := ParseWeekdays(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseWeekdays() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (start != tt.wantStart || end != tt.wantEnd) {
+				t.Fatalf("ParseWeekdays() = (%d, %d), want (%d, %d)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		data      string
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{data: "5", wantStart: 5, wantEnd: 5},
+		{data: "9-17", wantStart: 9, wantEnd: 17},
+		{data: " 9 - 17 ", wantStart: 9, wantEnd: 17},
+		{data: "", wantErr: true},
+		{data: "a", wantErr: true},
+		{data: "1-b", wantErr: true},
+		{data: "a-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			start, end, err := ParseRange(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (start != tt.wantStart || end != tt.wantEnd) {
+				t.Fatalf("ParseRange() = (%d, %d), want (%d, %d)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}